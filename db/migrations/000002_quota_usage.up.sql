@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS quota_usage (
+    tenant_id INTEGER NOT NULL,               -- Foreign key to tenants table
+    period TEXT NOT NULL,                     -- Billing period the usage applies to (YYYY-MM)
+    request_count INTEGER NOT NULL DEFAULT 0 CHECK (request_count >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,   -- Timestamp of the last recorded request
+    PRIMARY KEY (tenant_id, period),
+    CONSTRAINT fk_tenant
+        FOREIGN KEY (tenant_id)
+        REFERENCES tenants (id)
+        ON DELETE CASCADE
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor TEXT NOT NULL DEFAULT '',           -- User that made the change, empty if unknown
+    table_name TEXT NOT NULL,                 -- Table of the changed record
+    record_id INTEGER NOT NULL,               -- ID of the changed record
+    operation TEXT NOT NULL CHECK (operation IN ('insert', 'update', 'delete')),
+    changes TEXT NOT NULL DEFAULT '{}',       -- JSON object of changed fields to their old and new values
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_record ON audit_log (table_name, record_id);
@@ -9,6 +9,8 @@ import (
 	"html/template"
 	"log/slog"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,24 +34,114 @@ type Controller interface {
 }
 
 type TenantController struct {
-	DB              *sql.DB
+	DB *sql.DB
+	// OpenAPI, if set, documents the tenant endpoints as they are registered.
+	OpenAPI         *httputils.OpenAPI
 	htmlTemplateMap map[string]*template.Template
+	idempotent      func(next http.Handler) http.Handler
+	quota           func(next http.Handler) http.Handler
+	encryptionKey   []byte
 }
 
-func NewTenantController(db *sql.DB, htmlTemplateMap map[string]*template.Template) *TenantController {
-	return &TenantController{DB: db, htmlTemplateMap: htmlTemplateMap}
+func NewTenantController(
+	db *sql.DB, htmlTemplateMap map[string]*template.Template, encryptionKey []byte,
+) *TenantController {
+	c := &TenantController{
+		DB:              db,
+		htmlTemplateMap: htmlTemplateMap,
+		encryptionKey:   encryptionKey,
+		// Clients may retry tenant creation with the same Idempotency-Key without creating duplicates.
+		// Keys are scoped by the authenticated user so that users cannot replay each other's responses.
+		idempotent: httputils.IdempotencyMiddleware(httputils.IdempotencyConfig{ //nolint: exhaustruct
+			Store: httputils.NewMemoryIdempotencyStore(),
+			Scope: func(r *http.Request) string {
+				return strconv.FormatInt(authutils.ContextGetUser[User](r).ID, 10)
+			},
+		}),
+	}
+	c.quota = httputils.QuotaMiddleware(httputils.QuotaConfig{ //nolint: exhaustruct
+		Store:         dbutils.NewQuotaStore(db),
+		MonthlyLimits: MonthlyRequestQuotas,
+		RateLimits:    NewPlanRateLimits(),
+		Tenant:        c.quotaTenant,
+	})
+
+	return c
+}
+
+// quotaTenant bills requests against the signed in user's tenant and its current plan. Requests without a
+// user, or whose tenant cannot be loaded, are not counted.
+func (c *TenantController) quotaTenant(r *http.Request) (int64, string, bool) {
+	user, ok := httputils.ContextGetUser[User](r)
+	if !ok {
+		return 0, "", false
+	}
+
+	tenant, err := GetTenantById(c.DB, user.TenantID)
+	if err != nil {
+		return 0, "", false
+	}
+
+	return tenant.ID, string(tenant.Plan), true
 }
 
 func (c *TenantController) PublicRoutes(_ httputils.Router) {
 
 }
 
+type validationErrorsResponse struct {
+	Errors []validation.Error `json:"errors"`
+}
+
+type tenantIDResponse struct {
+	ID int64 `json:"id"`
+}
+
 func (c *TenantController) ProtectedRoutes(router httputils.Router) {
-	router.Post("/tenants", c.CreateTenantHandler)
-	router.Get("/tenants/{id}", c.GetTenantHandler)
-	router.Get("/tenants", c.SearchTenantsHandler)
-	router.Patch("/tenants/{id}", c.UpdateTenantHandler)
-	router.Delete("/tenants/{id}", c.DeleteTenantHandler)
+	tags := []string{tenantResourceKey}
+	tenants := httputils.With(router, c.quota)
+
+	httputils.Describe(httputils.With(tenants, c.idempotent), c.OpenAPI, httputils.OpenAPIOperation{
+		Summary: "Create a tenant",
+		Tags:    tags,
+		Request: CreateTenantRequest{},
+		Responses: map[int]any{
+			http.StatusCreated:    tenantIDResponse{},
+			http.StatusBadRequest: validationErrorsResponse{},
+		},
+	}).Post("/tenants", c.CreateTenantHandler)
+	httputils.Describe(tenants, c.OpenAPI, httputils.OpenAPIOperation{
+		Summary:   "Create tenants in a batch",
+		Tags:      tags,
+		Request:   BatchCreateTenantsRequest{},
+		Responses: map[int]any{http.StatusOK: nil, http.StatusMultiStatus: nil, http.StatusBadRequest: nil},
+	}).Post("/tenants/batch", c.BatchCreateTenantsHandler)
+	httputils.Describe(tenants, c.OpenAPI, httputils.OpenAPIOperation{
+		Summary:   "Get a tenant",
+		Tags:      tags,
+		Responses: map[int]any{http.StatusOK: GetTenantResponse{}, http.StatusNotFound: nil},
+	}).Get("/tenants/{id}", c.GetTenantHandler)
+	httputils.Describe(tenants, c.OpenAPI, httputils.OpenAPIOperation{
+		Summary:   "Search tenants",
+		Tags:      tags,
+		Query:     SearchTenantsRequest{},
+		Responses: map[int]any{http.StatusOK: httputils.Page[SearchTenantResponse]{}},
+	}).Get("/tenants", c.SearchTenantsHandler)
+	httputils.Describe(tenants, c.OpenAPI, httputils.OpenAPIOperation{
+		Summary: "Update a tenant",
+		Tags:    tags,
+		Request: UpdateTenantRequest{},
+		Responses: map[int]any{
+			http.StatusOK:         GetTenantResponse{},
+			http.StatusBadRequest: validationErrorsResponse{},
+			http.StatusNotFound:   nil,
+		},
+	}).Patch("/tenants/{id}", c.UpdateTenantHandler)
+	httputils.Describe(tenants, c.OpenAPI, httputils.OpenAPIOperation{
+		Summary:   "Delete a tenant",
+		Tags:      tags,
+		Responses: map[int]any{http.StatusOK: nil, http.StatusNotFound: nil},
+	}).Delete("/tenants/{id}", c.DeleteTenantHandler)
 	router.Post("/api/invite", c.InviteUser)
 	router.Get("/", c.Dashboard)
 }
@@ -62,7 +154,7 @@ type InviteUserRequest struct {
 func (c *TenantController) InviteUser(w http.ResponseWriter, r *http.Request) {
 	inviteUserRequest, err := httputils.ReadJSON[InviteUserRequest](w, r)
 	if err != nil {
-		httputils.BadRequestResponse(w, r, err)
+		httputils.HandleErrorResponse(w, r, err)
 
 		return
 	}
@@ -74,7 +166,7 @@ func (c *TenantController) InviteUser(w http.ResponseWriter, r *http.Request) {
 		"email":     inviteUserRequest.Email,
 	}
 
-	inviteToken, err := authutils.CreateInviteToken(payload)
+	inviteToken, err := authutils.CreateInviteToken(c.encryptionKey, payload)
 
 	if err != nil {
 		httputils.ServerErrorResponse(w, r, err)
@@ -82,7 +174,7 @@ func (c *TenantController) InviteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httputils.WriteJSON(w, http.StatusOK, map[string]string{"token": inviteToken}, nil)
+	httputils.RespondJSON(w, r, http.StatusOK, map[string]string{"token": inviteToken}, nil)
 }
 
 func (c *TenantController) Dashboard(w http.ResponseWriter, r *http.Request) {
@@ -99,13 +191,54 @@ const (
 	Paid TenantPlan = "paid"
 )
 
-func IsValidTenantPlan(plan TenantPlan) bool {
-	switch plan {
+// ErrInvalidPlan is returned when a plan is not one of the supported tenant plans.
+var ErrInvalidPlan = validation.Error{
+	Field:   planRequestKey,
+	Message: "Invalid plan",
+}
+
+// ParsePlan parses a tenant plan, ignoring case and surrounding whitespace. It returns ErrInvalidPlan if
+// the plan is not supported.
+func ParsePlan(plan string) (TenantPlan, error) {
+	switch parsed := TenantPlan(strings.ToLower(strings.TrimSpace(plan))); parsed {
 	case Free, Paid:
-		return true
+		return parsed, nil
 	}
 
-	return false
+	return "", ErrInvalidPlan
+}
+
+// Scan implements sql.Scanner, so that plans are validated with ParsePlan when they are read from the database.
+func (p *TenantPlan) Scan(src any) error {
+	return dbutils.ScanWith(p, ParsePlan).Scan(src)
+}
+
+// checkPlan normalizes plan in place, adding ErrInvalidPlan to the validator if it is not supported.
+func checkPlan(v *validation.Validator, plan *TenantPlan) {
+	parsed, err := ParsePlan(string(*plan))
+	if err != nil {
+		v.AddError(ErrInvalidPlan.Field, ErrInvalidPlan.Message)
+
+		return
+	}
+
+	*plan = parsed
+}
+
+// MonthlyRequestQuotas maps each tenant plan to the number of API requests a tenant may make per month.
+//
+//nolint:gochecknoglobals
+var MonthlyRequestQuotas = map[string]int64{
+	string(Free): 10_000,
+	string(Paid): 1_000_000,
+}
+
+// NewPlanRateLimits returns a backend for each tenant plan that limits the requests per second a tenant may make.
+func NewPlanRateLimits() map[string]httputils.RateLimitBackend {
+	return map[string]httputils.RateLimitBackend{
+		string(Free): httputils.NewMemoryRateLimitBackend(5, 10),
+		string(Paid): httputils.NewMemoryRateLimitBackend(50, 100),
+	}
 }
 
 const (
@@ -124,16 +257,12 @@ type CreateTenantRequest struct {
 func (tc *TenantController) CreateTenantHandler(w http.ResponseWriter, r *http.Request) {
 	createTenantRequest, err := httputils.ReadJSON[CreateTenantRequest](w, r)
 	if err != nil {
-		httputils.UnprocessableEntityResponse(w, r, err)
+		httputils.HandleErrorResponse(w, r, err)
 
 		return
 	}
 
-	v := validation.NewValidator()
-	v.Required(createTenantRequest.TenantName, tenantNameRequestKey, "Tenant Name is required")
-	v.Email(createTenantRequest.ContactEmail, contactEmailRequestKey, "Contact Email is required")
-	v.Check(IsValidTenantPlan(createTenantRequest.Plan), planRequestKey, "Invalid plan")
-
+	v := validateCreateTenantRequest(&createTenantRequest)
 	if v.HasErrors() {
 		httputils.FailedValidationResponse(w, r, v.Errors)
 
@@ -150,10 +279,71 @@ func (tc *TenantController) CreateTenantHandler(w http.ResponseWriter, r *http.R
 	headers := make(http.Header)
 	headers.Set("Location", fmt.Sprintf("/tenants/%d", tenantID))
 
-	err = httputils.WriteJSON(w, http.StatusCreated, envelope{"id": tenantID}, headers)
+	httputils.RespondJSON(w, r, http.StatusCreated, envelope{"id": tenantID}, headers)
+}
+
+func validateCreateTenantRequest(createTenantRequest *CreateTenantRequest) *validation.Validator {
+	v := validation.NewValidator()
+	v.Required(createTenantRequest.TenantName, tenantNameRequestKey, "Tenant Name is required")
+	v.MaxLength(createTenantRequest.TenantName, maxTenantNameLength, tenantNameRequestKey, fmt.Sprintf("Tenant Name must be at most %d characters", maxTenantNameLength))
+	v.Email(createTenantRequest.ContactEmail, contactEmailRequestKey, "Contact Email is invalid")
+	checkPlan(v, &createTenantRequest.Plan)
+
+	return v
+}
+
+const (
+	maxBatchSize        = 100
+	maxTenantNameLength = 100
+)
+
+type BatchCreateTenantsRequest struct {
+	Tenants []CreateTenantRequest `json:"tenants"`
+}
+
+// BatchCreateTenantsHandler creates each tenant in the request independently. If some tenants fail to be
+// created, the response is a 207 Multi-Status listing the status of each tenant.
+func (tc *TenantController) BatchCreateTenantsHandler(w http.ResponseWriter, r *http.Request) {
+	batchRequest, err := httputils.ReadJSON[BatchCreateTenantsRequest](w, r)
 	if err != nil {
-		httputils.ServerErrorResponse(w, r, err)
+		httputils.HandleErrorResponse(w, r, err)
+
+		return
 	}
+
+	v := validation.NewValidator()
+	v.Check(len(batchRequest.Tenants) > 0, tenantResourceKey, "At least one tenant is required")
+	v.Check(len(batchRequest.Tenants) <= maxBatchSize, tenantResourceKey, fmt.Sprintf("At most %d tenants are allowed", maxBatchSize))
+
+	if v.HasErrors() {
+		httputils.FailedValidationResponse(w, r, v.Errors)
+
+		return
+	}
+
+	results := make([]httputils.BatchResult, 0, len(batchRequest.Tenants))
+
+	for i := range batchRequest.Tenants {
+		createTenantRequest := &batchRequest.Tenants[i]
+
+		v := validateCreateTenantRequest(createTenantRequest)
+		if v.HasErrors() {
+			results = append(results, httputils.BatchValidationFailure(i, v.Errors))
+
+			continue
+		}
+
+		tenantID, err := CreateTenant(tc.DB, createTenantRequest)
+		if err != nil {
+			results = append(results, httputils.BatchFailure(r, i, err))
+
+			continue
+		}
+
+		results = append(results, httputils.BatchSuccess(i, http.StatusCreated, envelope{"id": tenantID}))
+	}
+
+	httputils.WriteBatchResponse(w, r, results)
 }
 
 type GetTenantResponse struct {
@@ -165,10 +355,9 @@ type GetTenantResponse struct {
 }
 
 func (tc *TenantController) GetTenantHandler(w http.ResponseWriter, r *http.Request) {
-	id, err := parser.ReadIDPathParam(r)
-
+	id, err := httputils.ReadIDParam(r)
 	if err != nil {
-		httputils.NotFoundResponse(w, r)
+		httputils.HandleErrorResponse(w, r, err)
 		return
 	}
 
@@ -179,12 +368,19 @@ func (tc *TenantController) GetTenantHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	err = httputils.WriteJSON(w, http.StatusOK, &GetTenantResponse{ID: tenant.ID, TenantName: tenant.TenantName, ContactEmail: tenant.ContactEmail, Plan: tenant.Plan, IsActive: tenant.IsActive}, nil)
-	if err != nil {
-		httputils.ServerErrorResponse(w, r, err)
+	if httputils.CheckNotModified(w, r, httputils.VersionETag(int64(tenant.Version))) {
+		return
+	}
+
+	if httputils.CheckNotModifiedSince(w, r, tenant.UpdatedAt) {
+		return
 	}
+
+	httputils.RespondJSON(w, r, http.StatusOK, &GetTenantResponse{ID: tenant.ID, TenantName: tenant.TenantName, ContactEmail: tenant.ContactEmail, Plan: tenant.Plan, IsActive: tenant.IsActive}, nil)
 }
 
+// UpdateTenantRequest is the body of a PATCH request. Fields that are omitted or null are left unchanged;
+// fields that are present are validated and applied, so an empty tenantName is rejected rather than ignored.
 type UpdateTenantRequest struct {
 	TenantName   *string     `json:"tenantName"`
 	ContactEmail *string     `json:"contactEmail"`
@@ -193,10 +389,9 @@ type UpdateTenantRequest struct {
 }
 
 func (tc *TenantController) UpdateTenantHandler(w http.ResponseWriter, r *http.Request) {
-	id, err := parser.ReadIDPathParam(r)
-
+	id, err := httputils.ReadIDParam(r)
 	if err != nil {
-		httputils.NotFoundResponse(w, r)
+		httputils.HandleErrorResponse(w, r, err)
 
 		return
 	}
@@ -210,7 +405,7 @@ func (tc *TenantController) UpdateTenantHandler(w http.ResponseWriter, r *http.R
 
 	updateTenantRequest, err := httputils.ReadJSON[UpdateTenantRequest](w, r)
 	if err != nil {
-		httputils.UnprocessableEntityResponse(w, r, err)
+		httputils.HandleErrorResponse(w, r, err)
 
 		return
 	}
@@ -222,8 +417,9 @@ func (tc *TenantController) UpdateTenantHandler(w http.ResponseWriter, r *http.R
 
 	v := validation.NewValidator()
 	v.Required(tenant.TenantName, tenantNameRequestKey, "Tenant Name is required")
-	v.Email(tenant.ContactEmail, contactEmailRequestKey, "Contact Email is required")
-	v.Check(IsValidTenantPlan(tenant.Plan), planRequestKey, "Invalid plan")
+	v.MaxLength(tenant.TenantName, maxTenantNameLength, tenantNameRequestKey, fmt.Sprintf("Tenant Name must be at most %d characters", maxTenantNameLength))
+	v.Email(tenant.ContactEmail, contactEmailRequestKey, "Contact Email is invalid")
+	checkPlan(v, &tenant.Plan)
 
 	if v.HasErrors() {
 		httputils.FailedValidationResponse(w, r, v.Errors)
@@ -233,22 +429,18 @@ func (tc *TenantController) UpdateTenantHandler(w http.ResponseWriter, r *http.R
 
 	err = UpdateTenant(r.Context(), tc.DB, tenant)
 	if err != nil {
-		httputils.HandleErrorResponse(w, r, err)
+		httputils.HandleErrorResponse(w, r, dbutils.MapUniqueConstraint(err, tenantUniqueConstraints))
 
 		return
 	}
 
-	err = httputils.WriteJSON(w, http.StatusOK, &GetTenantResponse{ID: tenant.ID, TenantName: tenant.TenantName, ContactEmail: tenant.ContactEmail, Plan: tenant.Plan, IsActive: tenant.IsActive}, nil)
-	if err != nil {
-		httputils.ServerErrorResponse(w, r, err)
-	}
+	httputils.RespondJSON(w, r, http.StatusOK, &GetTenantResponse{ID: tenant.ID, TenantName: tenant.TenantName, ContactEmail: tenant.ContactEmail, Plan: tenant.Plan, IsActive: tenant.IsActive}, nil)
 }
 
 func (tc *TenantController) DeleteTenantHandler(w http.ResponseWriter, r *http.Request) {
-	id, err := parser.ReadIDPathParam(r)
-
+	id, err := httputils.ReadIDParam(r)
 	if err != nil {
-		httputils.NotFoundResponse(w, r)
+		httputils.HandleErrorResponse(w, r, err)
 
 		return
 	}
@@ -260,31 +452,29 @@ func (tc *TenantController) DeleteTenantHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	err = httputils.WriteJSON(w, http.StatusOK, envelope{"message": "Tenant successfully deleted"}, nil)
-	if err != nil {
-		httputils.ServerErrorResponse(w, r, err)
-	}
+	httputils.RespondJSON(w, r, http.StatusOK, envelope{"message": "Tenant successfully deleted"}, nil)
 }
 
 type SearchTenantsRequest struct {
-	TenantName   *string
-	Plan         *string
-	IsActive     *bool
-	ContactEmail *string
+	TenantName   *string `query:"tenantName"`
+	Plan         *string `query:"plan"`
+	IsActive     *bool   `query:"isActive"`
+	ContactEmail *string `query:"contactEmail"`
 	parser.Filters
 }
 
 func (tc *TenantController) SearchTenantsHandler(w http.ResponseWriter, r *http.Request) {
-	v := validation.NewValidator()
 	queryString := r.URL.Query()
-	searchTenantsRequest := &SearchTenantsRequest{
-		TenantName:   parser.ParseQSString(queryString, tenantNameRequestKey, nil),
-		Plan:         parser.ParseQSString(queryString, planRequestKey, nil),
-		IsActive:     parser.ParseQSBool(queryString, "isActive", nil),
-		ContactEmail: parser.ParseQSString(queryString, contactEmailRequestKey, nil),
+	searchTenantsRequest := &SearchTenantsRequest{}
+	v := parser.BindQuery(r, searchTenantsRequest)
+
+	if searchTenantsRequest.Plan != nil {
+		plan := TenantPlan(*searchTenantsRequest.Plan)
+		checkPlan(v, &plan)
+		*searchTenantsRequest.Plan = string(plan)
 	}
 
-	searchTenantsRequest.ParseQSFilters(queryString, v, []string{"id", tenantNameRequestKey, planRequestKey, contactEmailRequestKey, fmt.Sprintf("-%s", tenantNameRequestKey), fmt.Sprintf("-%s", planRequestKey), fmt.Sprintf("-%s", contactEmailRequestKey)})
+	searchTenantsRequest.ParseQSFilters(queryString, v, []string{"id", tenantNameRequestKey, planRequestKey, contactEmailRequestKey})
 	if v.HasErrors() {
 		httputils.FailedValidationResponse(w, r, v.Errors)
 		return
@@ -295,10 +485,8 @@ func (tc *TenantController) SearchTenantsHandler(w http.ResponseWriter, r *http.
 		httputils.HandleErrorResponse(w, r, err)
 		return
 	}
-	err = httputils.WriteJSON(w, http.StatusOK, envelope{"metadata": pagination, tenantResourceKey: tenants}, nil)
-	if err != nil {
-		httputils.ServerErrorResponse(w, r, err)
-	}
+	page := httputils.NewPage(tenants, searchTenantsRequest.Page, searchTenantsRequest.PageSize, pagination.TotalRecords)
+	httputils.RespondJSON(w, r, http.StatusOK, page, nil)
 }
 
 var ErrTenantAlreadyRegistered = validation.Error{
@@ -306,6 +494,11 @@ var ErrTenantAlreadyRegistered = validation.Error{
 	Message: "This tenant is already registered",
 }
 
+// tenantUniqueConstraints maps the tenants table's UNIQUE constraints to the field errors reported to clients.
+var tenantUniqueConstraints = map[string]validation.Error{
+	tenantNameDbFieldName: ErrTenantAlreadyRegistered,
+}
+
 // service layer
 func CreateTenant(db *sql.DB, createTenantRequest *CreateTenantRequest) (*int64, error) {
 	tenantModel := NewTenantModel(createTenantRequest.TenantName, createTenantRequest.ContactEmail, createTenantRequest.Plan)
@@ -313,11 +506,7 @@ func CreateTenant(db *sql.DB, createTenantRequest *CreateTenantRequest) (*int64,
 	id, err := InsertTenant(db, tenantModel)
 
 	if err != nil {
-		if errors.Is(err, dbutils.ErrUniqueConstraint) && strings.Contains(err.Error(), tenantNameDbFieldName) {
-			return nil, ErrTenantAlreadyRegistered
-		}
-
-		return nil, err
+		return nil, dbutils.MapUniqueConstraint(err, tenantUniqueConstraints)
 	}
 	return id, nil
 }
@@ -359,7 +548,17 @@ var emailTemplates embed.FS
 var htmlTemplates embed.FS
 
 func main() {
-	db := dbutils.Open(parser.ParseEnvStringPanic("DB_FILEPATH"))
+	err := parser.LoadDotEnv(".env")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		panic(err)
+	}
+
+	encryptionKey, err := authutils.GetEncryptionKey()
+	if err != nil {
+		panic(err)
+	}
+
+	db := dbutils.Open(parser.MustParseEnvString("DB_FILEPATH"))
 
 	defer func() {
 		closeErr := db.Close()
@@ -376,9 +575,9 @@ func main() {
 
 	gob.Register(User{})
 
-	authService := NewAuthService(db, mailer, parser.ParseEnvStringPanic("HOST"))
-	tenantController := NewTenantController(db, htmlTemplateMap)
-	err := starter.CreateAppServer[User](authService, db, tenantController)
+	authService := NewAuthService(db, mailer, parser.MustParseEnvString("HOST"))
+	tenantController := NewTenantController(db, htmlTemplateMap, encryptionKey)
+	err = starter.CreateAppServer[User](authService, db, mailer.Workers(), tenantController)
 
 	if err != nil {
 		slog.Error(err.Error())
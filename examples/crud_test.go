@@ -1,14 +1,27 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gurch101/gowebutils/pkg/authutils"
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/httputils"
 	"github.com/gurch101/gowebutils/pkg/testutils"
 )
 
+//nolint:gochecknoglobals
+var testEncryptionKey = []byte("0123456789ABCDEF0123456789ABCDEF")
+
 func doTenantRequest(controller *TenantController, req *http.Request) *httptest.ResponseRecorder {
 	rr := httptest.NewRecorder()
 	router := testutils.NewRouter()
@@ -27,7 +40,7 @@ func TestCreateTenant(t *testing.T) {
 		}
 	}()
 	// Create the TenantController instance with the test database
-	tenantController := NewTenantController(db, nil)
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
 
 	// Define the input JSON for the request
 	createTenantRequest := map[string]interface{}{
@@ -63,6 +76,45 @@ func TestCreateTenant(t *testing.T) {
 	}
 }
 
+func TestCreateTenantGzipBody(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+	var body bytes.Buffer
+	gz := gzip.NewWriter(&body)
+	if _, err := gz.Write([]byte(`{"tenantName":"GzipTenant","contactEmail":"gzip@example.com","plan":"paid"}`)); err != nil {
+		t.Fatalf("Failed to compress body: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Failed to compress body: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/tenants", &body)
+	httputils.SetJSONContentTypeRequestHeader(req)
+	req.Header.Set("Content-Encoding", "gzip")
+
+	rr := doTenantRequest(tenantController, req)
+	testutils.AssertStatus(t, rr, http.StatusCreated)
+
+	var contactEmail, plan string
+	err := db.QueryRow("SELECT contact_email, plan FROM tenants WHERE tenant_name = ?", "GzipTenant").Scan(&contactEmail, &plan)
+	if err != nil {
+		t.Fatalf("Failed to query tenant: %v", err)
+	}
+
+	if contactEmail != "gzip@example.com" || plan != "paid" {
+		t.Errorf("Expected the decompressed tenant to be created, got contact email %q and plan %q", contactEmail, plan)
+	}
+}
+
 func TestCreateTenantInvalidPlan(t *testing.T) {
 	t.Parallel()
 	db := testutils.SetupTestDB(t)
@@ -73,7 +125,7 @@ func TestCreateTenantInvalidPlan(t *testing.T) {
 		}
 	}()
 	// Create the TenantController instance with the test database
-	tenantController := NewTenantController(db, nil)
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
 
 	// Define the input JSON for the request
 	createTenantRequest := map[string]interface{}{
@@ -85,19 +137,275 @@ func TestCreateTenantInvalidPlan(t *testing.T) {
 	req := testutils.CreatePostRequest(t, "/tenants", createTenantRequest)
 	rr := doTenantRequest(tenantController, req)
 
-	// Check the response status code
+	testutils.AssertStatus(t, rr, http.StatusBadRequest)
+
+	var response map[string]interface{}
+	testutils.AssertJSONBody(t, rr, &response)
+	testutils.AssertError(t, response, "plan", "Invalid plan")
+}
+
+func TestParsePlan(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		plan     string
+		expected TenantPlan
+		err      error
+	}{
+		{"free", "free", Free, nil},
+		{"paid", "paid", Paid, nil},
+		{"uppercase", "PAID", Paid, nil},
+		{"mixed case with whitespace", "  Free ", Free, nil},
+		{"empty", "", "", ErrInvalidPlan},
+		{"unsupported", "enterprise", "", ErrInvalidPlan},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			plan, err := ParsePlan(tt.plan)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected error %v, got %v", tt.err, err)
+			}
+
+			if plan != tt.expected {
+				t.Errorf("Expected plan '%s', got '%s'", tt.expected, plan)
+			}
+		})
+	}
+}
+
+func TestGetTenantByIdScansPlan(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	if _, err := db.Exec("UPDATE tenants SET plan = ' PAID ' WHERE id = 1"); err != nil {
+		t.Fatalf("Failed to update tenant: %v", err)
+	}
+	if _, err := db.Exec("UPDATE tenants SET plan = 'enterprise' WHERE id = 2"); err != nil {
+		t.Fatalf("Failed to update tenant: %v", err)
+	}
+
+	tenant, err := GetTenantById(db, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if tenant.Plan != Paid {
+		t.Errorf("Expected plan '%s', got '%s'", Paid, tenant.Plan)
+	}
+
+	_, err = GetTenantById(db, 2)
+	if !errors.Is(err, dbutils.ErrInvalidColumnValue) {
+		t.Errorf("Expected ErrInvalidColumnValue for an unsupported stored plan, got %v", err)
+	}
+}
+
+func TestCreateTenantNormalizesPlan(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+	createTenantRequest := map[string]interface{}{
+		"tenantName":   "TestTenant",
+		"contactEmail": "test@example.com",
+		"plan":         "Paid",
+	}
+
+	req := testutils.CreatePostRequest(t, "/tenants", createTenantRequest)
+	rr := doTenantRequest(tenantController, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201 Created, got %d", rr.Code)
+	}
+
+	var plan string
+	err := db.QueryRow("SELECT plan FROM tenants WHERE tenant_name = ?", "TestTenant").Scan(&plan)
+	if err != nil {
+		t.Fatalf("Failed to query tenant: %v", err)
+	}
+
+	if plan != string(Paid) {
+		t.Errorf("Expected plan '%s', got '%s'", Paid, plan)
+	}
+}
+
+func TestCreateTenantIdempotencyKey(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+	createTenantRequest := map[string]interface{}{
+		"tenantName":   "TestTenant",
+		"contactEmail": "test@example.com",
+		"plan":         "free",
+	}
+	headers := http.Header{"Idempotency-Key": []string{"create-test-tenant"}}
+
+	newRequest := func(userID int64) *http.Request {
+		req := testutils.CreateRequest(t, http.MethodPost, "/tenants", createTenantRequest, headers)
+
+		return authutils.ContextSetUser(req, User{ID: userID, TenantID: 1, UserName: "", Email: ""})
+	}
+
+	first := doTenantRequest(tenantController, newRequest(1))
+	testutils.AssertStatus(t, first, http.StatusCreated)
+
+	second := doTenantRequest(tenantController, newRequest(1))
+	testutils.AssertStatus(t, second, http.StatusCreated)
+
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("Expected retry to replay %s, got %s", first.Body, second.Body)
+	}
+
+	other := doTenantRequest(tenantController, newRequest(2))
+	if other.Header().Get(httputils.IdempotentReplayedHeader) == "true" {
+		t.Errorf("Expected another user's request with the same key not to be replayed")
+	}
+
+	var count int
+
+	err := db.QueryRow("SELECT count(*) FROM tenants WHERE tenant_name = ?", "TestTenant").Scan(&count)
+	if err != nil {
+		t.Fatalf("Failed to count tenants: %v", err)
+	}
+
+	if count != 1 {
+		t.Errorf("Expected 1 tenant to be created, got %d", count)
+	}
+}
+
+func TestTenantRoutesEnforceMonthlyQuota(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+	limit := MonthlyRequestQuotas[string(Free)]
+	now := time.Now().UTC()
+
+	// Tenant 1 is seeded on the free plan; exhaust its quota for the current month.
+	_, err := db.Exec(
+		"INSERT INTO quota_usage (tenant_id, period, request_count) VALUES (?, ?, ?)", 1, now.Format("2006-01"), limit,
+	)
+	if err != nil {
+		t.Fatalf("Failed to seed quota usage: %v", err)
+	}
+
+	newRequest := func(tenantID int64) *http.Request {
+		req := testutils.CreateGetRequest("/tenants/1")
+
+		return authutils.ContextSetUser(req, User{ID: 1, TenantID: tenantID, UserName: "", Email: ""})
+	}
+
+	rr := doTenantRequest(tenantController, newRequest(1))
+	testutils.AssertStatus(t, rr, http.StatusTooManyRequests)
+
+	reset := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	if got := rr.Header().Get("X-Quota-Reset"); got != strconv.FormatInt(reset.Unix(), 10) {
+		t.Errorf("Expected X-Quota-Reset %d, got %q", reset.Unix(), got)
+	}
+
+	if got := rr.Header().Get("X-Quota-Remaining"); got != "0" {
+		t.Errorf("Expected X-Quota-Remaining 0, got %q", got)
+	}
+
+	retryAfter, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+	if err != nil || retryAfter <= 0 || retryAfter > int(time.Until(reset).Seconds())+1 {
+		t.Errorf("Expected Retry-After until the quota resets, got %q", rr.Header().Get("Retry-After"))
+	}
+
+	// Tenant 2 is on the paid plan and has not used any of its quota.
+	rr = doTenantRequest(tenantController, newRequest(2))
+	testutils.AssertStatus(t, rr, http.StatusOK)
+
+	if got := rr.Header().Get("X-Quota-Remaining"); got != strconv.FormatInt(MonthlyRequestQuotas[string(Paid)]-1, 10) {
+		t.Errorf("Expected the request to be counted against the paid quota, got X-Quota-Remaining %q", got)
+	}
+}
+
+func TestCreateTenantInvalidContactEmail(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+	createTenantRequest := map[string]interface{}{
+		"tenantName":   "TestTenant",
+		"contactEmail": "acme.acme.com",
+		"plan":         "free",
+	}
+
+	req := testutils.CreatePostRequest(t, "/tenants", createTenantRequest)
+	rr := doTenantRequest(tenantController, req)
+
 	if rr.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400 Created, got %d", rr.Code)
+		t.Errorf("Expected status 400 Bad Request, got %d", rr.Code)
 	}
 
-	// Check the response body
 	var response map[string]interface{}
 	err := json.Unmarshal(rr.Body.Bytes(), &response)
 	if err != nil {
 		t.Fatalf("Failed to unmarshal response: %v", err)
 	}
 
-	testutils.AssertError(t, response, "plan", "Invalid plan")
+	testutils.AssertError(t, response, "contactEmail", "Contact Email is invalid")
+}
+
+func TestCreateTenantMultipleErrors(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+	req := testutils.CreatePostRequest(t, "/tenants", map[string]interface{}{
+		"tenantName":   "TestTenant",
+		"contactEmail": "acme.acme.com",
+		"plan":         "enterprise",
+	})
+	rr := doTenantRequest(tenantController, req)
+
+	testutils.AssertStatus(t, rr, http.StatusBadRequest)
+
+	var response map[string]interface{}
+	testutils.AssertJSONBody(t, rr, &response)
+	testutils.AssertErrorCount(t, response, 2)
+	testutils.AssertErrorContains(t, response, "plan", "Invalid plan")
+	testutils.AssertErrorContains(t, response, "contactEmail", "Contact Email is invalid")
 }
 
 func TestCreateTenant_DuplicateTenant(t *testing.T) {
@@ -110,7 +418,7 @@ func TestCreateTenant_DuplicateTenant(t *testing.T) {
 		}
 	}()
 	// Create the TenantController instance with the test database
-	tenantController := NewTenantController(db, nil)
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
 
 	// Define the input JSON for the request
 	createTenantRequest := map[string]interface{}{
@@ -145,6 +453,152 @@ func TestCreateTenant_DuplicateTenant(t *testing.T) {
 	testutils.AssertError(t, response, "tenantName", "This tenant is already registered")
 }
 
+func TestCreateTenant_ConcurrentDuplicateTenant(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+	createTenantRequest := map[string]interface{}{
+		"tenantName":   "TestTenant",
+		"contactEmail": "acme@acme.com",
+		"plan":         "free",
+	}
+
+	const concurrentRequests = 8
+
+	var wg sync.WaitGroup
+
+	responses := make([]*httptest.ResponseRecorder, concurrentRequests)
+	for i := range responses {
+		req := testutils.CreatePostRequest(t, "/tenants", createTenantRequest)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			responses[i] = doTenantRequest(tenantController, req)
+		}()
+	}
+
+	wg.Wait()
+
+	created := 0
+
+	for _, rr := range responses {
+		if rr.Code == http.StatusCreated {
+			created++
+
+			continue
+		}
+
+		testutils.AssertStatus(t, rr, http.StatusBadRequest)
+
+		var response map[string]interface{}
+
+		testutils.AssertJSONBody(t, rr, &response)
+		testutils.AssertError(t, response, "tenantName", "This tenant is already registered")
+	}
+
+	if created != 1 {
+		t.Errorf("Expected exactly 1 tenant to be created, got %d", created)
+	}
+}
+
+func TestBatchCreateTenants_PartialSuccess(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+	batchRequest := map[string]interface{}{
+		"tenants": []map[string]interface{}{
+			{"tenantName": "BatchTenant", "contactEmail": "batch@example.com", "plan": "free"},
+			{"tenantName": "InvalidPlanTenant", "contactEmail": "invalid@example.com", "plan": "invalid"},
+			{"tenantName": "Acme", "contactEmail": "acme@acme.com", "plan": "paid"},
+		},
+	}
+
+	req := testutils.CreatePostRequest(t, "/tenants/batch", batchRequest)
+	rr := doTenantRequest(tenantController, req)
+
+	if rr.Code != http.StatusMultiStatus {
+		t.Fatalf("Expected status 207 Multi-Status, got %d", rr.Code)
+	}
+
+	var response struct {
+		Results []struct {
+			Index  int                    `json:"index"`
+			Status int                    `json:"status"`
+			Data   map[string]interface{} `json:"data"`
+			Errors []map[string]string    `json:"errors"`
+		} `json:"results"`
+	}
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if len(response.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(response.Results))
+	}
+
+	if response.Results[0].Status != http.StatusCreated || response.Results[0].Data["id"] == nil {
+		t.Errorf("Expected first tenant to be created, got %+v", response.Results[0])
+	}
+
+	if response.Results[1].Status != http.StatusBadRequest || response.Results[1].Errors[0]["field"] != "plan" {
+		t.Errorf("Expected second tenant to fail plan validation, got %+v", response.Results[1])
+	}
+
+	if response.Results[2].Status != http.StatusBadRequest || response.Results[2].Errors[0]["field"] != "tenantName" {
+		t.Errorf("Expected third tenant to be rejected as a duplicate, got %+v", response.Results[2])
+	}
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM tenants WHERE tenant_name = ?", "BatchTenant").Scan(&count)
+	if err != nil || count != 1 {
+		t.Errorf("Expected BatchTenant to be persisted, got count %d, err %v", count, err)
+	}
+}
+
+func TestBatchCreateTenants_AllSucceed(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+	batchRequest := map[string]interface{}{
+		"tenants": []map[string]interface{}{
+			{"tenantName": "BatchTenant1", "contactEmail": "batch1@example.com", "plan": "free"},
+			{"tenantName": "BatchTenant2", "contactEmail": "batch2@example.com", "plan": "paid"},
+		},
+	}
+
+	req := testutils.CreatePostRequest(t, "/tenants/batch", batchRequest)
+	rr := doTenantRequest(tenantController, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200 OK, got %d", rr.Code)
+	}
+}
+
 func TestGetTenantHandler(t *testing.T) {
 	t.Parallel()
 	db := testutils.SetupTestDB(t)
@@ -155,7 +609,7 @@ func TestGetTenantHandler(t *testing.T) {
 		}
 	}()
 	// Create the TenantController instance with the test database
-	tenantController := NewTenantController(db, nil)
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
 
 	req := testutils.CreateGetRequest("/tenants/1")
 	rr := doTenantRequest(tenantController, req)
@@ -189,6 +643,244 @@ func TestGetTenantHandler(t *testing.T) {
 	}
 }
 
+func TestGetTenantHandler_NotModified(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+	rr := doTenantRequest(tenantController, testutils.CreateGetRequest("/tenants/1"))
+	etag := rr.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected ETag header to be set")
+	}
+
+	req := testutils.CreateGetRequest("/tenants/1")
+	req.Header.Set("If-None-Match", etag)
+	rr = doTenantRequest(tenantController, req)
+
+	if rr.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304 Not Modified, got %d", rr.Code)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("Expected empty body, got %q", rr.Body.String())
+	}
+}
+
+func TestGetTenantHandler_NotModifiedSince(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+	rr := doTenantRequest(tenantController, testutils.CreateGetRequest("/tenants/1"))
+	lastModified, err := http.ParseTime(rr.Header().Get("Last-Modified"))
+	if err != nil {
+		t.Fatalf("Expected a valid Last-Modified header, got %q", rr.Header().Get("Last-Modified"))
+	}
+
+	req := testutils.CreateGetRequest("/tenants/1")
+	req.Header.Set("If-Modified-Since", lastModified.Add(time.Minute).Format(http.TimeFormat))
+	rr = doTenantRequest(tenantController, req)
+
+	if rr.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304 Not Modified, got %d", rr.Code)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("Expected empty body, got %q", rr.Body.String())
+	}
+
+	req = testutils.CreateGetRequest("/tenants/1")
+	req.Header.Set("If-Modified-Since", lastModified.Add(-time.Minute).Format(http.TimeFormat))
+	rr = doTenantRequest(tenantController, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for a stale If-Modified-Since, got %d", rr.Code)
+	}
+}
+
+func TestGetTenantHandler_DataEnvelope(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+	router := testutils.NewRouter()
+	tenantController.ProtectedRoutes(router)
+
+	tests := []struct {
+		name    string
+		enabled bool
+	}{
+		{"unwrapped", false},
+		{"wrapped", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := httputils.DataEnvelopeMiddleware(tt.enabled)(router)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, testutils.CreateGetRequest("/tenants/1"))
+			testutils.AssertStatus(t, rr, http.StatusOK)
+
+			var response struct {
+				GetTenantResponse
+				Data *GetTenantResponse `json:"data"`
+			}
+			testutils.AssertJSONBody(t, rr, &response)
+
+			tenant := response.GetTenantResponse
+			if tt.enabled {
+				if response.Data == nil {
+					t.Fatalf("Expected tenant wrapped in data, got %s", rr.Body.String())
+				}
+				if response.ID != 0 {
+					t.Errorf("Expected no top-level tenant fields, got %s", rr.Body.String())
+				}
+				tenant = *response.Data
+			} else if response.Data != nil {
+				t.Errorf("Expected unwrapped tenant, got %s", rr.Body.String())
+			}
+
+			if tenant.ID != 1 || tenant.TenantName != "Acme" {
+				t.Errorf("Expected tenant 1 Acme, got %+v", tenant)
+			}
+
+			rr = httptest.NewRecorder()
+			handler.ServeHTTP(rr, testutils.CreateGetRequest("/tenants/999"))
+			testutils.AssertStatus(t, rr, http.StatusNotFound)
+
+			var errResponse map[string]interface{}
+			testutils.AssertJSONBody(t, rr, &errResponse)
+			if _, ok := errResponse["errors"]; !ok {
+				t.Errorf("Expected unwrapped error envelope, got %s", rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestTenantRoutes_UnknownPath(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+	rr := doTenantRequest(tenantController, testutils.CreatePostRequest(t, "/unknown", map[string]interface{}{}))
+	testutils.AssertStatus(t, rr, http.StatusNotFound)
+
+	if contentType := rr.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", contentType)
+	}
+
+	var response map[string]interface{}
+
+	testutils.AssertJSONBody(t, rr, &response)
+
+	if response["errors"] != "the requested resource could not be found" {
+		t.Errorf("Expected not found error, got %v", response)
+	}
+}
+
+func TestTenantRoutes_OpenAPI(t *testing.T) {
+	t.Parallel()
+
+	tenantController := NewTenantController(nil, nil, testEncryptionKey)
+	tenantController.OpenAPI = httputils.NewOpenAPI("Tenants API", "1.0.0")
+
+	router := testutils.NewRouter()
+	tenantController.ProtectedRoutes(router)
+	router.Get("/openapi.json", tenantController.OpenAPI.ServeHTTP)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	testutils.AssertStatus(t, rr, http.StatusOK)
+
+	type operation struct {
+		Responses map[string]struct {
+			Content map[string]struct {
+				Schema map[string]any `json:"schema"`
+			} `json:"content"`
+		} `json:"responses"`
+	}
+
+	var spec struct {
+		OpenAPI    string                          `json:"openapi"`
+		Paths      map[string]map[string]operation `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]any `json:"properties"`
+				Required   []string                  `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+
+	testutils.AssertJSONBody(t, rr, &spec)
+
+	if spec.OpenAPI != "3.0.3" {
+		t.Errorf("Expected OpenAPI 3.0.3, got %q", spec.OpenAPI)
+	}
+
+	expectedMethods := map[string][]string{
+		"/tenants":       {"get", "post"},
+		"/tenants/{id}":  {"delete", "get", "patch"},
+		"/tenants/batch": {"post"},
+	}
+	for path, methods := range expectedMethods {
+		for _, method := range methods {
+			if _, ok := spec.Paths[path][method]; !ok {
+				t.Errorf("Expected %s %s in the spec, got %v", method, path, spec.Paths[path])
+			}
+		}
+	}
+
+	getTenant := spec.Paths["/tenants/{id}"]["get"]
+	expectedRef := "#/components/schemas/GetTenantResponse"
+	if ref := getTenant.Responses["200"].Content["application/json"].Schema["$ref"]; ref != expectedRef {
+		t.Errorf("Expected GET /tenants/{id} to respond with %s, got %v", expectedRef, ref)
+	}
+
+	schema, ok := spec.Components.Schemas["GetTenantResponse"]
+	if !ok {
+		t.Fatalf("Expected a GetTenantResponse schema, got %v", spec.Components.Schemas)
+	}
+
+	expectedProperties := map[string]map[string]any{
+		"id":           {"type": "integer", "format": "int64"},
+		"tenantName":   {"type": "string"},
+		"contactEmail": {"type": "string"},
+		"plan":         {"type": "string"},
+		"isActive":     {"type": "boolean"},
+	}
+	if !reflect.DeepEqual(schema.Properties, expectedProperties) {
+		t.Errorf("Expected GetTenantResponse properties %v, got %v", expectedProperties, schema.Properties)
+	}
+
+	if _, ok := spec.Components.Schemas["Page_SearchTenantResponse"]; !ok {
+		t.Errorf("Expected a Page_SearchTenantResponse schema, got %v", spec.Components.Schemas)
+	}
+}
+
 func TestGetTenantHandler_InvalidID(t *testing.T) {
 	t.Parallel()
 	db := testutils.SetupTestDB(t)
@@ -199,14 +891,14 @@ func TestGetTenantHandler_InvalidID(t *testing.T) {
 		}
 	}()
 	// Create the TenantController instance with the test database
-	tenantController := NewTenantController(db, nil)
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
 
-	req := testutils.CreateGetRequest("/tenants/invalid")
+	req := testutils.CreateGetRequest("/tenants/abc")
 	rr := doTenantRequest(tenantController, req)
 
 	// Check the response status code
-	if rr.Code != http.StatusNotFound {
-		t.Errorf("Expected status 404 Not Found, got %d", rr.Code)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 Bad Request, got %d", rr.Code)
 	}
 }
 
@@ -220,7 +912,7 @@ func TestGetTenantHandler_NotFound(t *testing.T) {
 		}
 	}()
 	// Create the TenantController instance with the test database
-	tenantController := NewTenantController(db, nil)
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
 
 	req := testutils.CreateGetRequest("/tenants/9999")
 	rr := doTenantRequest(tenantController, req)
@@ -241,7 +933,7 @@ func TestDeleteTenantHandler(t *testing.T) {
 		}
 	}()
 	// Create the TenantController instance with the test database
-	tenantController := NewTenantController(db, nil)
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
 
 	req := testutils.CreateDeleteRequest("/tenants/1")
 	rr := doTenantRequest(tenantController, req)
@@ -260,6 +952,15 @@ func TestDeleteTenantHandler(t *testing.T) {
 	if count != 0 {
 		t.Errorf("Expected tenant to be deleted, but it still exists")
 	}
+
+	// Users reference tenants with ON DELETE CASCADE, so they are deleted with the tenant.
+	err = db.QueryRow("SELECT COUNT(*) FROM users WHERE tenant_id = 1").Scan(&count)
+	if err != nil {
+		t.Fatalf("Failed to query users: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected the tenant's users to be deleted, but %d remain", count)
+	}
 }
 
 func TestDeleteTenantHandler_InvalidID(t *testing.T) {
@@ -271,12 +972,12 @@ func TestDeleteTenantHandler_InvalidID(t *testing.T) {
 			t.Fatalf("Failed to close database connection: %v", closeErr)
 		}
 	}()
-	tenantController := NewTenantController(db, nil)
-	req := testutils.CreateDeleteRequest("/tenants/invalid")
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+	req := testutils.CreateDeleteRequest("/tenants/abc")
 	rr := doTenantRequest(tenantController, req)
 
-	if rr.Code != http.StatusNotFound {
-		t.Errorf("Expected status 404, got %d", rr.Code)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 Bad Request, got %d", rr.Code)
 	}
 }
 
@@ -289,7 +990,7 @@ func TestDeleteTenantHandler_NotFound(t *testing.T) {
 			t.Fatalf("Failed to close database connection: %v", closeErr)
 		}
 	}()
-	tenantController := NewTenantController(db, nil)
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
 	req := testutils.CreateDeleteRequest("/tenants/9999")
 	rr := doTenantRequest(tenantController, req)
 
@@ -308,7 +1009,7 @@ func TestUpdateTenantHandler(t *testing.T) {
 		}
 	}()
 	// Create the TenantController instance with the test database
-	tenantController := NewTenantController(db, nil)
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
 
 	// Define the input JSON for the update request
 	updateTenantRequest := map[string]interface{}{
@@ -365,6 +1066,102 @@ func TestUpdateTenantHandler(t *testing.T) {
 	}
 }
 
+func TestUpdateTenantHandler_PartialUpdate(t *testing.T) {
+	t.Parallel()
+
+	type tenantRow struct {
+		TenantName   string
+		ContactEmail string
+		Plan         string
+		IsActive     bool
+	}
+
+	original := tenantRow{TenantName: "Acme", ContactEmail: "admin@acme.com", Plan: "free", IsActive: true}
+
+	tests := []struct {
+		name     string
+		body     map[string]interface{}
+		expected tenantRow
+	}{
+		{
+			name:     "plan only",
+			body:     map[string]interface{}{"plan": "paid"},
+			expected: tenantRow{TenantName: "Acme", ContactEmail: "admin@acme.com", Plan: "paid", IsActive: true},
+		},
+		{
+			name:     "isActive only",
+			body:     map[string]interface{}{"isActive": false},
+			expected: tenantRow{TenantName: "Acme", ContactEmail: "admin@acme.com", Plan: "free", IsActive: false},
+		},
+		{
+			name:     "null fields are not provided",
+			body:     map[string]interface{}{"tenantName": nil, "contactEmail": nil, "plan": "paid"},
+			expected: tenantRow{TenantName: "Acme", ContactEmail: "admin@acme.com", Plan: "paid", IsActive: true},
+		},
+		{
+			name:     "empty body",
+			body:     map[string]interface{}{},
+			expected: original,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			db := testutils.SetupTestDB(t)
+			defer func() {
+				closeErr := db.Close()
+				if closeErr != nil {
+					t.Fatalf("Failed to close database connection: %v", closeErr)
+				}
+			}()
+			tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+			rr := doTenantRequest(tenantController, testutils.CreatePatchRequest(t, "/tenants/1", tt.body))
+
+			testutils.AssertStatus(t, rr, http.StatusOK)
+
+			var tenant tenantRow
+			err := db.QueryRow(`SELECT tenant_name, contact_email, plan, is_active FROM tenants WHERE id = 1`).
+				Scan(&tenant.TenantName, &tenant.ContactEmail, &tenant.Plan, &tenant.IsActive)
+			if err != nil {
+				t.Fatalf("Failed to query updated tenant: %v", err)
+			}
+
+			if tenant != tt.expected {
+				t.Errorf("Expected tenant %+v, got %+v", tt.expected, tenant)
+			}
+		})
+	}
+}
+
+func TestUpdateTenantHandler_EmptyTenantName(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+	req := testutils.CreatePatchRequest(t, "/tenants/1", map[string]interface{}{"tenantName": ""})
+	rr := doTenantRequest(tenantController, req)
+
+	testutils.AssertStatus(t, rr, http.StatusBadRequest)
+
+	var response map[string]interface{}
+	testutils.AssertJSONBody(t, rr, &response)
+	testutils.AssertErrorContains(t, response, "tenantName", "Tenant Name is required")
+
+	var tenantName string
+	err := db.QueryRow(`SELECT tenant_name FROM tenants WHERE id = 1`).Scan(&tenantName)
+	if err != nil || tenantName != "Acme" {
+		t.Errorf("Expected tenant name to be unchanged, got '%s', %v", tenantName, err)
+	}
+}
+
 func TestUpdateTenantHandler_InvalidID(t *testing.T) {
 	t.Parallel()
 	db := testutils.SetupTestDB(t)
@@ -374,12 +1171,12 @@ func TestUpdateTenantHandler_InvalidID(t *testing.T) {
 			t.Fatalf("Failed to close database connection: %v", closeErr)
 		}
 	}()
-	tenantController := NewTenantController(db, nil)
-	req := testutils.CreatePatchRequest(t, "/tenants/invalid", map[string]interface{}{})
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+	req := testutils.CreatePatchRequest(t, "/tenants/abc", map[string]interface{}{})
 	rr := doTenantRequest(tenantController, req)
 
-	if rr.Code != http.StatusNotFound {
-		t.Errorf("Expected status 404, got %d", rr.Code)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 Bad Request, got %d", rr.Code)
 	}
 }
 
@@ -392,7 +1189,7 @@ func TestUpdateTenantHandler_NotFound(t *testing.T) {
 			t.Fatalf("Failed to close database connection: %v", closeErr)
 		}
 	}()
-	tenantController := NewTenantController(db, nil)
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
 	req := testutils.CreatePatchRequest(t, "/tenants/9999", map[string]interface{}{})
 	rr := doTenantRequest(tenantController, req)
 
@@ -410,7 +1207,7 @@ func TestUpdateTenantHandler_InvalidRequest(t *testing.T) {
 			t.Fatalf("Failed to close database connection: %v", closeErr)
 		}
 	}()
-	tenantController := NewTenantController(db, nil)
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
 	req := testutils.CreatePatchRequest(t, "/tenants/1", map[string]interface{}{
 		"tenantName":   "UpdatedTenant",
 		"contactEmail": "updated@example.com",
@@ -422,4 +1219,214 @@ func TestUpdateTenantHandler_InvalidRequest(t *testing.T) {
 	if rr.Code != http.StatusBadRequest {
 		t.Errorf("Expected status 400, got %d", rr.Code)
 	}
+
+	var response map[string]interface{}
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	testutils.AssertError(t, response, "plan", "Invalid plan")
+}
+
+func TestInviteUser(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+	req := testutils.CreatePostRequest(t, "/api/invite", map[string]interface{}{
+		"userName": "invitee",
+		"email":    "invitee@acme.com",
+	})
+	req = testutils.WithAuthUser(req, User{ID: 1, TenantID: 7, UserName: "admin", Email: "admin@acme.com"})
+	rr := doTenantRequest(tenantController, req)
+
+	testutils.AssertStatus(t, rr, http.StatusOK)
+
+	var response map[string]string
+	testutils.AssertJSONBody(t, rr, &response)
+
+	payload, err := authutils.VerifyInviteToken(testEncryptionKey, response["token"])
+	if err != nil {
+		t.Fatalf("Failed to verify invite token: %v", err)
+	}
+
+	if payload["tenant_id"] != float64(7) || payload["email"] != "invitee@acme.com" {
+		t.Errorf("Expected invite for tenant 7 and invitee@acme.com, got %v", payload)
+	}
+}
+
+func TestInviteUser_InvalidBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		body           string
+		contentType    string
+		expectedStatus int
+	}{
+		{"malformed JSON", `{"email":`, "application/json", http.StatusBadRequest},
+		{"unsupported charset", `{"email":"invitee@acme.com"}`, "application/json; charset=iso-8859-1",
+			http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tenantController := NewTenantController(nil, nil, testEncryptionKey)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/invite", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req = testutils.WithAuthUser(req, User{ID: 1, TenantID: 7, UserName: "admin", Email: "admin@acme.com"})
+			rr := doTenantRequest(tenantController, req)
+
+			testutils.AssertStatus(t, rr, tt.expectedStatus)
+		})
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestGetTenantHandler_RateLimited(t *testing.T) {
+	t.Setenv("RATE_LIMIT_RATE", "0.001")
+	t.Setenv("RATE_LIMIT_BURST", "1")
+
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+	tenantController := NewTenantController(db, nil, testEncryptionKey)
+	router := testutils.NewRouter()
+	tenantController.ProtectedRoutes(router)
+
+	server := testutils.NewTestServer(router)
+	defer server.Close()
+
+	for i, expectedStatus := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		resp, err := http.Get(server.URL + "/tenants/1")
+		if err != nil {
+			t.Fatalf("Request %d failed: %v", i, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != expectedStatus {
+			t.Errorf("Expected request %d to get status %d, got %d", i, expectedStatus, resp.StatusCode)
+		}
+	}
+}
+
+func TestSearchTenantsHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedIDs   []int64
+		expectedTotal int
+	}{
+		{name: "no filters", query: "", expectedIDs: []int64{1, 2, 3, 4}, expectedTotal: 4},
+		{name: "first page", query: "page=1&pageSize=3", expectedIDs: []int64{1, 2, 3}, expectedTotal: 4},
+		{name: "last partial page", query: "page=2&pageSize=3", expectedIDs: []int64{4}, expectedTotal: 4},
+		{name: "page past the end", query: "page=3&pageSize=3", expectedIDs: []int64{}, expectedTotal: 0},
+		{name: "max page size", query: "pageSize=100", expectedIDs: []int64{1, 2, 3, 4}, expectedTotal: 4},
+		{name: "plan", query: "plan=paid", expectedIDs: []int64{2, 3}, expectedTotal: 2},
+		{name: "plan is case insensitive", query: "plan=PAID", expectedIDs: []int64{2, 3}, expectedTotal: 2},
+		{name: "inactive", query: "isActive=false", expectedIDs: []int64{3}, expectedTotal: 1},
+		{name: "plan and active", query: "plan=paid&isActive=true", expectedIDs: []int64{2}, expectedTotal: 1},
+		{name: "plan and inactive", query: "plan=free&isActive=false", expectedIDs: []int64{}, expectedTotal: 0},
+		{name: "filter and page", query: "plan=free&pageSize=1&page=2", expectedIDs: []int64{4}, expectedTotal: 2},
+		{name: "sort descending", query: "sort=-id", expectedIDs: []int64{4, 3, 2, 1}, expectedTotal: 4},
+		{name: "sort by name", query: "sort=-tenantName", expectedIDs: []int64{4, 3, 2, 1}, expectedTotal: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			db := testutils.SetupTestDB(t)
+			defer func() {
+				closeErr := db.Close()
+				if closeErr != nil {
+					t.Fatalf("Failed to close database connection: %v", closeErr)
+				}
+			}()
+
+			_, err := db.Exec(`INSERT INTO tenants (tenant_name, contact_email, plan, is_active) VALUES
+				('Globex', 'admin@globex.com', 'paid', FALSE),
+				('Initech', 'admin@initech.com', 'free', TRUE)`)
+			if err != nil {
+				t.Fatalf("Failed to insert tenants: %v", err)
+			}
+
+			tenantController := NewTenantController(db, nil, testEncryptionKey)
+			rr := doTenantRequest(tenantController, testutils.CreateGetRequest("/tenants?"+tt.query))
+
+			testutils.AssertStatus(t, rr, http.StatusOK)
+
+			var response httputils.Page[SearchTenantResponse]
+			testutils.AssertJSONBody(t, rr, &response)
+
+			ids := make([]int64, 0, len(response.Items))
+			for _, tenant := range response.Items {
+				ids = append(ids, tenant.ID)
+			}
+
+			if !reflect.DeepEqual(ids, tt.expectedIDs) {
+				t.Errorf("Expected tenant IDs %v, got %v", tt.expectedIDs, ids)
+			}
+
+			if response.TotalItems != tt.expectedTotal {
+				t.Errorf("Expected %d total items, got %d", tt.expectedTotal, response.TotalItems)
+			}
+		})
+	}
+}
+
+func TestSearchTenantsHandler_InvalidRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		query   string
+		field   string
+		message string
+	}{
+		{name: "page zero", query: "page=0", field: "page", message: "must be greater than zero"},
+		{name: "page size zero", query: "pageSize=0", field: "pageSize", message: "must be greater than zero"},
+		{name: "page size too large", query: "pageSize=101", field: "pageSize", message: "must be a maximum of 100"},
+		{name: "unsupported sort", query: "sort=createdAt", field: "sort", message: "invalid sort value"},
+		{name: "injected sort", query: "sort=id%3B%20DROP%20TABLE%20tenants", field: "sort", message: "invalid sort value"},
+		{name: "unsupported plan", query: "plan=enterprise", field: "plan", message: "Invalid plan"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			db := testutils.SetupTestDB(t)
+			defer func() {
+				closeErr := db.Close()
+				if closeErr != nil {
+					t.Fatalf("Failed to close database connection: %v", closeErr)
+				}
+			}()
+			tenantController := NewTenantController(db, nil, testEncryptionKey)
+
+			rr := doTenantRequest(tenantController, testutils.CreateGetRequest("/tenants?"+tt.query))
+
+			testutils.AssertStatus(t, rr, http.StatusBadRequest)
+
+			var response map[string]interface{}
+			testutils.AssertJSONBody(t, rr, &response)
+			testutils.AssertErrorContains(t, response, tt.field, tt.message)
+		})
+	}
 }
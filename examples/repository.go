@@ -17,6 +17,7 @@ type tenantModel struct {
 	Plan         TenantPlan
 	IsActive     bool
 	CreatedAt    time.Time
+	UpdatedAt    time.Time
 	Version      int32
 }
 
@@ -27,9 +28,14 @@ const (
 	planDbFieldName         = "plan"
 	isActiveDbFieldName     = "is_active"
 	createdAtDbFieldName    = "created_at"
+	updatedAtDbFieldName    = "updated_at"
 	versionDbFieldName      = "version"
 )
 
+func init() {
+	dbutils.RegisterTimestamps(tenantResourceKey, dbutils.DefaultTimestampColumns)
+}
+
 func NewTenantModel(name, email string, plan TenantPlan) *tenantModel {
 	return &tenantModel{
 		TenantName:   name,
@@ -58,6 +64,7 @@ func GetTenantById(db *sql.DB, tenantId int64) (*tenantModel, error) {
 		planDbFieldName:         &tenant.Plan,
 		isActiveDbFieldName:     &tenant.IsActive,
 		createdAtDbFieldName:    &tenant.CreatedAt,
+		updatedAtDbFieldName:    &tenant.UpdatedAt,
 		versionDbFieldName:      &tenant.Version,
 	})
 	if err != nil {
@@ -89,7 +96,7 @@ func FindTenants(db *sql.DB, searchTenantsRequest *SearchTenantsRequest) ([]tena
 		AndWhere(fmt.Sprintf("%s = ?", planDbFieldName), searchTenantsRequest.Plan).
 		AndWhere(fmt.Sprintf("%s = ?", isActiveDbFieldName), searchTenantsRequest.IsActive).
 		AndWhereLike(contactEmailDbFieldName, dbutils.OpContains, searchTenantsRequest.ContactEmail).
-		OrderBy(searchTenantsRequest.Sort).
+		OrderBySpec(searchTenantsRequest.SortSpec).
 		Page(searchTenantsRequest.Page, searchTenantsRequest.PageSize).
 		Execute(func(rows *sql.Rows) error {
 			var tenant tenantModel
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/parser"
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+// TenantRecord is the tenant model served by the resource controller returned by NewTenantResourceController.
+type TenantRecord struct {
+	ID           int64      `json:"id"`
+	TenantName   string     `json:"tenantName"`
+	ContactEmail string     `json:"contactEmail"`
+	Plan         TenantPlan `json:"plan"`
+	IsActive     bool       `json:"isActive"`
+	Version      int32      `json:"version"`
+}
+
+// NewTenantResourceController expresses the tenant CRUD endpoints with httputils.NewResourceController
+// instead of hand-written handlers.
+func NewTenantResourceController(db *sql.DB) *httputils.ResourceController[TenantRecord] {
+	return httputils.NewResourceController(db, httputils.Resource[TenantRecord]{
+		Name:  tenantResourceKey,
+		Table: tenantResourceKey,
+		Path:  "/tenants",
+		Columns: func(tenant *TenantRecord) map[string]any {
+			return map[string]any{
+				tenantIdDbFieldName:     &tenant.ID,
+				tenantNameDbFieldName:   &tenant.TenantName,
+				contactEmailDbFieldName: &tenant.ContactEmail,
+				planDbFieldName:         &tenant.Plan,
+				isActiveDbFieldName:     &tenant.IsActive,
+				versionDbFieldName:      &tenant.Version,
+			}
+		},
+		Values: func(tenant *TenantRecord) map[string]any {
+			return map[string]any{
+				tenantNameDbFieldName:   tenant.TenantName,
+				contactEmailDbFieldName: tenant.ContactEmail,
+				planDbFieldName:         tenant.Plan,
+				isActiveDbFieldName:     tenant.IsActive,
+			}
+		},
+		Keys: func(tenant *TenantRecord) (*int64, *int32) {
+			return &tenant.ID, &tenant.Version
+		},
+		New: func() TenantRecord {
+			return TenantRecord{IsActive: true}
+		},
+		Validate: func(v *validation.Validator, tenant *TenantRecord) {
+			v.Required(tenant.TenantName, tenantNameRequestKey, "Tenant Name is required")
+			v.MaxLength(tenant.TenantName, maxTenantNameLength, tenantNameRequestKey, fmt.Sprintf("Tenant Name must be at most %d characters", maxTenantNameLength))
+			v.Email(tenant.ContactEmail, contactEmailRequestKey, "Contact Email is invalid")
+			checkPlan(v, &tenant.Plan)
+		},
+		MapError: func(err error) error {
+			return dbutils.MapUniqueConstraint(err, tenantUniqueConstraints)
+		},
+		Filter: func(queryString url.Values, query *dbutils.QueryBuilder) {
+			query.AndWhere(fmt.Sprintf("%s = ?", planDbFieldName), parser.ParseQSString(queryString, planRequestKey, nil))
+			query.AndWhere(fmt.Sprintf("%s = ?", isActiveDbFieldName), parser.ParseQSBool(queryString, "isActive", nil))
+		},
+		SortSafeList: []string{"id", tenantNameRequestKey},
+	})
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func TestTenantResourceController(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	router := testutils.NewRouter()
+	NewTenantResourceController(db).Routes(router)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := serve(testutils.CreatePostRequest(t, "/tenants", map[string]any{
+		"tenantName":   "Globex",
+		"contactEmail": "admin@globex.com",
+		"plan":         "PAID",
+	}))
+	testutils.AssertStatus(t, rr, http.StatusCreated)
+
+	if location := rr.Header().Get("Location"); location != "/tenants/3" {
+		t.Errorf("Expected Location /tenants/3, got %q", location)
+	}
+
+	var tenant TenantRecord
+
+	rr = serve(testutils.CreateGetRequest("/tenants/3"))
+	testutils.AssertStatus(t, rr, http.StatusOK)
+	testutils.AssertJSONBody(t, rr, &tenant)
+
+	expected := TenantRecord{
+		ID: 3, TenantName: "Globex", ContactEmail: "admin@globex.com", Plan: Paid, IsActive: true, Version: 1,
+	}
+	if tenant != expected {
+		t.Errorf("Expected %+v, got %+v", expected, tenant)
+	}
+
+	rr = serve(testutils.CreatePatchRequest(t, "/tenants/3", map[string]any{"plan": "free", "version": 99}))
+	testutils.AssertStatus(t, rr, http.StatusOK)
+	testutils.AssertJSONBody(t, rr, &tenant)
+
+	expected.Plan, expected.Version = Free, 2
+	if tenant != expected {
+		t.Errorf("Expected %+v, got %+v", expected, tenant)
+	}
+
+	var list httputils.Page[TenantRecord]
+
+	rr = serve(testutils.CreateGetRequest("/tenants?plan=free&sort=-id"))
+	testutils.AssertStatus(t, rr, http.StatusOK)
+	testutils.AssertJSONBody(t, rr, &list)
+
+	if len(list.Items) != 2 || list.TotalItems != 2 {
+		t.Fatalf("Expected 2 free tenants, got %+v", list)
+	}
+
+	if list.Items[0].ID != 3 {
+		t.Errorf("Expected tenant 3 first, got %d", list.Items[0].ID)
+	}
+
+	rr = serve(testutils.CreateDeleteRequest("/tenants/3"))
+	testutils.AssertStatus(t, rr, http.StatusOK)
+
+	rr = serve(testutils.CreateGetRequest("/tenants/3"))
+	testutils.AssertStatus(t, rr, http.StatusNotFound)
+}
+
+func TestTenantResourceController_InvalidRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		request        func(t *testing.T) *http.Request
+		expectedStatus int
+		expectedField  string
+		expectedError  string
+	}{
+		{
+			name: "duplicate tenant name",
+			request: func(t *testing.T) *http.Request {
+				t.Helper()
+
+				return testutils.CreatePostRequest(t, "/tenants", map[string]any{
+					"tenantName": "Acme", "contactEmail": "other@acme.com", "plan": "free",
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedField:  tenantNameRequestKey,
+			expectedError:  "This tenant is already registered",
+		},
+		{
+			name: "invalid plan",
+			request: func(t *testing.T) *http.Request {
+				t.Helper()
+
+				return testutils.CreatePostRequest(t, "/tenants", map[string]any{
+					"tenantName": "Globex", "contactEmail": "admin@globex.com", "plan": "gold",
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedField:  planRequestKey,
+			expectedError:  "Invalid plan",
+		},
+		{
+			name: "empty tenant name on update",
+			request: func(t *testing.T) *http.Request {
+				t.Helper()
+
+				return testutils.CreatePatchRequest(t, "/tenants/1", map[string]any{"tenantName": ""})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedField:  tenantNameRequestKey,
+			expectedError:  "Tenant Name is required",
+		},
+		{
+			name: "invalid sort",
+			request: func(t *testing.T) *http.Request {
+				t.Helper()
+
+				return testutils.CreateGetRequest("/tenants?sort=plan")
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedField:  "sort",
+		},
+		{
+			name: "malformed id",
+			request: func(t *testing.T) *http.Request {
+				t.Helper()
+
+				return testutils.CreateGetRequest("/tenants/abc")
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "missing tenant",
+			request: func(t *testing.T) *http.Request {
+				t.Helper()
+
+				return testutils.CreateGetRequest("/tenants/9999")
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			db := testutils.SetupTestDB(t)
+			defer func() {
+				closeErr := db.Close()
+				if closeErr != nil {
+					t.Fatalf("Failed to close database connection: %v", closeErr)
+				}
+			}()
+
+			router := testutils.NewRouter()
+			NewTenantResourceController(db).Routes(router)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, tt.request(t))
+			testutils.AssertStatus(t, rr, tt.expectedStatus)
+
+			if tt.expectedField == "" {
+				return
+			}
+
+			var resp map[string]any
+
+			testutils.AssertJSONBody(t, rr, &resp)
+
+			if tt.expectedError == "" {
+				testutils.AssertErrorCount(t, resp, 1)
+
+				return
+			}
+
+			testutils.AssertErrorContains(t, resp, tt.expectedField, tt.expectedError)
+		})
+	}
+}
@@ -1,29 +1,24 @@
 package authutils
 
 import (
-	"context"
 	"net/http"
-)
-
-type contextKey string
 
-const userContextKey = contextKey("user")
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
 
 // The ContextSetUser() method returns a new copy of the request with the provided
-// User struct added to the context. Note that we use our userContextKey constant as the
-// key.
+// User struct added to the context. Tests can populate it with testutils.WithAuthUser.
 func ContextSetUser[T any](r *http.Request, user T) *http.Request {
-	ctx := context.WithValue(r.Context(), userContextKey, user)
-
-	return r.WithContext(ctx)
+	return httputils.ContextSetUser(r, user)
 }
 
 // The ContextGetUser() retrieves the User struct from the request context. The only
 // time that we'll use this helper is when we logically expect there to be User struct
 // value in the context, and if it doesn't exist it will firmly be an 'unexpected' error.
 // As we discussed earlier in the book, it's OK to panic in those circumstances.
-func ContextGetUser[T any](r *http.Request) T {
-	user, ok := r.Context().Value(userContextKey).(T)
+// Use httputils.ContextGetUser where the user is optional.
+func ContextGetUser[T any](r *http.Request) T { //nolint: ireturn
+	user, ok := httputils.ContextGetUser[T](r)
 	if !ok {
 		panic("missing or invalid user value in request context")
 	}
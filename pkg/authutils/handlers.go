@@ -38,6 +38,7 @@ type OidcController[T any] struct {
 	oauth2Config      *oauth2Config
 	getOrCreateUserFn GetOrCreateUser[T]
 	sessionManager    *scs.SessionManager
+	encryptionKey     []byte
 }
 
 type oauth2Config struct {
@@ -94,33 +95,42 @@ func createOauthConfig(
 
 type GetOrCreateUser[T any] func(ctx context.Context, email string, inviteTokenPayload map[string]any) (T, error)
 
+// CreateOidcController creates an OidcController from the OIDC env vars. encryptionKey, e.g. from
+// GetEncryptionKey, encrypts the state and verifies invite tokens.
 func CreateOidcController[T any](
 	sessionManager *scs.SessionManager,
 	getOrCreateUserFn GetOrCreateUser[T],
+	encryptionKey []byte,
 ) *OidcController[T] {
 	config, err := createOauthConfig(
-		parser.ParseEnvStringPanic("OIDC_CLIENT_ID"),
-		parser.ParseEnvStringPanic("OIDC_CLIENT_SECRET"),
-		parser.ParseEnvStringPanic("OIDC_DISCOVERY_URL"),
-		parser.ParseEnvStringPanic("REGISTRATION_URL"),
-		parser.ParseEnvStringPanic("LOGOUT_URL"),
-		parser.ParseEnvStringPanic("POST_LOGOUT_REDIRECT_URL"),
-		parser.ParseEnvStringPanic("OIDC_REDIRECT_URL"),
+		parser.MustParseEnvString("OIDC_CLIENT_ID"),
+		parser.MustParseEnvStringOrFile("OIDC_CLIENT_SECRET"),
+		parser.MustParseEnvString("OIDC_DISCOVERY_URL"),
+		parser.MustParseEnvString("REGISTRATION_URL"),
+		parser.MustParseEnvString("LOGOUT_URL"),
+		parser.MustParseEnvString("POST_LOGOUT_REDIRECT_URL"),
+		parser.MustParseEnvString("OIDC_REDIRECT_URL"),
 	)
 	if err != nil {
 		slog.Error("failed to create oauth config", "error", err)
 		panic(err)
 	}
 
-	return &OidcController[T]{sessionManager: sessionManager, getOrCreateUserFn: getOrCreateUserFn, oauth2Config: config}
+	return NewOidcController(sessionManager, getOrCreateUserFn, config, encryptionKey)
 }
 
 func NewOidcController[T any](
 	sessionManager *scs.SessionManager,
 	fn GetOrCreateUser[T],
 	config *oauth2Config,
+	encryptionKey []byte,
 ) *OidcController[T] {
-	return &OidcController[T]{sessionManager: sessionManager, getOrCreateUserFn: fn, oauth2Config: config}
+	return &OidcController[T]{
+		sessionManager:    sessionManager,
+		getOrCreateUserFn: fn,
+		oauth2Config:      config,
+		encryptionKey:     encryptionKey,
+	}
 }
 
 func (c *OidcController[T]) PublicRoutes(r httputils.Router) {
@@ -140,7 +150,7 @@ func (c *OidcController[T]) redirectToAuthURL(w http.ResponseWriter, r *http.Req
 
 	payload["state"] = uuid.New().String()
 
-	state, err := Encrypt(payload)
+	state, err := Encrypt(c.encryptionKey, payload)
 	if err != nil {
 		httputils.ServerErrorResponse(w, r, fmt.Errorf("failed to encrypt state: %w", err))
 	}
@@ -175,7 +185,7 @@ func (c *OidcController[T]) registerHandler(w http.ResponseWriter, r *http.Reque
 	invite := parser.ParseQSString(r.URL.Query(), "invite", &defaultInvite)
 
 	if *invite != "" {
-		_, err := VerifyInviteToken(*invite)
+		_, err := VerifyInviteToken(c.encryptionKey, *invite)
 		if err != nil {
 			httputils.BadRequestResponse(w, r, ErrInvalidInviteToken)
 		}
@@ -183,7 +193,7 @@ func (c *OidcController[T]) registerHandler(w http.ResponseWriter, r *http.Reque
 		payload["invite"] = invite
 	}
 
-	state, err := Encrypt(payload)
+	state, err := Encrypt(c.encryptionKey, payload)
 	if err != nil {
 		httputils.ServerErrorResponse(w, r, fmt.Errorf("failed to encrypt state: %w", err))
 	}
@@ -216,7 +226,7 @@ func (c *OidcController[T]) registerHandler(w http.ResponseWriter, r *http.Reque
 }
 
 func (c *OidcController[T]) authCallback(w http.ResponseWriter, r *http.Request) {
-	state, err := verifyState(w, r)
+	state, err := verifyState(w, r, c.encryptionKey)
 	if err != nil {
 		slog.Info("failed to verify state", "error", err)
 		http.Redirect(w, r, "/login", http.StatusTemporaryRedirect)
@@ -246,7 +256,7 @@ func (c *OidcController[T]) authCallback(w http.ResponseWriter, r *http.Request)
 
 	invite, ok := state["invite"].(string)
 	if ok {
-		payload, err = VerifyInviteToken(invite)
+		payload, err = VerifyInviteToken(c.encryptionKey, invite)
 		if err != nil {
 			httputils.ServerErrorResponse(w, r, fmt.Errorf("failed to verify invite token: %w", err))
 
@@ -298,7 +308,7 @@ func (c *OidcController[T]) logoutHandler(w http.ResponseWriter, r *http.Request
 	http.Redirect(w, r, logoutURL, http.StatusSeeOther)
 }
 
-func verifyState(w http.ResponseWriter, r *http.Request) (map[string]any, error) {
+func verifyState(w http.ResponseWriter, r *http.Request, encryptionKey []byte) (map[string]any, error) {
 	//nolint: exhaustruct
 	cookie := &http.Cookie{
 		Name:     "state",
@@ -326,7 +336,7 @@ func verifyState(w http.ResponseWriter, r *http.Request) (map[string]any, error)
 		return nil, fmt.Errorf("state mismatch: %w", ErrInvalidState)
 	}
 
-	payload, err := Decrypt(state)
+	payload, err := Decrypt(encryptionKey, state)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt state: %w", err)
 	}
@@ -22,16 +22,44 @@ var ErrInviteExpiresAt = errors.New("invite token has expired")
 
 var ErrInvalidPayload = errors.New("invalid payload")
 
+var ErrInvalidEncryptionKey = errors.New("ENCRYPTION_KEY must be 16, 24 or 32 bytes")
+
 const inviteTokenExpiresAt = time.Hour * 24 * 7
 
-// Encrypt a map[string]any.
-func Encrypt(data map[string]any) (string, error) {
+// GetEncryptionKey returns the key configured by ENCRYPTION_KEY or the file named by ENCRYPTION_KEY_FILE.
+// It should be resolved once at startup and passed to Encrypt and Decrypt, so that a missing or invalid key
+// stops the application from starting rather than failing requests.
+func GetEncryptionKey() ([]byte, error) {
+	key, err := parser.ParseEnvStringOrFile("ENCRYPTION_KEY", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key: %w", err)
+	}
+
+	switch len(key) {
+	case 16, 24, 32: //nolint: mnd
+		return []byte(key), nil
+	default:
+		return nil, fmt.Errorf("%w, got %d bytes", ErrInvalidEncryptionKey, len(key))
+	}
+}
+
+// MustGetEncryptionKey returns the key configured by ENCRYPTION_KEY like GetEncryptionKey. It panics if the
+// key is missing or invalid.
+func MustGetEncryptionKey() []byte {
+	key, err := GetEncryptionKey()
+	if err != nil {
+		panic(err)
+	}
+
+	return key
+}
+
+// Encrypt a map[string]any with key.
+func Encrypt(key []byte, data map[string]any) (string, error) {
 	if data == nil {
 		return "", ErrInvalidPayload
 	}
 
-	key := []byte(parser.ParseEnvStringPanic("ENCRYPTION_KEY"))
-
 	// Serialize the map to JSON
 	plaintext, err := json.Marshal(data)
 	if err != nil {
@@ -62,10 +90,8 @@ func Encrypt(data map[string]any) (string, error) {
 	return base64.RawURLEncoding.EncodeToString(ciphertext), nil
 }
 
-// Decrypt a string that was encrypted with Encrypt.
-func Decrypt(ciphertext string) (map[string]any, error) {
-	key := []byte(parser.ParseEnvStringPanic("ENCRYPTION_KEY"))
-
+// Decrypt a string that was encrypted with Encrypt using the same key.
+func Decrypt(key []byte, ciphertext string) (map[string]any, error) {
 	ciphertextBytes, err := base64.RawURLEncoding.DecodeString(ciphertext)
 	if err != nil {
 		return nil, fmt.Errorf("%w: base64 decode failed: %w", ErrDecryption, err)
@@ -105,7 +131,7 @@ func Decrypt(ciphertext string) (map[string]any, error) {
 	return data, nil
 }
 
-func CreateInviteToken(payload map[string]any) (string, error) {
+func CreateInviteToken(key []byte, payload map[string]any) (string, error) {
 	inviteTokenPayload := make(map[string]any)
 
 	for key, value := range payload {
@@ -114,7 +140,7 @@ func CreateInviteToken(payload map[string]any) (string, error) {
 
 	inviteTokenPayload["expires_at"] = time.Now().UTC().Add(inviteTokenExpiresAt)
 
-	encrypted, err := Encrypt(inviteTokenPayload)
+	encrypted, err := Encrypt(key, inviteTokenPayload)
 	if err != nil {
 		return "", fmt.Errorf("failed to encrypt invite token: %w", err)
 	}
@@ -122,8 +148,8 @@ func CreateInviteToken(payload map[string]any) (string, error) {
 	return encrypted, nil
 }
 
-func VerifyInviteToken(token string) (map[string]any, error) {
-	decrypted, err := Decrypt(token)
+func VerifyInviteToken(key []byte, token string) (map[string]any, error) {
+	decrypted, err := Decrypt(key, token)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt invite token: %w", err)
 	}
@@ -1,21 +1,15 @@
 package authutils_test
 
 import (
-	"os"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/gurch101/gowebutils/pkg/authutils"
 )
 
-func TestMain(m *testing.M) {
-	// Set test encryption key
-	os.Setenv("ENCRYPTION_KEY", "0123456789ABCDEF0123456789ABCDEF")
-
-	code := m.Run()
-
-	os.Exit(code)
-}
+//nolint:gochecknoglobals
+var testEncryptionKey = []byte("0123456789ABCDEF0123456789ABCDEF")
 
 func TestEncryptDecrypt(t *testing.T) {
 	tests := []struct {
@@ -45,7 +39,7 @@ func TestEncryptDecrypt(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			encrypted, err := authutils.Encrypt(tt.data)
+			encrypted, err := authutils.Encrypt(testEncryptionKey, tt.data)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Encrypt() error = %v, wantErr %v", err, tt.wantErr)
 
@@ -56,7 +50,7 @@ func TestEncryptDecrypt(t *testing.T) {
 				return
 			}
 
-			decrypted, err := authutils.Decrypt(encrypted)
+			decrypted, err := authutils.Decrypt(testEncryptionKey, encrypted)
 			if err != nil {
 				t.Errorf("Decrypt() error = %v", err)
 
@@ -93,7 +87,7 @@ func TestDecrypt_Invalid(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := authutils.Decrypt(tt.ciphertext)
+			_, err := authutils.Decrypt(testEncryptionKey, tt.ciphertext)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -124,7 +118,7 @@ func TestInviteToken(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			token, err := authutils.CreateInviteToken(tt.payload)
+			token, err := authutils.CreateInviteToken(testEncryptionKey, tt.payload)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateInviteToken() error = %v, wantErr %v", err, tt.wantErr)
 
@@ -136,7 +130,7 @@ func TestInviteToken(t *testing.T) {
 			}
 
 			// Verify token
-			decoded, err := authutils.VerifyInviteToken(token)
+			decoded, err := authutils.VerifyInviteToken(testEncryptionKey, token)
 			if err != nil {
 				t.Errorf("VerifyInviteToken() error = %v", err)
 
@@ -158,33 +152,51 @@ func TestInviteToken_Expiration(t *testing.T) {
 		"user_id": 123,
 	}
 
-	token, err := authutils.CreateInviteToken(payload)
+	token, err := authutils.CreateInviteToken(testEncryptionKey, payload)
 	if err != nil {
 		t.Fatalf("CreateInviteToken() error = %v", err)
 	}
 
 	// Verify valid token
-	_, err = authutils.VerifyInviteToken(token)
+	_, err = authutils.VerifyInviteToken(testEncryptionKey, token)
 	if err != nil {
 		t.Errorf("VerifyInviteToken() error = %v", err)
 	}
 
 	// Modify expiration time to test expired token
-	decoded, err := authutils.Decrypt(token)
+	decoded, err := authutils.Decrypt(testEncryptionKey, token)
 	if err != nil {
 		t.Fatalf("Decrypt() error = %v", err)
 	}
 
 	decoded["expires_at"] = time.Now().UTC().Add(-time.Hour)
 
-	expired, err := authutils.Encrypt(decoded)
+	expired, err := authutils.Encrypt(testEncryptionKey, decoded)
 	if err != nil {
 		t.Fatalf("Encrypt() error = %v", err)
 	}
 
 	// Verify expired token
-	_, err = authutils.VerifyInviteToken(expired)
+	_, err = authutils.VerifyInviteToken(testEncryptionKey, expired)
 	if err == nil {
 		t.Error("VerifyInviteToken() expected error for expired token")
 	}
 }
+
+//nolint:paralleltest // sets environment variables
+func TestGetEncryptionKey(t *testing.T) {
+	t.Setenv("ENCRYPTION_KEY", string(testEncryptionKey))
+
+	key, err := authutils.GetEncryptionKey()
+	if err != nil || string(key) != string(testEncryptionKey) {
+		t.Errorf("GetEncryptionKey() = %q, %v", key, err)
+	}
+
+	for _, invalid := range []string{"", "too-short"} {
+		t.Setenv("ENCRYPTION_KEY", invalid)
+
+		if _, err := authutils.GetEncryptionKey(); !errors.Is(err, authutils.ErrInvalidEncryptionKey) {
+			t.Errorf("GetEncryptionKey() with %q: expected ErrInvalidEncryptionKey, got %v", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DefaultAuditTable is the table created by the audit_log migration.
+const DefaultAuditTable = "audit_log"
+
+// AuditChange is the value of a field before and after a change. Old is nil for inserted records and New is
+// nil for deleted records.
+type AuditChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// AuditEntry describes a change to a single record.
+type AuditEntry struct {
+	Actor     string
+	Table     string
+	RecordID  int64
+	Operation Operation
+	Changes   map[string]AuditChange
+}
+
+// AuditLog records who changed which record, how and which fields changed, for every record inserted with
+// Insert, updated with UpdateByID or deleted with DeleteByID through a DB wrapped with NewAuditDB. Updates
+// record only the fields whose values changed and deletes record every field of the deleted record. Entries are
+// written with the same DB as the change, so changes made in a transaction are only audited if the transaction
+// commits. When the helpers are called with an audited *sql.DB, the old values are read, the change is made and
+// the entry is written in a transaction of their own, so that a change is never committed without its entry
+// and concurrent writers cannot change the record between reading its old values and changing it.
+// DeleteByField is not audited since it deletes records without knowing their IDs.
+type AuditLog struct {
+	// Table is the table entries are written to. Defaults to DefaultAuditTable.
+	Table string
+	// Actor returns who is making a change, e.g. the ID of the user stored in the request context with
+	// httputils.ContextSetUser. Entries have an empty actor if Actor is nil.
+	Actor func(ctx context.Context) string
+}
+
+// AuditDB is a DB whose changes made with the dbutils mutation helpers are recorded by Log. Other queries are
+// run with the wrapped DB as they are.
+type AuditDB struct {
+	DB
+	Log *AuditLog
+}
+
+// NewAuditDB returns db with auditing by log, e.g.
+//
+//	auditedDB := dbutils.NewAuditDB(db, &dbutils.AuditLog{Actor: actorFromContext})
+//	dbutils.UpdateByID(ctx, auditedDB, "tenants", id, version, fields)
+//
+// db may be a *sql.DB or a transaction. Changes made with an unwrapped DB are not audited.
+func NewAuditDB(db DB, log *AuditLog) *AuditDB {
+	return &AuditDB{DB: db, Log: log}
+}
+
+// auditLogOf returns the AuditLog of db and the DB it wraps, or a nil AuditLog and db if it is not audited.
+func auditLogOf(db DB) (*AuditLog, DB) {
+	if auditDB, ok := db.(*AuditDB); ok {
+		return auditDB.Log, auditDB.DB
+	}
+
+	return nil, db
+}
+
+// Record writes entry to the audit table. If entry has no actor, it is set with Actor.
+func (l *AuditLog) Record(ctx context.Context, db DB, entry AuditEntry) error {
+	if entry.Actor == "" && l.Actor != nil {
+		entry.Actor = l.Actor(ctx)
+	}
+
+	if entry.Changes == nil {
+		entry.Changes = map[string]AuditChange{}
+	}
+
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+
+	table := l.Table
+	if table == "" {
+		table = DefaultAuditTable
+	}
+
+	// #nosec G201
+	query := fmt.Sprintf(
+		"INSERT INTO %s (actor, table_name, record_id, operation, changes) VALUES (?, ?, ?, ?, ?)", table,
+	)
+	args := []any{entry.Actor, entry.Table, entry.RecordID, string(entry.Operation), string(changes)}
+
+	start := time.Now()
+	_, err = db.ExecContext(ctx, query, args...)
+	observeQuery(ctx, OperationInsert, query, args, start, err)
+
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", WrapDBError(err))
+	}
+
+	return nil
+}
+
+// withAuditTransaction calls fn with a transaction on db if log is not nil and db is a *sql.DB, so that the
+// audit snapshot, the change and its audit entry are committed together. Otherwise fn is called with db, which
+// may already be a transaction.
+func withAuditTransaction(ctx context.Context, log *AuditLog, db DB, fn func(db DB) error) error {
+	sqlDB, ok := db.(*sql.DB)
+	if !ok || log == nil {
+		return fn(db)
+	}
+
+	return WithTransaction(ctx, sqlDB, func(tx *sql.Tx) error {
+		return fn(tx)
+	})
+}
+
+// auditSnapshot returns the columns of the record with the given id and, if version is non-negative, version,
+// so that their old values can be audited. All columns are returned if columns is empty. The snapshot is nil
+// if log is nil or the record does not exist.
+func auditSnapshot(
+	ctx context.Context, log *AuditLog, db DB, tableName string, id int64, version int32, columns []string,
+) (map[string]any, error) {
+	if log == nil {
+		return nil, nil //nolint: nilnil
+	}
+
+	selectClause := "*"
+	if len(columns) > 0 {
+		selectClause = strings.Join(columns, ", ")
+	}
+
+	// #nosec G201
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ?", selectClause, tableName)
+	args := []any{id}
+
+	if version >= 0 {
+		query += " AND version = ?"
+		args = append(args, version)
+	}
+
+	start := time.Now()
+	rows, err := db.QueryContext(ctx, query, args...)
+
+	var snapshot []map[string]any
+
+	if err == nil {
+		defer rows.Close()
+
+		columns, err = rows.Columns()
+		if err == nil {
+			snapshot, err = scanRowMaps(rows, columns, 1)
+		}
+	}
+
+	observeQuery(ctx, OperationGet, query, args, start, err)
+
+	if err != nil {
+		return nil, WrapDBError(err)
+	}
+
+	if len(snapshot) == 0 {
+		return nil, nil //nolint: nilnil
+	}
+
+	return snapshot[0], nil
+}
+
+// auditInsert records the fields of an inserted record with log, if it is not nil.
+func auditInsert(
+	ctx context.Context, log *AuditLog, db DB, tableName string, id int64, fields map[string]any,
+) error {
+	changes := make(map[string]AuditChange, len(fields))
+	for field, value := range fields {
+		changes[field] = AuditChange{Old: nil, New: auditValue(value)}
+	}
+
+	return recordAudit(ctx, log, db, tableName, id, OperationInsert, changes)
+}
+
+// auditUpdate records the fields of an updated record whose values differ from those in snapshot with log, if
+// it is not nil.
+func auditUpdate(
+	ctx context.Context, log *AuditLog, db DB, tableName string, id int64, snapshot, fields map[string]any,
+) error {
+	changes := make(map[string]AuditChange, len(fields))
+
+	for field, value := range fields {
+		oldValue, newValue := auditValue(snapshot[field]), auditValue(value)
+		if !auditValuesEqual(oldValue, newValue) {
+			changes[field] = AuditChange{Old: oldValue, New: newValue}
+		}
+	}
+
+	return recordAudit(ctx, log, db, tableName, id, OperationUpdate, changes)
+}
+
+// auditDelete records the fields of a deleted record from snapshot with log, if it is not nil.
+func auditDelete(
+	ctx context.Context, log *AuditLog, db DB, tableName string, id int64, snapshot map[string]any,
+) error {
+	changes := make(map[string]AuditChange, len(snapshot))
+	for field, value := range snapshot {
+		changes[field] = AuditChange{Old: auditValue(value), New: nil}
+	}
+
+	return recordAudit(ctx, log, db, tableName, id, OperationDelete, changes)
+}
+
+func recordAudit(
+	ctx context.Context,
+	log *AuditLog,
+	db DB,
+	tableName string,
+	id int64,
+	operation Operation,
+	changes map[string]AuditChange,
+) error {
+	if log == nil {
+		return nil
+	}
+
+	return log.Record(ctx, db, AuditEntry{
+		Actor:     "",
+		Table:     tableName,
+		RecordID:  id,
+		Operation: operation,
+		Changes:   changes,
+	})
+}
+
+// auditValue converts value to the type it is stored as, e.g. a named string type or driver.Valuer to a
+// string, so that old and new values can be compared and encoded consistently.
+func auditValue(value any) any {
+	converted, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil {
+		return value
+	}
+
+	if b, ok := converted.([]byte); ok {
+		return string(b)
+	}
+
+	return converted
+}
+
+func auditValuesEqual(a, b any) bool {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+
+		return ok && ta.Equal(tb)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,266 @@
+package dbutils_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+type auditActorKey struct{}
+
+type auditRow struct {
+	Actor     string
+	Table     string
+	RecordID  int64
+	Operation string
+	Changes   map[string]dbutils.AuditChange
+}
+
+func newAuditDB(db dbutils.DB) *dbutils.AuditDB {
+	return dbutils.NewAuditDB(db, &dbutils.AuditLog{
+		Table: dbutils.DefaultAuditTable,
+		Actor: func(ctx context.Context) string {
+			actor, _ := ctx.Value(auditActorKey{}).(string)
+
+			return actor
+		},
+	})
+}
+
+func auditRows(t *testing.T, db *sql.DB) []auditRow {
+	t.Helper()
+
+	rows, err := db.Query("SELECT actor, table_name, record_id, operation, changes FROM audit_log ORDER BY id")
+	if err != nil {
+		t.Fatalf("failed to query audit log: %v", err)
+	}
+	defer rows.Close()
+
+	var result []auditRow
+
+	for rows.Next() {
+		var (
+			row     auditRow
+			changes string
+		)
+
+		if err := rows.Scan(&row.Actor, &row.Table, &row.RecordID, &row.Operation, &changes); err != nil {
+			t.Fatalf("failed to scan audit row: %v", err)
+		}
+
+		if err := json.Unmarshal([]byte(changes), &row.Changes); err != nil {
+			t.Fatalf("failed to decode audit changes %q: %v", changes, err)
+		}
+
+		result = append(result, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+
+	return result
+}
+
+func TestAuditLog_UpdateByID(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	ctx := context.WithValue(context.Background(), auditActorKey{}, "admin")
+
+	err := dbutils.UpdateByID(ctx, newAuditDB(db), "users", 2, 1, map[string]any{
+		"user_name": "johnny",
+		"email":     "john@acme.com",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []auditRow{{
+		Actor:     "admin",
+		Table:     "users",
+		RecordID:  2,
+		Operation: "update",
+		Changes:   map[string]dbutils.AuditChange{"user_name": {Old: "john", New: "johnny"}},
+	}}
+
+	if rows := auditRows(t, db); !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected audit rows %+v, got %+v", expected, rows)
+	}
+}
+
+func TestAuditLog_UpdateByIDEditConflict(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	err := dbutils.UpdateByID(context.Background(), newAuditDB(db), "users", 2, 5, map[string]any{"user_name": "johnny"})
+	if err == nil {
+		t.Fatal("expected an edit conflict")
+	}
+
+	if rows := auditRows(t, db); len(rows) != 0 {
+		t.Errorf("expected no audit rows for a failed update, got %+v", rows)
+	}
+}
+
+func TestAuditLog_InsertAndDelete(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	auditDB := newAuditDB(db)
+
+	id, err := dbutils.Insert(context.Background(), auditDB, "users", map[string]any{
+		"user_name": "jane",
+		"email":     "jane@acme.com",
+		"tenant_id": 1,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := dbutils.DeleteByID(context.Background(), auditDB, "users", *id); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	rows := auditRows(t, db)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 audit rows, got %+v", rows)
+	}
+
+	inserted := map[string]dbutils.AuditChange{
+		"user_name": {Old: nil, New: "jane"},
+		"email":     {Old: nil, New: "jane@acme.com"},
+		"tenant_id": {Old: nil, New: float64(1)},
+	}
+	if rows[0].Operation != "insert" || rows[0].RecordID != *id || !reflect.DeepEqual(rows[0].Changes, inserted) {
+		t.Errorf("expected insert audit row with changes %+v, got %+v", inserted, rows[0])
+	}
+
+	deleted := rows[1].Changes
+	if rows[1].Operation != "delete" || deleted["user_name"] != (dbutils.AuditChange{Old: "jane", New: nil}) {
+		t.Errorf("expected delete audit row with the deleted user, got %+v", rows[1])
+	}
+}
+
+func TestAuditLog_Disabled(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	err := dbutils.UpdateByID(context.Background(), db, "users", 2, 1, map[string]any{"user_name": "johnny"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if rows := auditRows(t, db); len(rows) != 0 {
+		t.Errorf("expected no audit rows, got %+v", rows)
+	}
+}
+
+func TestAuditLog_FailedEntryRollsBackChange(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	auditDB := dbutils.NewAuditDB(db, &dbutils.AuditLog{Table: "missing_audit_log", Actor: nil})
+
+	_, err := dbutils.Insert(context.Background(), auditDB, "users", map[string]any{
+		"user_name": "jane",
+		"email":     "jane@acme.com",
+		"tenant_id": 1,
+	})
+	if err == nil {
+		t.Fatal("expected an error when the audit entry cannot be written")
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT count(*) FROM users WHERE user_name = ?", "jane").Scan(&count); err != nil {
+		t.Fatalf("failed to count users: %v", err)
+	}
+
+	if count != 0 {
+		t.Errorf("expected the insert to be rolled back, got %d users", count)
+	}
+
+	err = dbutils.UpdateByID(context.Background(), auditDB, "users", 2, 1, map[string]any{"user_name": "johnny"})
+	if err == nil {
+		t.Fatal("expected an error when the audit entry cannot be written")
+	}
+
+	if err := db.QueryRow("SELECT count(*) FROM users WHERE id = 2 AND version = 1").Scan(&count); err != nil {
+		t.Fatalf("failed to count users: %v", err)
+	}
+
+	if count != 1 {
+		t.Errorf("expected the update to be rolled back")
+	}
+}
+
+func TestAuditLog_Transaction(t *testing.T) {
+	t.Parallel()
+
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	err := dbutils.WithTransaction(context.Background(), db, func(tx *sql.Tx) error {
+		return dbutils.UpdateByID(context.Background(), newAuditDB(tx), "users", 2, 1, map[string]any{
+			"user_name": "johnny",
+		})
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if rows := auditRows(t, db); len(rows) != 1 || rows[0].Operation != "update" {
+		t.Errorf("expected an update audit row, got %+v", rows)
+	}
+}
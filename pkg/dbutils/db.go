@@ -5,8 +5,12 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"time"
 )
 
+// DefaultHealthCheckTimeout is the time HealthCheck waits for the database to respond.
+const DefaultHealthCheckTimeout = 2 * time.Second
+
 // DB is an interface that both sql.DB and sql.Tx satisfy.
 type DB interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
@@ -42,9 +46,9 @@ func WithTransaction(ctx context.Context, db *sql.DB, callback func(tx *sql.Tx)
 	return nil
 }
 
-// Open opens a SQLite database file.
+// Open opens a SQLite database file, applying the pragmas returned by GetPragmas to every connection.
 func Open(filepath string) *sql.DB {
-	db, err := sql.Open(SqliteDriverName, filepath+"?_foreign_keys=1&_journal=WAL")
+	db, err := OpenWithPragmas(SqliteDriverName, filepath, GetPragmas()...)
 	if err != nil {
 		panic(err)
 	}
@@ -55,3 +59,24 @@ func Open(filepath string) *sql.DB {
 
 	return db
 }
+
+// HealthCheck verifies the database is reachable by running a trivial query.
+func HealthCheck(ctx context.Context, db DB) error {
+	return HealthCheckWithTimeout(ctx, db, DefaultHealthCheckTimeout)
+}
+
+// HealthCheckWithTimeout verifies the database is reachable by running a trivial query,
+// giving up once the provided timeout elapses.
+func HealthCheckWithTimeout(ctx context.Context, db DB, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var result int
+
+	err := queryRow(ctx, db, OperationSelect, "SELECT 1", nil, &result)
+	if err != nil {
+		return fmt.Errorf("database health check failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package dbutils_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func TestHealthCheck(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	err := dbutils.HealthCheck(context.Background(), db)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestHealthCheck_ClosedDB(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	closeErr := db.Close()
+	if closeErr != nil {
+		t.Fatalf("Failed to close database connection: %v", closeErr)
+	}
+
+	err := dbutils.HealthCheckWithTimeout(context.Background(), db, time.Second)
+	if err == nil {
+		t.Error("Expected error for closed database, got nil")
+	}
+}
@@ -2,13 +2,22 @@ package dbutils
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 )
 
 const deleteTimeout = 3 * time.Second
 
-// DeleteByID deletes a record from the specified table by its ID.
+// ErrEmptyDeleteValue is returned when DeleteByField is called with a nil or empty value, which would
+// otherwise match no rows or, for columns holding empty strings, rows that were not meant to be deleted.
+var ErrEmptyDeleteValue = errors.New("delete value must not be empty")
+
+// DeleteByID deletes a record from the specified table by its ID. Rows in other tables that reference it
+// with ON DELETE CASCADE are deleted with it, as long as the foreign_keys pragma is on, which Open and
+// OpenWithPragmas with DefaultPragmas ensure. Use DeleteWithDependents for tables without cascading foreign keys.
+// The deleted record is audited if db is an AuditDB.
 func DeleteByID(ctx context.Context, db DB, tableName string, id int64) error {
 	if id < 0 {
 		return ErrRecordNotFound
@@ -20,16 +29,88 @@ func DeleteByID(ctx context.Context, db DB, tableName string, id int64) error {
 	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
 	defer cancel()
 
-	result, err := db.ExecContext(ctx, query, id)
-	if err != nil {
-		return WrapDBError(err)
+	log, db := auditLogOf(db)
+
+	return withAuditTransaction(ctx, log, db, func(db DB) error {
+		snapshot, err := auditSnapshot(ctx, log, db, tableName, id, -1, nil)
+		if err != nil {
+			return err
+		}
+
+		start := time.Now()
+		result, err := db.ExecContext(ctx, query, id)
+		observeQuery(ctx, OperationDelete, query, []any{id}, start, err)
+
+		if err != nil {
+			return WrapDBError(err)
+		}
+
+		rowsAffected, _ := result.RowsAffected()
+
+		if rowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+
+		return auditDelete(ctx, log, db, tableName, id, snapshot)
+	})
+}
+
+// DeleteByField deletes the records whose column equals value, e.g. a session by its token, and returns the
+// number of records deleted. Unlike DeleteByID, deleting no records is not an error.
+func DeleteByField(ctx context.Context, db DB, tableName string, column string, value any) (int64, error) {
+	if isNilValue(value) || value == "" {
+		return 0, ErrEmptyDeleteValue
 	}
 
-	rowsAffected, _ := result.RowsAffected()
+	// #nosec G201
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = ?", tableName, column)
 
-	if rowsAffected == 0 {
-		return ErrRecordNotFound
+	ctx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := db.ExecContext(ctx, query, value)
+	observeQuery(ctx, OperationDelete, query, []any{value}, start, err)
+
+	if err != nil {
+		return 0, WrapDBError(err)
 	}
 
-	return nil
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// Dependent is a table whose rows reference a parent record through ForeignKey and must be deleted with it.
+type Dependent struct {
+	Table      string
+	ForeignKey string
+}
+
+// DeleteWithDependents deletes the rows of each dependent that reference the record with the given id, in
+// order, and then the record itself in a single transaction, so that either all of them are deleted or none
+// are. It returns ErrRecordNotFound, deleting nothing, if the record does not exist. Dependents of dependents
+// are not followed; declare them with ON DELETE CASCADE or delete them first.
+func DeleteWithDependents(
+	ctx context.Context, db *sql.DB, tableName string, id int64, dependents []Dependent,
+) error {
+	return WithTransaction(ctx, db, func(tx *sql.Tx) error {
+		for _, dependent := range dependents {
+			// #nosec G201
+			query := fmt.Sprintf("DELETE FROM %s WHERE %s = ?", dependent.Table, dependent.ForeignKey)
+
+			start := time.Now()
+			_, err := tx.ExecContext(ctx, query, id)
+			observeQuery(ctx, OperationDelete, query, []any{id}, start, err)
+
+			if err != nil {
+				return WrapDBError(err)
+			}
+		}
+
+		return DeleteByID(ctx, tx, tableName, id)
+	})
 }
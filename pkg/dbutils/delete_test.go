@@ -2,6 +2,7 @@ package dbutils_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 
@@ -81,3 +82,182 @@ func TestDelete_ErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteByField(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	t.Run("matching value", func(t *testing.T) {
+		deleted, err := dbutils.DeleteByField(context.Background(), db, "users", "email", "john@acme.com")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if deleted != 1 {
+			t.Errorf("Expected 1 record deleted, got %d", deleted)
+		}
+
+		exists, err := dbutils.ExistsBy(context.Background(), db, "users", "email", "john@acme.com")
+		if err != nil || exists {
+			t.Errorf("Expected user to be deleted, got exists %v and error %v", exists, err)
+		}
+
+		if !dbutils.Exists(context.Background(), db, "users", 1) {
+			t.Error("Expected other users to be kept")
+		}
+	})
+
+	t.Run("no matching value", func(t *testing.T) {
+		deleted, err := dbutils.DeleteByField(context.Background(), db, "users", "email", "nobody@acme.com")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if deleted != 0 {
+			t.Errorf("Expected no records deleted, got %d", deleted)
+		}
+	})
+
+	t.Run("empty values", func(t *testing.T) {
+		var nilString *string
+
+		for _, value := range []any{nil, "", nilString} {
+			_, err := dbutils.DeleteByField(context.Background(), db, "users", "email", value)
+			if !errors.Is(err, dbutils.ErrEmptyDeleteValue) {
+				t.Errorf("Expected ErrEmptyDeleteValue for %#v, got %v", value, err)
+			}
+		}
+	})
+}
+
+func countRows(t *testing.T, db *sql.DB, query string, args ...any) int {
+	t.Helper()
+
+	var count int
+	if err := db.QueryRow(query, args...).Scan(&count); err != nil {
+		t.Fatalf("Failed to count rows: %v", err)
+	}
+
+	return count
+}
+
+func TestDeleteByID_CascadesToDependents(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	if countRows(t, db, "SELECT COUNT(*) FROM users WHERE tenant_id = 1") == 0 {
+		t.Fatal("Expected seeded users for tenant 1")
+	}
+
+	if err := dbutils.DeleteByID(context.Background(), db, "tenants", 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if count := countRows(t, db, "SELECT COUNT(*) FROM users WHERE tenant_id = 1"); count != 0 {
+		t.Errorf("Expected the tenant's users to be deleted, got %d", count)
+	}
+}
+
+func TestDeleteWithDependents(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) *sql.DB {
+		t.Helper()
+
+		db := testutils.SetupTestDB(t)
+		t.Cleanup(func() { _ = db.Close() })
+
+		// tenant_notes references tenants without ON DELETE CASCADE, so it blocks deleting a tenant.
+		_, err := db.Exec(`
+			CREATE TABLE tenant_notes (
+				id INTEGER PRIMARY KEY,
+				tenant_id INTEGER NOT NULL REFERENCES tenants (id),
+				note TEXT NOT NULL
+			);
+			INSERT INTO tenant_notes (tenant_id, note) VALUES (1, 'first'), (1, 'second'), (2, 'other');
+		`)
+		if err != nil {
+			t.Fatalf("Failed to create tenant_notes: %v", err)
+		}
+
+		return db
+	}
+
+	notes := dbutils.Dependent{Table: "tenant_notes", ForeignKey: "tenant_id"}
+
+	t.Run("foreign key blocks plain delete", func(t *testing.T) {
+		t.Parallel()
+		db := setup(t)
+
+		err := dbutils.DeleteByID(context.Background(), db, "tenants", 1)
+		if !errors.Is(err, dbutils.ErrForeignKeyConstraint) {
+			t.Errorf("Expected ErrForeignKeyConstraint, got %v", err)
+		}
+	})
+
+	t.Run("deletes dependents and record", func(t *testing.T) {
+		t.Parallel()
+		db := setup(t)
+
+		err := dbutils.DeleteWithDependents(context.Background(), db, "tenants", 1, []dbutils.Dependent{notes})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if count := countRows(t, db, "SELECT COUNT(*) FROM tenant_notes WHERE tenant_id = 1"); count != 0 {
+			t.Errorf("Expected the tenant's notes to be deleted, got %d", count)
+		}
+
+		if count := countRows(t, db, "SELECT COUNT(*) FROM users WHERE tenant_id = 1"); count != 0 {
+			t.Errorf("Expected the tenant's users to be deleted by cascade, got %d", count)
+		}
+
+		if count := countRows(t, db, "SELECT COUNT(*) FROM tenant_notes WHERE tenant_id = 2"); count != 1 {
+			t.Errorf("Expected other tenants' notes to be kept, got %d", count)
+		}
+	})
+
+	t.Run("rolls back when a dependent fails", func(t *testing.T) {
+		t.Parallel()
+		db := setup(t)
+
+		err := dbutils.DeleteWithDependents(context.Background(), db, "tenants", 1, []dbutils.Dependent{
+			notes, {Table: "missing_table", ForeignKey: "tenant_id"},
+		})
+		if err == nil {
+			t.Fatal("Expected an error deleting from a missing table")
+		}
+
+		if count := countRows(t, db, "SELECT COUNT(*) FROM tenant_notes WHERE tenant_id = 1"); count != 2 {
+			t.Errorf("Expected the tenant's notes to be kept, got %d", count)
+		}
+	})
+
+	t.Run("missing record", func(t *testing.T) {
+		t.Parallel()
+		db := setup(t)
+
+		err := dbutils.DeleteWithDependents(context.Background(), db, "tenants", 999, []dbutils.Dependent{notes})
+		if !errors.Is(err, dbutils.ErrRecordNotFound) {
+			t.Errorf("Expected ErrRecordNotFound, got %v", err)
+		}
+
+		if count := countRows(t, db, "SELECT COUNT(*) FROM tenant_notes"); count != 3 {
+			t.Errorf("Expected no notes to be deleted, got %d remaining", count)
+		}
+	})
+}
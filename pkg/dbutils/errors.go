@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/gurch101/gowebutils/pkg/validation"
 )
 
 // ConstraintError represents an error related to database constraints.
@@ -21,6 +23,44 @@ var ErrNotNullConstraint = errors.New("not null constraint")
 // ErrUniqueConstraint is returned when a UNIQUE constraint is violated.
 var ErrUniqueConstraint = errors.New("unique constraint")
 
+// UniqueConstraintError is returned when a UNIQUE constraint is violated. It wraps ErrUniqueConstraint.
+type UniqueConstraintError struct {
+	// Table is the table the constraint belongs to.
+	Table string
+	// Columns are the columns covered by the constraint, in the order they are declared.
+	Columns []string
+}
+
+func (e *UniqueConstraintError) Error() string {
+	qualified := make([]string, len(e.Columns))
+	for i, column := range e.Columns {
+		qualified[i] = e.Table + "." + column
+	}
+
+	return fmt.Sprintf("%s: %s", ErrUniqueConstraint, strings.Join(qualified, ", "))
+}
+
+func (e *UniqueConstraintError) Unwrap() error {
+	return ErrUniqueConstraint
+}
+
+// MapUniqueConstraint returns the field error for the UNIQUE constraint violated by err. Constraints are
+// identified by their comma-separated column names, e.g. tenant_name or tenant_id,email. Mapping the error
+// returned by an insert lets a controller report a duplicate against a request field without checking for
+// an existing record first, which would race with concurrent inserts. Other errors are returned unchanged.
+func MapUniqueConstraint(err error, fields map[string]validation.Error) error {
+	var uniqueErr *UniqueConstraintError
+	if !errors.As(err, &uniqueErr) {
+		return err
+	}
+
+	if fieldErr, ok := fields[strings.Join(uniqueErr.Columns, ",")]; ok {
+		return fieldErr
+	}
+
+	return err
+}
+
 // ErrForeignKeyConstraint is returned when a FOREIGN KEY constraint is violated.
 var ErrForeignKeyConstraint = errors.New("foreign key constraint")
 
@@ -75,11 +115,23 @@ func handleNotNullError(input string) error {
 	return fmt.Errorf("%w: %s", ErrNotNullConstraint, details)
 }
 
-// handleUniqueError handles UNIQUE constraint errors.
+// handleUniqueError handles UNIQUE constraint errors, which list the constraint's columns as
+// table.column, separated by commas.
 func handleUniqueError(input string) error {
 	details := strings.TrimPrefix(input, uniquePrefix)
+	uniqueErr := &UniqueConstraintError{Table: "", Columns: nil}
+
+	for _, qualified := range strings.Split(details, ",") {
+		table, column, ok := strings.Cut(strings.TrimSpace(qualified), ".")
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUniqueConstraint, details)
+		}
+
+		uniqueErr.Table = table
+		uniqueErr.Columns = append(uniqueErr.Columns, column)
+	}
 
-	return fmt.Errorf("%w: %s", ErrUniqueConstraint, details)
+	return uniqueErr
 }
 
 // handleForeignKeyError handles FOREIGN KEY constraint errors.
@@ -0,0 +1,125 @@
+package dbutils_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+func TestUniqueConstraintError(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	_, err := db.Exec(`CREATE TABLE memberships (
+		id INTEGER PRIMARY KEY,
+		tenant_id INTEGER NOT NULL,
+		email TEXT NOT NULL,
+		UNIQUE (tenant_id, email)
+	)`)
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		table           string
+		fields          map[string]any
+		expectedColumns []string
+		expectedMessage string
+	}{
+		{
+			name:  "single column",
+			table: "tenants",
+			fields: map[string]any{
+				"tenant_name":   "Acme",
+				"contact_email": "other@acme.com",
+				"plan":          "free",
+			},
+			expectedColumns: []string{"tenant_name"},
+			expectedMessage: "unique constraint: tenants.tenant_name",
+		},
+		{
+			name:            "multiple columns",
+			table:           "memberships",
+			fields:          map[string]any{"tenant_id": 1, "email": "jane@example.com"},
+			expectedColumns: []string{"tenant_id", "email"},
+			expectedMessage: "unique constraint: memberships.tenant_id, memberships.email",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The first insert succeeds unless the row is already seeded.
+			_, _ = dbutils.Insert(context.Background(), db, tt.table, tt.fields)
+			_, err := dbutils.Insert(context.Background(), db, tt.table, tt.fields)
+
+			var uniqueErr *dbutils.UniqueConstraintError
+			if !errors.As(err, &uniqueErr) || !errors.Is(err, dbutils.ErrUniqueConstraint) {
+				t.Fatalf("Expected UniqueConstraintError, got %v", err)
+			}
+
+			if uniqueErr.Table != tt.table || !reflect.DeepEqual(uniqueErr.Columns, tt.expectedColumns) {
+				t.Errorf("Expected %s %v, got %s %v", tt.table, tt.expectedColumns, uniqueErr.Table, uniqueErr.Columns)
+			}
+
+			if err.Error() != tt.expectedMessage {
+				t.Errorf("Expected message %q, got %q", tt.expectedMessage, err.Error())
+			}
+		})
+	}
+}
+
+func TestMapUniqueConstraint(t *testing.T) {
+	t.Parallel()
+
+	duplicateName := validation.Error{Field: "tenantName", Message: "This tenant is already registered"}
+	duplicateEmail := validation.Error{Field: "email", Message: "This email is already registered"}
+	fields := map[string]validation.Error{
+		"tenant_name":     duplicateName,
+		"tenant_id,email": duplicateEmail,
+	}
+	unmappedErr := &dbutils.UniqueConstraintError{Table: "users", Columns: []string{"email"}}
+	otherErr := errors.New("other")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{
+			name:     "mapped column",
+			err:      &dbutils.UniqueConstraintError{Table: "tenants", Columns: []string{"tenant_name"}},
+			expected: duplicateName,
+		},
+		{
+			name:     "mapped columns",
+			err:      &dbutils.UniqueConstraintError{Table: "users", Columns: []string{"tenant_id", "email"}},
+			expected: duplicateEmail,
+		},
+		{name: "unmapped constraint", err: unmappedErr, expected: unmappedErr},
+		{name: "other error", err: otherErr, expected: otherErr},
+		{name: "nil", err: nil, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := dbutils.MapUniqueConstraint(tt.err, fields); !reflect.DeepEqual(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidChunkSize is returned when a non-positive chunk size is provided to ExportTable.
+var ErrInvalidChunkSize = errors.New("chunk size must be greater than zero")
+
+// ErrNonIntegerID is returned when a table's id column does not hold integer values.
+var ErrNonIntegerID = errors.New("id column is not an integer")
+
+// ExportChunkFunc processes a single chunk of exported rows. Each row maps a column name to its value.
+// lastID is the primary key of the final row in the chunk and can be persisted to resume the export.
+type ExportChunkFunc func(rows []map[string]any, lastID int64) error
+
+// ExportTable walks an entire table in keyset-paginated chunks ordered by primary key, invoking callback
+// once per chunk. Each chunk is fetched with its own short query so no long-running transaction is held.
+// Rows inserted concurrently with a higher id are picked up by later chunks; rows are never skipped or
+// repeated since pagination is keyed on id rather than offset.
+//
+// Pass afterID = 0 to export from the beginning or the last id handed to callback to resume an export.
+// The id column is always selected. ExportTable returns the id of the last exported row.
+func ExportTable(
+	ctx context.Context,
+	db DB,
+	tableName string,
+	fields []string,
+	chunkSize int,
+	afterID int64,
+	callback ExportChunkFunc,
+) (int64, error) {
+	if chunkSize <= 0 {
+		return afterID, ErrInvalidChunkSize
+	}
+
+	columns := fields
+	if !slices.Contains(columns, "id") {
+		columns = append([]string{"id"}, fields...)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE id > $1 ORDER BY id LIMIT $2",
+		strings.Join(columns, ","),
+		tableName,
+	)
+
+	lastID := afterID
+
+	for {
+		rows, err := exportChunk(ctx, db, query, columns, lastID, chunkSize)
+		if err != nil {
+			return lastID, err
+		}
+
+		if len(rows) == 0 {
+			return lastID, nil
+		}
+
+		chunkLastID, ok := rows[len(rows)-1]["id"].(int64)
+		if !ok {
+			return lastID, fmt.Errorf("%w: %s", ErrNonIntegerID, tableName)
+		}
+
+		if err := callback(rows, chunkLastID); err != nil {
+			return lastID, err
+		}
+
+		lastID = chunkLastID
+
+		if len(rows) < chunkSize {
+			return lastID, nil
+		}
+	}
+}
+
+// exportChunk fetches a single chunk of rows with id greater than afterID.
+func exportChunk(
+	ctx context.Context,
+	db DB,
+	query string,
+	columns []string,
+	afterID int64,
+	chunkSize int,
+) ([]map[string]any, error) {
+	ctx, cancel := context.WithTimeout(ctx, getTimeout)
+	defer cancel()
+
+	start := time.Now()
+
+	rows, err := db.QueryContext(ctx, query, afterID, chunkSize)
+
+	defer func() { observeQuery(ctx, OperationSelect, query, []any{afterID, chunkSize}, start, err) }()
+
+	if err != nil {
+		return nil, WrapDBError(err)
+	}
+	defer rows.Close()
+
+	chunk, err := scanRowMaps(rows, columns, chunkSize)
+
+	return chunk, err
+}
+
+// scanRowMaps reads the remaining rows, mapping each column name to its value.
+func scanRowMaps(rows *sql.Rows, columns []string, sizeHint int) ([]map[string]any, error) {
+	result := make([]map[string]any, 0, sizeHint)
+
+	for rows.Next() {
+		values := make([]any, len(columns))
+		dest := make([]any, len(columns))
+
+		for i := range values {
+			dest[i] = &values[i]
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, WrapDBError(err)
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			row[column] = values[i]
+		}
+
+		result = append(result, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, WrapDBError(err)
+	}
+
+	return result, nil
+}
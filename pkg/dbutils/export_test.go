@@ -0,0 +1,113 @@
+package dbutils_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func TestExportTable(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	for i := range 3 {
+		_, err := dbutils.Insert(context.Background(), db, "tenants", map[string]any{
+			"tenant_name":   fmt.Sprintf("Tenant %d", i),
+			"contact_email": fmt.Sprintf("admin@tenant%d.com", i),
+			"plan":          "free",
+		})
+		if err != nil {
+			t.Fatalf("Failed to insert tenant: %v", err)
+		}
+	}
+
+	t.Run("exports all rows in chunks", func(t *testing.T) {
+		var chunkSizes []int
+
+		var names []any
+
+		lastID, err := dbutils.ExportTable(context.Background(), db, "tenants", []string{"tenant_name"}, 2, 0,
+			func(rows []map[string]any, _ int64) error {
+				chunkSizes = append(chunkSizes, len(rows))
+				for _, row := range rows {
+					names = append(names, row["tenant_name"])
+				}
+
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if lastID != 5 {
+			t.Errorf("Expected last id 5, got %d", lastID)
+		}
+
+		if len(chunkSizes) != 3 || chunkSizes[0] != 2 || chunkSizes[1] != 2 || chunkSizes[2] != 1 {
+			t.Errorf("Expected chunk sizes [2 2 1], got %v", chunkSizes)
+		}
+
+		if len(names) != 5 || names[0] != "Acme" || names[4] != "Tenant 2" {
+			t.Errorf("Expected 5 tenants in id order, got %v", names)
+		}
+	})
+
+	t.Run("resumes after id", func(t *testing.T) {
+		var ids []any
+
+		_, err := dbutils.ExportTable(context.Background(), db, "tenants", []string{"id"}, 10, 3,
+			func(rows []map[string]any, _ int64) error {
+				for _, row := range rows {
+					ids = append(ids, row["id"])
+				}
+
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(ids) != 2 || ids[0] != int64(4) || ids[1] != int64(5) {
+			t.Errorf("Expected ids [4 5], got %v", ids)
+		}
+	})
+
+	t.Run("callback error stops export", func(t *testing.T) {
+		errStop := errors.New("stop")
+
+		lastID, err := dbutils.ExportTable(context.Background(), db, "tenants", []string{"tenant_name"}, 2, 0,
+			func(_ []map[string]any, lastID int64) error {
+				if lastID > 2 {
+					return errStop
+				}
+
+				return nil
+			})
+		if !errors.Is(err, errStop) {
+			t.Errorf("Expected callback error, got %v", err)
+		}
+
+		if lastID != 2 {
+			t.Errorf("Expected last exported id 2, got %d", lastID)
+		}
+	})
+
+	t.Run("invalid chunk size", func(t *testing.T) {
+		_, err := dbutils.ExportTable(context.Background(), db, "tenants", []string{"id"}, 0, 0,
+			func(_ []map[string]any, _ int64) error { return nil })
+		if !errors.Is(err, dbutils.ErrInvalidChunkSize) {
+			t.Errorf("Expected ErrInvalidChunkSize, got %v", err)
+		}
+	})
+}
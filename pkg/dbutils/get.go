@@ -2,13 +2,21 @@ package dbutils
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 )
 
-const getTimeout = 3 * time.Second
+const (
+	getTimeout = 3 * time.Second
+
+	// getByIDsChunkSize is the maximum number of ids bound in a single GetByIDs query, well under SQLite's limit
+	// on the number of query parameters.
+	getByIDsChunkSize = 500
+)
 
 // ErrNoGetFilters is returned when no filters are provided to the GetBy function.
 var ErrNoGetFilters = errors.New("no filters provided")
@@ -22,6 +30,91 @@ func GetByID(ctx context.Context, db DB, tableName string, id int64, fields map[
 	return GetBy(ctx, db, tableName, fields, map[string]any{"id": id})
 }
 
+// GetByIDForTenant gets a record from the database by its id, scoped to the tenant with the given id. Records
+// belonging to other tenants return ErrRecordNotFound, so that one tenant cannot read another's records by
+// guessing ids. The table must have a tenant_id column.
+func GetByIDForTenant(
+	ctx context.Context,
+	db DB,
+	tableName string,
+	id int64,
+	tenantID int64,
+	fields map[string]any,
+) error {
+	if id < 0 {
+		return ErrRecordNotFound
+	}
+
+	return GetBy(ctx, db, tableName, fields, map[string]any{"id": id, "tenant_id": tenantID})
+}
+
+// GetByIDs gets the records with the given ids in a single WHERE id IN (...) query per 500 ids, rather than
+// one query per id. Each row maps a column name to its value, and the id column is always selected. Rows are
+// ordered by id regardless of the order of ids; duplicate ids are fetched once and ids with no record are
+// omitted. No query is run if ids is empty.
+func GetByIDs(ctx context.Context, db DB, tableName string, ids []int64, columns []string) ([]map[string]any, error) {
+	if len(ids) == 0 {
+		return []map[string]any{}, nil
+	}
+
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	if !slices.Contains(columns, "id") {
+		columns = append([]string{"id"}, columns...)
+	}
+
+	result := make([]map[string]any, 0, len(ids))
+
+	for chunk := range slices.Chunk(ids, getByIDsChunkSize) {
+		rows, err := getByIDsChunk(ctx, db, tableName, chunk, columns)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, rows...)
+	}
+
+	return result, nil
+}
+
+// getByIDsChunk fetches the records with the given ids, which must be sorted.
+func getByIDsChunk(
+	ctx context.Context, db DB, tableName string, ids []int64, columns []string,
+) ([]map[string]any, error) {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	// #nosec G201
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE id IN (%s) ORDER BY id",
+		strings.Join(columns, ","),
+		tableName,
+		strings.TrimSuffix(strings.Repeat("?,", len(ids)), ","),
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, getTimeout)
+	defer cancel()
+
+	start := time.Now()
+
+	rows, err := db.QueryContext(ctx, query, args...)
+
+	defer func() { observeQuery(ctx, OperationGet, query, args, start, err) }()
+
+	if err != nil {
+		return nil, WrapDBError(err)
+	}
+	defer rows.Close()
+
+	records, err := scanRowMaps(rows, columns, len(ids))
+
+	return records, err
+}
+
 // GetBy gets a record from the database by the provided filters.
 func GetBy(ctx context.Context, db DB, tableName string, fields map[string]any, filters map[string]any) error {
 	if len(filters) == 0 {
@@ -57,7 +150,7 @@ func GetBy(ctx context.Context, db DB, tableName string, fields map[string]any,
 	ctx, cancel := context.WithTimeout(ctx, getTimeout)
 	defer cancel()
 
-	err := db.QueryRowContext(ctx, query, whereArgs...).Scan(args...)
+	err := queryRow(ctx, db, OperationGet, query, whereArgs, args...)
 	if err != nil {
 		return WrapDBError(err)
 	}
@@ -65,22 +158,37 @@ func GetBy(ctx context.Context, db DB, tableName string, fields map[string]any,
 	return nil
 }
 
+// Exists reports whether a record with the given id exists. Errors are reported as the record not existing.
 func Exists(ctx context.Context, db DB, tableName string, id int64) bool {
 	if id < 0 {
 		return false
 	}
 
-	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1)", tableName)
+	exists, err := ExistsBy(ctx, db, tableName, "id", id)
+
+	return err == nil && exists
+}
+
+// ExistsBy reports whether a record whose column equals value exists, without fetching it. An existence check
+// before an insert gives a friendlier error for the common case, but the insert must still handle the
+// UNIQUE constraint error (see MapUniqueConstraint) since a concurrent insert can happen between the two.
+func ExistsBy(ctx context.Context, db DB, tableName string, column string, value any) (bool, error) {
+	// #nosec G201
+	query := fmt.Sprintf("SELECT 1 FROM %s WHERE %s = ? LIMIT 1", tableName, column)
 
 	ctx, cancel := context.WithTimeout(ctx, getTimeout)
 	defer cancel()
 
-	var exists bool
+	var found int
+
+	err := queryRow(ctx, db, OperationGet, query, []any{value}, &found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
 
-	err := db.QueryRowContext(ctx, query, id).Scan(&exists)
 	if err != nil {
-		return false
+		return false, WrapDBError(err)
 	}
 
-	return exists
+	return true, nil
 }
@@ -75,6 +75,126 @@ func TestGetByID(t *testing.T) {
 	})
 }
 
+func TestGetByIDForTenant(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	t.Run("record in tenant", func(t *testing.T) {
+		var name string
+
+		err := dbutils.GetByIDForTenant(context.Background(), db, "users", 1, 1, map[string]any{"user_name": &name})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if name != "admin" {
+			t.Errorf("Expected name 'admin', got '%s'", name)
+		}
+	})
+
+	t.Run("record in another tenant", func(t *testing.T) {
+		var name string
+
+		err := dbutils.GetByIDForTenant(context.Background(), db, "users", 1, 2, map[string]any{"user_name": &name})
+		if !errors.Is(err, dbutils.ErrRecordNotFound) {
+			t.Errorf("Expected ErrRecordNotFound, got %v", err)
+		}
+
+		if name != "" {
+			t.Errorf("Expected no fields to be scanned, got '%s'", name)
+		}
+	})
+
+	t.Run("negative ID", func(t *testing.T) {
+		var name string
+
+		err := dbutils.GetByIDForTenant(context.Background(), db, "users", -1, 1, map[string]any{"user_name": &name})
+		if !errors.Is(err, dbutils.ErrRecordNotFound) {
+			t.Errorf("Expected ErrRecordNotFound, got %v", err)
+		}
+	})
+}
+
+func TestGetByIDs(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	manyIDs := make([]int64, 0, 1200)
+	for id := int64(1200); id > 0; id-- {
+		manyIDs = append(manyIDs, id)
+	}
+
+	tests := []struct {
+		name string
+		ids  []int64
+	}{
+		{"ascending", []int64{1, 2}},
+		{"descending", []int64{2, 1}},
+		{"duplicates and missing ids", []int64{2, 999, 1, 2}},
+		{"more ids than fit in one query", manyIDs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := dbutils.GetByIDs(context.Background(), db, "tenants", tt.ids, []string{"tenant_name"})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(rows) != 2 {
+				t.Fatalf("Expected 2 rows, got %d: %v", len(rows), rows)
+			}
+
+			if rows[0]["id"] != int64(1) || rows[0]["tenant_name"] != "Acme" {
+				t.Errorf("Expected tenant 1 Acme first, got %v", rows[0])
+			}
+
+			if rows[1]["id"] != int64(2) || rows[1]["tenant_name"] != "Flancrest Enterprises" {
+				t.Errorf("Expected tenant 2 Flancrest Enterprises second, got %v", rows[1])
+			}
+		})
+	}
+}
+
+func TestGetByIDs_EmptyIDs(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	// A closed database fails any query, so a nil error shows that no query was run.
+	closeErr := db.Close()
+	if closeErr != nil {
+		t.Fatalf("Failed to close database connection: %v", closeErr)
+	}
+
+	rows, err := dbutils.GetByIDs(context.Background(), db, "tenants", nil, []string{"tenant_name"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if rows == nil || len(rows) != 0 {
+		t.Errorf("Expected an empty slice, got %v", rows)
+	}
+
+	_, err = dbutils.GetByIDs(context.Background(), db, "tenants", []int64{1}, []string{"tenant_name"})
+	if err == nil {
+		t.Error("Expected an error querying a closed database")
+	}
+}
+
 func TestExists(t *testing.T) {
 	t.Parallel()
 	db := testutils.SetupTestDB(t)
@@ -100,3 +220,47 @@ func TestExists(t *testing.T) {
 		}
 	})
 }
+
+func TestExistsBy(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	tests := []struct {
+		name     string
+		column   string
+		value    any
+		expected bool
+	}{
+		{"existing string value", "tenant_name", "Acme", true},
+		{"existing integer value", "id", 2, true},
+		{"missing value", "tenant_name", "Globex", false},
+		{"differently cased value", "tenant_name", "acme", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exists, err := dbutils.ExistsBy(context.Background(), db, "tenants", tt.column, tt.value)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if exists != tt.expected {
+				t.Errorf("Expected exists to be %v, got %v", tt.expected, exists)
+			}
+		})
+	}
+
+	t.Run("unknown column", func(t *testing.T) {
+		_, err := dbutils.ExistsBy(context.Background(), db, "tenants", "missing_column", "Acme")
+		if err == nil {
+			t.Error("Expected an error for an unknown column")
+		}
+	})
+}
@@ -12,12 +12,15 @@ var ErrNoFieldsToInsert = errors.New("no fields to insert")
 
 const insertTimeout = 3 * time.Second
 
-// Insert inserts a record into the database.
+// Insert inserts a record into the database. Timestamp columns registered with RegisterTimestamps are set to
+// the current time unless they are in fields. The record is audited if db is an AuditDB.
 func Insert(ctx context.Context, db DB, tableName string, fields map[string]any) (*int64, error) {
 	if len(fields) == 0 {
 		return nil, ErrNoFieldsToInsert
 	}
 
+	fields = withTimestamps(tableName, fields, true)
+
 	columns := make([]string, 0, len(fields))
 	values := make([]any, 0, len(fields))
 	placeholders := make([]string, 0, len(fields))
@@ -41,9 +44,17 @@ func Insert(ctx context.Context, db DB, tableName string, fields map[string]any)
 
 	var id int64
 
-	err := db.QueryRowContext(ctx, query, values...).Scan(&id)
+	log, db := auditLogOf(db)
+
+	err := withAuditTransaction(ctx, log, db, func(db DB) error {
+		if err := queryRow(ctx, db, OperationInsert, query, values, &id); err != nil {
+			return WrapDBError(err)
+		}
+
+		return auditInsert(ctx, log, db, tableName, id, fields)
+	})
 	if err != nil {
-		return nil, WrapDBError(err)
+		return nil, err
 	}
 
 	return &id, nil
@@ -0,0 +1,48 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Operation labels the kind of query in query metrics.
+type Operation string
+
+const (
+	OperationGet    Operation = "get"
+	OperationInsert Operation = "insert"
+	OperationUpdate Operation = "update"
+	OperationDelete Operation = "delete"
+	// OperationSelect labels queries built with QueryBuilder and other multi-row reads.
+	OperationSelect Operation = "select"
+)
+
+// queryRow runs a query expected to return at most one row, scans it into dest and records it with
+// observeQuery. The row is scanned before the query is recorded since drivers may not run the query until then.
+func queryRow(ctx context.Context, db DB, operation Operation, query string, args []any, dest ...any) error {
+	start := time.Now()
+	err := db.QueryRowContext(ctx, query, args...).Scan(dest...)
+	observeQuery(ctx, operation, query, args, start, err)
+
+	return err //nolint: wrapcheck
+}
+
+// observeQuery logs and records metrics for a query that started at start, if a QueryLogger or QueryMetrics
+// is set. sql.ErrNoRows is not treated as an error.
+func observeQuery(ctx context.Context, operation Operation, query string, args []any, start time.Time, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+
+	duration := time.Since(start)
+
+	if logger := queryLogger.Load(); logger != nil {
+		logger.LogQuery(ctx, query, args, duration, err)
+	}
+
+	if metrics := queryMetrics.Load(); metrics != nil {
+		metrics.observe(operation, duration, err)
+	}
+}
@@ -0,0 +1,85 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+)
+
+// ErrPragmasUnsupported is returned when the driver's connections cannot execute the PRAGMA statements.
+var ErrPragmasUnsupported = errors.New("driver connections do not support executing pragmas")
+
+// DefaultPragmas are applied to every SQLite connection opened by Open:
+//
+//   - foreign_keys enforces FOREIGN KEY constraints, which SQLite leaves off by default.
+//   - journal_mode = WAL lets readers run concurrently with a writer.
+//   - busy_timeout waits up to 5 seconds for a lock instead of failing immediately with SQLITE_BUSY.
+//   - synchronous = NORMAL is durable in WAL mode while syncing less often than the default.
+//
+//nolint:gochecknoglobals
+var DefaultPragmas = []string{"foreign_keys = ON", "journal_mode = WAL", "busy_timeout = 5000", "synchronous = NORMAL"}
+
+// GetPragmas returns the pragmas listed in the DB_PRAGMAS environment variable, e.g.
+// foreign_keys = ON,busy_timeout = 10000. It defaults to DefaultPragmas.
+func GetPragmas() []string {
+	return parser.ParseEnvStringSlice("DB_PRAGMAS", DefaultPragmas)
+}
+
+// OpenWithPragmas opens a database with the given driver and data source name that runs PRAGMA statements
+// for each of pragmas, e.g. "foreign_keys = ON", on every new connection. Pragmas are set per connection
+// rather than per database, so they must be applied by the connection pool rather than once after opening.
+func OpenWithPragmas(driverName, dataSourceName string, pragmas ...string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// The database is only opened to look up the driver, so no connections have been made yet.
+	drv := db.Driver()
+	_ = db.Close()
+
+	return sql.OpenDB(&pragmaConnector{driver: drv, dataSourceName: dataSourceName, pragmas: pragmas}), nil
+}
+
+// pragmaConnector opens driver connections and applies pragmas to them.
+type pragmaConnector struct {
+	driver         driver.Driver
+	dataSourceName string
+	pragmas        []string
+}
+
+func (c *pragmaConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open connection: %w", err)
+	}
+
+	if len(c.pragmas) == 0 {
+		return conn, nil
+	}
+
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		_ = conn.Close()
+
+		return nil, ErrPragmasUnsupported
+	}
+
+	for _, pragma := range c.pragmas {
+		if _, err := execer.ExecContext(ctx, "PRAGMA "+pragma, nil); err != nil {
+			_ = conn.Close()
+
+			return nil, fmt.Errorf("failed to set pragma %q: %w", pragma, err)
+		}
+	}
+
+	return conn, nil
+}
+
+func (c *pragmaConnector) Driver() driver.Driver {
+	return c.driver
+}
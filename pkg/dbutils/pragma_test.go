@@ -0,0 +1,116 @@
+package dbutils_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func TestSetupTestDBEnablesForeignKeys(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	var foreignKeys int
+	if err := db.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+		t.Fatalf("Failed to read pragma: %v", err)
+	}
+
+	if foreignKeys != 1 {
+		t.Errorf("Expected foreign_keys to be ON, got %d", foreignKeys)
+	}
+
+	_, err := dbutils.Insert(context.Background(), db, "users", map[string]any{
+		"user_name": "orphan", "email": "orphan@acme.com", "tenant_id": 999,
+	})
+	if !errors.Is(err, dbutils.ErrForeignKeyConstraint) {
+		t.Errorf("Expected ErrForeignKeyConstraint, got %v", err)
+	}
+}
+
+func TestOpenWithPragmas(t *testing.T) {
+	t.Parallel()
+
+	db, err := dbutils.OpenWithPragmas(
+		dbutils.SqliteDriverName, filepath.Join(t.TempDir(), "test.db"), dbutils.DefaultPragmas...,
+	)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	// Hold the first connection in a transaction so that the pragmas are read on a second connection.
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	defer func() { _ = tx.Rollback() }()
+
+	tests := []struct {
+		pragma   string
+		expected string
+	}{
+		{"foreign_keys", "1"},
+		{"journal_mode", "wal"},
+		{"busy_timeout", "5000"},
+		{"synchronous", "1"},
+	}
+
+	for _, tt := range tests {
+		var value string
+		if err := db.QueryRow("PRAGMA " + tt.pragma).Scan(&value); err != nil {
+			t.Fatalf("Failed to read pragma %s: %v", tt.pragma, err)
+		}
+
+		if value != tt.expected {
+			t.Errorf("Expected %s to be %s, got %s", tt.pragma, tt.expected, value)
+		}
+	}
+}
+
+func TestOpenWithPragmasInvalidPragma(t *testing.T) {
+	t.Parallel()
+
+	db, err := dbutils.OpenWithPragmas(dbutils.SqliteDriverName, ":memory:", "foreign_keys = ON", "not a pragma")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	defer func() { _ = db.Close() }()
+
+	if err := db.Ping(); err == nil {
+		t.Error("Expected an error connecting with an invalid pragma")
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestGetPragmas(t *testing.T) {
+	if pragmas := dbutils.GetPragmas(); !slices.Equal(pragmas, dbutils.DefaultPragmas) {
+		t.Errorf("Expected default pragmas, got %v", pragmas)
+	}
+
+	t.Setenv("DB_PRAGMAS", "foreign_keys = ON, busy_timeout = 10000")
+
+	expected := []string{"foreign_keys = ON", "busy_timeout = 10000"}
+	if pragmas := dbutils.GetPragmas(); !slices.Equal(pragmas, expected) {
+		t.Errorf("Expected %v, got %v", expected, pragmas)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gurch101/gowebutils/pkg/parser"
 	"github.com/gurch101/gowebutils/pkg/stringutils"
 )
 
@@ -182,6 +183,16 @@ func (qb *QueryBuilder) OrderBy(fields ...string) *QueryBuilder {
 	return qb
 }
 
+// OrderBySpec orders the results by sort specs parsed and validated with parser.ParseSort.
+func (qb *QueryBuilder) OrderBySpec(specs ...parser.SortSpec) *QueryBuilder {
+	fields := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		fields = append(fields, spec.String())
+	}
+
+	return qb.OrderBy(fields...)
+}
+
 func (qb *QueryBuilder) Limit(limit int) *QueryBuilder {
 	qb.limit = limit
 
@@ -256,13 +267,18 @@ func (qb *QueryBuilder) Build() (string, []interface{}) {
 	return query.String(), qb.args
 }
 
-func (qb *QueryBuilder) Execute(callback func(*sql.Rows) error) error {
+func (qb *QueryBuilder) Execute(callback func(*sql.Rows) error) (err error) {
 	query, args := qb.Build()
 
 	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
 	defer cancel()
 
+	start := time.Now()
+
 	rows, err := qb.db.QueryContext(ctx, query, args...)
+
+	defer func() { observeQuery(ctx, OperationSelect, query, args, start, err) }()
+
 	if err != nil {
 		return fmt.Errorf("query builder exec error: %w", err)
 	}
@@ -281,7 +297,8 @@ func (qb *QueryBuilder) Execute(callback func(*sql.Rows) error) error {
 	}()
 
 	for rows.Next() {
-		if err := callback(rows); err != nil {
+		err = callback(rows)
+		if err != nil {
 			return err
 		}
 	}
@@ -300,7 +317,7 @@ func (qb *QueryBuilder) QueryRow(dest ...any) error {
 	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
 	defer cancel()
 
-	err := qb.db.QueryRowContext(ctx, query, args...).Scan(dest...)
+	err := queryRow(ctx, qb.db, OperationSelect, query, args, dest...)
 	if err != nil {
 		return WrapDBError(err)
 	}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/parser"
 	"github.com/gurch101/gowebutils/pkg/testutils"
 )
 
@@ -111,6 +112,21 @@ func TestQueryBuilder_SelectWithOrderBy(t *testing.T) {
 	}
 }
 
+func TestQueryBuilder_SelectWithOrderBySpec(t *testing.T) {
+	t.Parallel()
+
+	qb := dbutils.NewQueryBuilder(nil).Select("id").From("users").OrderBySpec(
+		parser.SortSpec{Column: "userName", Descending: false},
+		parser.SortSpec{Column: "id", Descending: true},
+	)
+	query, _ := qb.Build()
+
+	expectedQuery := "SELECT id FROM users ORDER BY user_name ASC, id DESC"
+	if query != expectedQuery {
+		t.Errorf("Expected query %q, got %q", expectedQuery, query)
+	}
+}
+
 func TestQueryBuilder_SelectWithLimitAndOffset(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,98 @@
+package dbutils
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+)
+
+const (
+	// DefaultSlowQueryThreshold is the duration after which a query is logged as slow.
+	DefaultSlowQueryThreshold = 200 * time.Millisecond
+
+	redactedArg = "***"
+)
+
+//nolint:gochecknoglobals
+var queryLogger atomic.Pointer[QueryLogger]
+
+// QueryLogger logs the SQL, arguments and duration of every query run by the dbutils helpers and the
+// QueryBuilder, where the duration includes reading the query's rows. Queries are logged at debug level, and
+// queries that take SlowThreshold or longer are logged at warn level as slow queries. String and byte slice
+// arguments are redacted since they may hold personal data or secrets.
+type QueryLogger struct {
+	Logger *slog.Logger
+	// SlowThreshold is the duration after which a query is logged as slow. Defaults to 200ms.
+	SlowThreshold time.Duration
+}
+
+// GetQueryLogger returns a QueryLogger that logs to logger if the DB_QUERY_LOG environment variable is true,
+// or nil otherwise. The slow query threshold is read from DB_SLOW_QUERY_THRESHOLD (e.g. 500ms).
+func GetQueryLogger(logger *slog.Logger) *QueryLogger {
+	if !parser.ParseEnvBool("DB_QUERY_LOG", false) {
+		return nil
+	}
+
+	threshold, err := parser.ParseEnvDuration("DB_SLOW_QUERY_THRESHOLD", DefaultSlowQueryThreshold)
+	if err != nil {
+		panic(err)
+	}
+
+	return &QueryLogger{Logger: logger, SlowThreshold: threshold}
+}
+
+// SetQueryLogger sets the QueryLogger used by the dbutils helpers. Query logging is off by default, and a nil
+// logger turns it off again.
+func SetQueryLogger(logger *QueryLogger) {
+	queryLogger.Store(logger)
+}
+
+// LogQuery logs a query that took duration to run.
+func (l *QueryLogger) LogQuery(ctx context.Context, query string, args []any, duration time.Duration, err error) {
+	logger := l.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	threshold := l.SlowThreshold
+	if threshold == 0 {
+		threshold = DefaultSlowQueryThreshold
+	}
+
+	level := slog.LevelDebug
+	message := "db query"
+
+	if duration >= threshold {
+		level = slog.LevelWarn
+		message = "slow db query"
+	}
+
+	attrs := []slog.Attr{
+		slog.String("sql", query),
+		slog.Any("args", redactArgs(args)),
+		slog.Duration("duration", duration),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	logger.LogAttrs(ctx, level, message, attrs...)
+}
+
+func redactArgs(args []any) []any {
+	redacted := make([]any, len(args))
+
+	for i, arg := range args {
+		switch arg.(type) {
+		case string, *string, []byte:
+			redacted[i] = redactedArg
+		default:
+			redacted[i] = arg
+		}
+	}
+
+	return redacted
+}
@@ -0,0 +1,169 @@
+package dbutils_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+// slowCounter counts to two million with a recursive CTE, which takes well over a millisecond.
+const slowCounter = `(WITH RECURSIVE counter(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM counter WHERE x < 2000000)
+SELECT x FROM counter)`
+
+func setQueryLogger(t *testing.T, threshold time.Duration) *bytes.Buffer {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})) //nolint: exhaustruct
+
+	dbutils.SetQueryLogger(&dbutils.QueryLogger{Logger: logger, SlowThreshold: threshold})
+	t.Cleanup(func() { dbutils.SetQueryLogger(nil) })
+
+	return buf
+}
+
+func decodeLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to decode log record %q: %v", line, err)
+		}
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+//nolint:paralleltest // the query logger is package-wide
+func TestQueryLoggerLogsSlowQueries(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	buf := setQueryLogger(t, time.Millisecond)
+
+	var count int
+	if err := dbutils.NewQueryBuilder(db).Select("count(*)").From(slowCounter).QueryRow(&count); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records := decodeLogRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 log record, got %d: %s", len(records), buf.String())
+	}
+
+	record := records[0]
+	if record["level"] != "WARN" || record["msg"] != "slow db query" {
+		t.Errorf("expected a slow query warning, got %v", record)
+	}
+
+	if sql, _ := record["sql"].(string); !strings.Contains(sql, slowCounter) {
+		t.Errorf("expected sql to contain %q, got %v", slowCounter, record["sql"])
+	}
+
+	if duration, ok := record["duration"].(float64); !ok || time.Duration(duration) < time.Millisecond {
+		t.Errorf("expected a duration of at least 1ms, got %v", record["duration"])
+	}
+}
+
+//nolint:paralleltest // the query logger is package-wide
+func TestQueryLoggerRedactsArgs(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	buf := setQueryLogger(t, time.Hour)
+
+	var tenantName string
+
+	err := dbutils.GetBy(context.Background(), db, "tenants",
+		map[string]any{"tenant_name": &tenantName},
+		map[string]any{"id": 1, "contact_email": "admin@acme.com"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records := decodeLogRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 log record, got %d: %s", len(records), buf.String())
+	}
+
+	if records[0]["level"] != "DEBUG" || records[0]["msg"] != "db query" {
+		t.Errorf("expected a debug query log, got %v", records[0])
+	}
+
+	if strings.Contains(buf.String(), "admin@acme.com") {
+		t.Errorf("expected string args to be redacted, got %s", buf.String())
+	}
+
+	if !strings.Contains(buf.String(), `"***"`) {
+		t.Errorf("expected redacted arg placeholder, got %s", buf.String())
+	}
+}
+
+//nolint:paralleltest // the query logger is package-wide
+func TestQueryLoggerDisabled(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	buf := setQueryLogger(t, time.Millisecond)
+	dbutils.SetQueryLogger(nil)
+
+	if err := dbutils.HealthCheck(context.Background(), db); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no query logs, got %s", buf.String())
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestGetQueryLogger(t *testing.T) {
+	t.Setenv("DB_QUERY_LOG", "false")
+
+	if logger := dbutils.GetQueryLogger(slog.Default()); logger != nil {
+		t.Errorf("expected query logging to be off by default, got %+v", logger)
+	}
+
+	t.Setenv("DB_QUERY_LOG", "true")
+	t.Setenv("DB_SLOW_QUERY_THRESHOLD", "50ms")
+
+	logger := dbutils.GetQueryLogger(slog.Default())
+	if logger == nil || logger.SlowThreshold != 50*time.Millisecond {
+		t.Errorf("expected a query logger with a 50ms threshold, got %+v", logger)
+	}
+}
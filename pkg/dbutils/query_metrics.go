@@ -0,0 +1,62 @@
+package dbutils
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+//nolint:gochecknoglobals
+var queryMetrics atomic.Pointer[QueryMetrics]
+
+// QueryMetrics records the following Prometheus metrics for queries run by the dbutils helpers and the QueryBuilder:
+//
+//   - db_queries_total: a counter of queries labeled by operation.
+//   - db_query_duration_seconds: a histogram of query durations, including reading rows, labeled by operation.
+//   - db_query_errors_total: a counter of failed queries labeled by operation.
+//
+// The operation label is one of the Operation values, e.g. get or insert.
+type QueryMetrics struct {
+	queries   *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+	errors    *prometheus.CounterVec
+}
+
+// NewQueryMetrics registers the query metrics with registerer. Install them with SetQueryMetrics.
+func NewQueryMetrics(registerer prometheus.Registerer) *QueryMetrics {
+	factory := promauto.With(registerer)
+
+	//nolint: exhaustruct
+	return &QueryMetrics{
+		queries: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "db_queries_total",
+			Help: "Total number of database queries.",
+		}, []string{"operation"}),
+		durations: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Database query durations in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation"}),
+		errors: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "db_query_errors_total",
+			Help: "Total number of failed database queries.",
+		}, []string{"operation"}),
+	}
+}
+
+// SetQueryMetrics sets the QueryMetrics recorded by the dbutils helpers. Metrics are off by default, and nil
+// turns them off again.
+func SetQueryMetrics(m *QueryMetrics) {
+	queryMetrics.Store(m)
+}
+
+func (m *QueryMetrics) observe(operation Operation, duration time.Duration, err error) {
+	m.queries.WithLabelValues(string(operation)).Inc()
+	m.durations.WithLabelValues(string(operation)).Observe(duration.Seconds())
+
+	if err != nil {
+		m.errors.WithLabelValues(string(operation)).Inc()
+	}
+}
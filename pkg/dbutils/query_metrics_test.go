@@ -0,0 +1,146 @@
+package dbutils_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func setQueryMetrics(t *testing.T) *prometheus.Registry {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+
+	dbutils.SetQueryMetrics(dbutils.NewQueryMetrics(registry))
+	t.Cleanup(func() { dbutils.SetQueryMetrics(nil) })
+
+	return registry
+}
+
+// scrapeMetrics returns the metrics in registry in the Prometheus text format.
+func scrapeMetrics(registry *prometheus.Registry) string {
+	rr := httptest.NewRecorder()
+	//nolint: exhaustruct
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	return rr.Body.String()
+}
+
+func assertMetricLines(t *testing.T, registry *prometheus.Registry, expected ...string) {
+	t.Helper()
+
+	out := scrapeMetrics(registry)
+
+	for _, line := range expected {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("expected metric line %q in:\n%s", line, out)
+		}
+	}
+}
+
+//nolint:paralleltest // query metrics are package-wide
+func TestQueryMetricsGetByID(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	registry := setQueryMetrics(t)
+
+	var tenantName string
+
+	err := dbutils.GetByID(context.Background(), db, "tenants", 1, map[string]any{"tenant_name": &tenantName})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = dbutils.GetByID(context.Background(), db, "tenants", 999, map[string]any{"tenant_name": &tenantName})
+	if !errors.Is(err, dbutils.ErrRecordNotFound) {
+		t.Fatalf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	assertMetricLines(t, registry,
+		`db_queries_total{operation="get"} 2`,
+		`db_query_duration_seconds_count{operation="get"} 2`,
+	)
+}
+
+//nolint:paralleltest // query metrics are package-wide
+func TestQueryMetricsErrors(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	registry := setQueryMetrics(t)
+
+	_, err := dbutils.Insert(context.Background(), db, "tenants", map[string]any{"tenant_name": nil})
+	if err == nil {
+		t.Fatal("expected an error inserting a tenant without a name")
+	}
+
+	if err := dbutils.DeleteByID(context.Background(), db, "tenants", 999); !errors.Is(err, dbutils.ErrRecordNotFound) {
+		t.Fatalf("expected ErrRecordNotFound, got %v", err)
+	}
+
+	assertMetricLines(t, registry,
+		`db_queries_total{operation="insert"} 1`,
+		`db_query_errors_total{operation="insert"} 1`,
+		`db_queries_total{operation="delete"} 1`,
+	)
+
+	if out := scrapeMetrics(registry); strings.Contains(out, `db_query_errors_total{operation="delete"}`) {
+		t.Errorf("expected deleting a missing record not to count as an error:\n%s", out)
+	}
+}
+
+//nolint:paralleltest // the query logger and metrics are package-wide
+func TestQueryBuilderExecuteCallbackError(t *testing.T) {
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	registry := setQueryMetrics(t)
+	buf := setQueryLogger(t, time.Hour)
+	errCallback := errors.New("callback failed")
+
+	err := dbutils.NewQueryBuilder(db).Select("id").From("tenants").Execute(func(_ *sql.Rows) error {
+		return errCallback
+	})
+	if !errors.Is(err, errCallback) {
+		t.Fatalf("expected the callback error, got %v", err)
+	}
+
+	assertMetricLines(t, registry,
+		`db_queries_total{operation="select"} 1`,
+		`db_query_errors_total{operation="select"} 1`,
+	)
+
+	records := decodeLogRecords(t, buf)
+	if len(records) != 1 || records[0]["error"] != errCallback.Error() {
+		t.Errorf("expected the query to be logged with the callback error, got %s", buf.String())
+	}
+}
@@ -0,0 +1,74 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// QuotaStore is a database-backed store that tracks per-tenant request usage for a billing period.
+// It persists usage in the quota_usage table so counts survive restarts.
+type QuotaStore struct {
+	db DB
+}
+
+// NewQuotaStore creates a new QuotaStore backed by db.
+func NewQuotaStore(db DB) *QuotaStore {
+	return &QuotaStore{db: db}
+}
+
+// Consume records a single request for the tenant in the given period if the tenant's usage is below limit.
+// It returns the usage after the call and whether the request was allowed. The check and increment happen
+// in a single statement so concurrent requests cannot exceed the limit.
+func (s *QuotaStore) Consume(ctx context.Context, tenantID int64, period string, limit int64) (int64, bool, error) {
+	if limit <= 0 {
+		return 0, false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	var used int64
+
+	err := queryRow(ctx, s.db, OperationUpdate, `
+		INSERT INTO quota_usage (tenant_id, period, request_count) VALUES ($1, $2, 1)
+		ON CONFLICT (tenant_id, period) DO UPDATE
+		SET request_count = request_count + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE request_count < $3
+		RETURNING request_count`,
+		[]any{tenantID, period, limit}, &used,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return limit, false, nil
+	}
+
+	if err != nil {
+		return 0, false, WrapDBError(err)
+	}
+
+	return used, true, nil
+}
+
+// Usage returns the number of requests recorded for the tenant in the given period.
+func (s *QuotaStore) Usage(ctx context.Context, tenantID int64, period string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, getTimeout)
+	defer cancel()
+
+	var used int64
+
+	err := queryRow(
+		ctx, s.db, OperationGet,
+		"SELECT request_count FROM quota_usage WHERE tenant_id = $1 AND period = $2",
+		[]any{tenantID, period}, &used,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to get quota usage: %w", WrapDBError(err))
+	}
+
+	return used, nil
+}
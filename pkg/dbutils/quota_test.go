@@ -0,0 +1,81 @@
+package dbutils_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func TestQuotaStore(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	store := dbutils.NewQuotaStore(db)
+	ctx := context.Background()
+
+	t.Run("consumes until limit", func(t *testing.T) {
+		for i := int64(1); i <= 2; i++ {
+			used, allowed, err := store.Consume(ctx, 1, "2026-10", 2)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if !allowed || used != i {
+				t.Errorf("Expected request %d to be allowed with usage %d, got allowed=%v usage=%d", i, i, allowed, used)
+			}
+		}
+
+		used, allowed, err := store.Consume(ctx, 1, "2026-10", 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if allowed || used != 2 {
+			t.Errorf("Expected request to be rejected with usage 2, got allowed=%v usage=%d", allowed, used)
+		}
+
+		usage, err := store.Usage(ctx, 1, "2026-10")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if usage != 2 {
+			t.Errorf("Expected rejected request not to be counted, got usage %d", usage)
+		}
+	})
+
+	t.Run("periods and tenants are tracked separately", func(t *testing.T) {
+		_, allowed, err := store.Consume(ctx, 1, "2026-11", 2)
+		if err != nil || !allowed {
+			t.Errorf("Expected new period to be allowed, got allowed=%v err=%v", allowed, err)
+		}
+
+		_, allowed, err = store.Consume(ctx, 2, "2026-10", 2)
+		if err != nil || !allowed {
+			t.Errorf("Expected other tenant to be allowed, got allowed=%v err=%v", allowed, err)
+		}
+	})
+
+	t.Run("zero limit", func(t *testing.T) {
+		_, allowed, err := store.Consume(ctx, 2, "2026-12", 0)
+		if err != nil || allowed {
+			t.Errorf("Expected request to be rejected, got allowed=%v err=%v", allowed, err)
+		}
+	})
+
+	t.Run("no usage recorded", func(t *testing.T) {
+		usage, err := store.Usage(ctx, 2, "2025-01")
+		if err != nil || usage != 0 {
+			t.Errorf("Expected zero usage, got usage=%d err=%v", usage, err)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package dbutils
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidColumnValue is returned when a column value cannot be converted by the parse function passed
+// to ScanWith.
+var ErrInvalidColumnValue = errors.New("invalid column value")
+
+// ScanWith returns a sql.Scanner that converts a text column with parse and stores the result in dest, so that
+// enum-like types can be scanned directly and values that are not valid are rejected, e.g.:
+//
+//	dbutils.GetByID(ctx, db, "tenants", id, map[string]any{"plan": dbutils.ScanWith(&tenant.Plan, ParsePlan)})
+//
+// NULL is scanned as the zero value. A type can use it to implement sql.Scanner itself so that it never
+// needs converting at the call site:
+//
+//	func (p *Plan) Scan(src any) error { return dbutils.ScanWith(p, ParsePlan).Scan(src) }
+//
+// The parse error is described in the returned error but not wrapped, since a bad value in the database is
+// a server error rather than, say, a validation error to report to the client.
+func ScanWith[T any](dest *T, parse func(string) (T, error)) sql.Scanner { //nolint: ireturn
+	return scanFunc(func(src any) error {
+		var text string
+
+		switch value := src.(type) {
+		case nil:
+			var zero T
+			*dest = zero
+
+			return nil
+		case string:
+			text = value
+		case []byte:
+			text = string(value)
+		default:
+			return fmt.Errorf("%w: cannot convert %T to %T", ErrInvalidColumnValue, src, *dest)
+		}
+
+		parsed, err := parse(text)
+		if err != nil {
+			return fmt.Errorf("%w: %q: %v", ErrInvalidColumnValue, text, err) //nolint: errorlint
+		}
+
+		*dest = parsed
+
+		return nil
+	})
+}
+
+type scanFunc func(src any) error
+
+func (f scanFunc) Scan(src any) error {
+	return f(src)
+}
@@ -0,0 +1,110 @@
+package dbutils_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+type scanTestPlan string
+
+var errUnknownPlan = errors.New("unknown plan")
+
+func parseScanTestPlan(value string) (scanTestPlan, error) {
+	switch plan := scanTestPlan(strings.ToLower(value)); plan {
+	case "free", "paid":
+		return plan, nil
+	}
+
+	return "", errUnknownPlan
+}
+
+func TestScanWith(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	if _, err := db.Exec("UPDATE tenants SET plan = 'PAID' WHERE id = 2"); err != nil {
+		t.Fatalf("Failed to update tenant: %v", err)
+	}
+
+	if _, err := db.Exec("UPDATE tenants SET plan = 'gold' WHERE id = 1"); err != nil {
+		t.Fatalf("Failed to update tenant: %v", err)
+	}
+
+	t.Run("valid value", func(t *testing.T) {
+		var plan scanTestPlan
+
+		err := dbutils.GetByID(context.Background(), db, "tenants", 2, map[string]any{
+			"plan": dbutils.ScanWith(&plan, parseScanTestPlan),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if plan != "paid" {
+			t.Errorf("Expected plan 'paid', got '%s'", plan)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		var plan scanTestPlan
+
+		err := dbutils.GetByID(context.Background(), db, "tenants", 1, map[string]any{
+			"plan": dbutils.ScanWith(&plan, parseScanTestPlan),
+		})
+		if !errors.Is(err, dbutils.ErrInvalidColumnValue) {
+			t.Fatalf("Expected ErrInvalidColumnValue, got %v", err)
+		}
+
+		if errors.Is(err, errUnknownPlan) {
+			t.Error("Expected the parse error not to be wrapped")
+		}
+	})
+}
+
+func TestScanWith_SourceTypes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		src         any
+		expected    scanTestPlan
+		expectedErr error
+	}{
+		{"string", "free", "free", nil},
+		{"bytes", []byte("Paid"), "paid", nil},
+		{"null", nil, "", nil},
+		{"integer", int64(1), "paid", dbutils.ErrInvalidColumnValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			plan := scanTestPlan("paid")
+			if tt.expectedErr == nil {
+				plan = "unset"
+			}
+
+			err := dbutils.ScanWith(&plan, parseScanTestPlan).Scan(tt.src)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			if plan != tt.expected {
+				t.Errorf("Expected plan '%s', got '%s'", tt.expected, plan)
+			}
+		})
+	}
+}
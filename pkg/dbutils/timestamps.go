@@ -0,0 +1,62 @@
+package dbutils
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// TimestampColumns names the columns that Insert and UpdateByID maintain for a table. An empty name leaves
+// that column alone.
+type TimestampColumns struct {
+	// CreatedAt is set to the current time when a record is inserted.
+	CreatedAt string
+	// UpdatedAt is set to the current time when a record is inserted or updated.
+	UpdatedAt string
+}
+
+// DefaultTimestampColumns are the created_at and updated_at columns.
+//
+//nolint:gochecknoglobals
+var DefaultTimestampColumns = TimestampColumns{CreatedAt: "created_at", UpdatedAt: "updated_at"}
+
+//nolint:gochecknoglobals
+var (
+	timestampColumnsMu sync.RWMutex
+	timestampColumns   = map[string]TimestampColumns{}
+)
+
+// RegisterTimestamps makes Insert and UpdateByID set the given timestamp columns of tableName to the current
+// UTC time, e.g. RegisterTimestamps("tenants", DefaultTimestampColumns). Values passed explicitly to Insert or
+// UpdateByID take precedence. Database defaults such as CURRENT_TIMESTAMP only have a resolution of one
+// second, so managed timestamps are more reliable for If-Modified-Since checks.
+func RegisterTimestamps(tableName string, columns TimestampColumns) {
+	timestampColumnsMu.Lock()
+	defer timestampColumnsMu.Unlock()
+
+	timestampColumns[tableName] = columns
+}
+
+// withTimestamps returns fields with the managed timestamp columns of tableName added. fields is not modified.
+func withTimestamps(tableName string, fields map[string]any, insert bool) map[string]any {
+	timestampColumnsMu.RLock()
+	columns, ok := timestampColumns[tableName]
+	timestampColumnsMu.RUnlock()
+
+	if !ok {
+		return fields
+	}
+
+	now := time.Now().UTC()
+	fields = maps.Clone(fields)
+
+	if _, set := fields[columns.CreatedAt]; insert && columns.CreatedAt != "" && !set {
+		fields[columns.CreatedAt] = now
+	}
+
+	if _, set := fields[columns.UpdatedAt]; columns.UpdatedAt != "" && !set {
+		fields[columns.UpdatedAt] = now
+	}
+
+	return fields
+}
@@ -0,0 +1,120 @@
+package dbutils_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func getTimestamps(t *testing.T, db *sql.DB, query string, id int64) (time.Time, time.Time) {
+	t.Helper()
+
+	var createdAt, updatedAt time.Time
+	if err := db.QueryRow(query, id).Scan(&createdAt, &updatedAt); err != nil {
+		t.Fatalf("Failed to read timestamps: %v", err)
+	}
+
+	return createdAt, updatedAt
+}
+
+func TestRegisterTimestamps(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	dbutils.RegisterTimestamps("tenants", dbutils.DefaultTimestampColumns)
+
+	ctx := context.Background()
+	query := "SELECT created_at, updated_at FROM tenants WHERE id = ?"
+	before := time.Now().UTC()
+
+	id, err := dbutils.Insert(ctx, db, "tenants", map[string]any{
+		"tenant_name": "Timestamps", "contact_email": "admin@timestamps.com", "plan": "free",
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert tenant: %v", err)
+	}
+
+	createdAt, updatedAt := getTimestamps(t, db, query, *id)
+	if createdAt.Before(before) || !updatedAt.Equal(createdAt) {
+		t.Errorf("Expected created_at and updated_at to be set on insert, got %v and %v", createdAt, updatedAt)
+	}
+
+	previous := updatedAt
+
+	for version := int32(1); version <= 2; version++ {
+		err = dbutils.UpdateByID(ctx, db, "tenants", *id, version, map[string]any{"plan": "paid"})
+		if err != nil {
+			t.Fatalf("Failed to update tenant: %v", err)
+		}
+
+		newCreatedAt, updatedAt := getTimestamps(t, db, query, *id)
+		if !updatedAt.After(previous) {
+			t.Errorf("Expected updated_at to advance past %v on update %d, got %v", previous, version, updatedAt)
+		}
+
+		if !newCreatedAt.Equal(createdAt) {
+			t.Errorf("Expected created_at to stay %v, got %v", createdAt, newCreatedAt)
+		}
+
+		previous = updatedAt
+	}
+}
+
+func TestRegisterTimestamps_CustomColumns(t *testing.T) {
+	t.Parallel()
+	db := testutils.SetupTestDB(t)
+
+	defer func() {
+		closeErr := db.Close()
+		if closeErr != nil {
+			t.Fatalf("Failed to close database connection: %v", closeErr)
+		}
+	}()
+
+	_, err := db.Exec(`CREATE TABLE timestamp_widgets (
+		id INTEGER PRIMARY KEY,
+		widget_name TEXT NOT NULL,
+		inserted_on TIMESTAMP,
+		modified_on TIMESTAMP,
+		version INTEGER NOT NULL DEFAULT 1
+	)`)
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	dbutils.RegisterTimestamps("timestamp_widgets", dbutils.TimestampColumns{
+		CreatedAt: "inserted_on",
+		UpdatedAt: "modified_on",
+	})
+
+	ctx := context.Background()
+	query := "SELECT inserted_on, modified_on FROM timestamp_widgets WHERE id = ?"
+	explicit := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	id, err := dbutils.Insert(ctx, db, "timestamp_widgets", map[string]any{
+		"widget_name": "sprocket", "inserted_on": explicit,
+	})
+	if err != nil {
+		t.Fatalf("Failed to insert widget: %v", err)
+	}
+
+	insertedOn, modifiedOn := getTimestamps(t, db, query, *id)
+	if !insertedOn.Equal(explicit) {
+		t.Errorf("Expected explicit inserted_on %v to be kept, got %v", explicit, insertedOn)
+	}
+
+	if !modifiedOn.After(explicit) {
+		t.Errorf("Expected modified_on to be set to the current time, got %v", modifiedOn)
+	}
+}
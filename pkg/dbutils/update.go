@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 )
@@ -17,7 +19,9 @@ var ErrNoFieldsToUpdate = errors.New("no fields to update")
 
 const updateTimeout = 3 * time.Second
 
-// UpdateByID updates a record in the database by its id and version.
+// UpdateByID updates a record in the database by its id and version. The updated at column registered with
+// RegisterTimestamps is set to the current time unless it is in fields. The fields whose values changed are
+// audited if db is an AuditDB.
 func UpdateByID(
 	ctx context.Context,
 	db DB,
@@ -42,6 +46,12 @@ func UpdateByID(
 		return ErrNoFieldsToUpdate
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	auditFields := fields
+	fields = withTimestamps(tableName, fields, false)
+
 	setClause, args := makeSetClause(fields)
 	// #nosec G201
 	query := fmt.Sprintf(
@@ -52,22 +62,28 @@ func UpdateByID(
 		version,
 	)
 
-	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
-	defer cancel()
+	log, db := auditLogOf(db)
+
+	return withAuditTransaction(ctx, log, db, func(db DB) error {
+		snapshot, err := auditSnapshot(ctx, log, db, tableName, id, version, slices.Collect(maps.Keys(auditFields)))
+		if err != nil {
+			return err
+		}
 
-	var newVersion int32
+		var newVersion int32
 
-	err := db.QueryRowContext(ctx, query, args...).Scan(&newVersion)
-	if err != nil {
-		switch {
-		case errors.Is(err, sql.ErrNoRows):
-			return ErrEditConflict
-		default:
-			return WrapDBError(err)
+		err = queryRow(ctx, db, OperationUpdate, query, args, &newVersion)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrEditConflict
+			default:
+				return WrapDBError(err)
+			}
 		}
-	}
 
-	return nil
+		return auditUpdate(ctx, log, db, tableName, id, snapshot, auditFields)
+	})
 }
 
 func makeSetClause(fields map[string]any) (string, []any) {
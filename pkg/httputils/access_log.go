@@ -0,0 +1,288 @@
+package httputils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/gurch101/gowebutils/pkg/parser"
+)
+
+// AccessLogFormat selects how completed requests are written to the access log.
+type AccessLogFormat string
+
+const (
+	// AccessLogFormatSlog emits a structured "request completed" record via slog.
+	AccessLogFormatSlog AccessLogFormat = "slog"
+	// AccessLogFormatCommon emits Apache Common Log Format lines.
+	AccessLogFormatCommon AccessLogFormat = "common"
+	// AccessLogFormatCombined emits Apache Combined Log Format lines (CLF plus referer and user agent).
+	AccessLogFormatCombined AccessLogFormat = "combined"
+)
+
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// ErrUnknownAccessLogFormat is returned when ACCESS_LOG_FORMAT is not one of the supported access log formats.
+var ErrUnknownAccessLogFormat = errors.New("unknown access log format")
+
+// CLFLogFormatter writes access logs in Apache Common or Combined Log Format so that
+// legacy log analyzers can parse them.
+type CLFLogFormatter struct {
+	writer io.Writer
+	format AccessLogFormat
+	mu     *sync.Mutex
+}
+
+// NewCLFLogFormatter creates a CLFLogFormatter that writes to w. format must be either
+// AccessLogFormatCommon or AccessLogFormatCombined.
+func NewCLFLogFormatter(w io.Writer, format AccessLogFormat) *CLFLogFormatter {
+	return &CLFLogFormatter{writer: w, format: format, mu: &sync.Mutex{}}
+}
+
+// NewLogEntry creates a new LogEntry for the request.
+func (f *CLFLogFormatter) NewLogEntry(r *http.Request) middleware.LogEntry { //nolint:ireturn
+	return &CLFLogEntry{formatter: f, request: r, start: time.Now()}
+}
+
+// CLFLogEntry records a single request for CLFLogFormatter.
+type CLFLogEntry struct {
+	formatter *CLFLogFormatter
+	request   *http.Request
+	start     time.Time
+}
+
+// Write writes a single access log line for the completed request.
+func (e *CLFLogEntry) Write(status, bytes int, _ http.Header, _ time.Duration, _ interface{}) {
+	r := e.request
+
+	line := fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s",
+		clientHost(r),
+		clfUser(r),
+		e.start.Format(clfTimeFormat),
+		r.Method,
+		r.URL.RequestURI(),
+		r.Proto,
+		status,
+		clfBytes(bytes),
+	)
+
+	if e.formatter.format == AccessLogFormatCombined {
+		line += fmt.Sprintf(" %s %s", clfQuote(r.Referer()), clfQuote(r.UserAgent()))
+	}
+
+	e.formatter.mu.Lock()
+	defer e.formatter.mu.Unlock()
+
+	_, err := io.WriteString(e.formatter.writer, line+"\n")
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to write access log", "error", err)
+	}
+}
+
+// Panic is a no-op; panics are reported by the structured logger.
+func (e *CLFLogEntry) Panic(_ interface{}, _ []byte) {}
+
+// clientHost returns the host portion of the request's remote address.
+func clientHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func clfUser(r *http.Request) string {
+	if r.URL.User != nil && r.URL.User.Username() != "" {
+		return r.URL.User.Username()
+	}
+
+	return "-"
+}
+
+func clfBytes(bytes int) string {
+	if bytes == 0 {
+		return "-"
+	}
+
+	return strconv.Itoa(bytes)
+}
+
+func clfQuote(value string) string {
+	if value == "" {
+		return `"-"`
+	}
+
+	return strconv.Quote(value)
+}
+
+// multiLogFormatter fans a request out to several log formatters.
+type multiLogFormatter struct {
+	formatters []middleware.LogFormatter
+}
+
+// NewMultiLogFormatter creates a LogFormatter that writes each request to all of the given formatters.
+func NewMultiLogFormatter(formatters ...middleware.LogFormatter) middleware.LogFormatter { //nolint:ireturn
+	return &multiLogFormatter{formatters: formatters}
+}
+
+func (f *multiLogFormatter) NewLogEntry(r *http.Request) middleware.LogEntry { //nolint:ireturn
+	entries := make([]middleware.LogEntry, 0, len(f.formatters))
+	for _, formatter := range f.formatters {
+		entries = append(entries, formatter.NewLogEntry(r))
+	}
+
+	return multiLogEntry(entries)
+}
+
+type multiLogEntry []middleware.LogEntry
+
+func (e multiLogEntry) Write(status, bytes int, header http.Header, elapsed time.Duration, extra interface{}) {
+	for _, entry := range e {
+		entry.Write(status, bytes, header, elapsed, extra)
+	}
+}
+
+func (e multiLogEntry) Panic(v interface{}, stack []byte) {
+	for _, entry := range e {
+		entry.Panic(v, stack)
+	}
+}
+
+const defaultAccessLogSlowThreshold = time.Second
+
+// LogSampling configures NewSampledLogFormatter.
+type LogSampling struct {
+	// Rate is the fraction of fast, successful requests that are logged, between 0 and 1.
+	Rate float64
+	// SlowThreshold is the duration at or above which a request is always logged.
+	SlowThreshold time.Duration
+	// Random returns a number in [0, 1) that decides whether a request is sampled. Defaults to rand.Float64.
+	Random func() float64
+}
+
+// sampledLogFormatter logs every error and slow request but only a sample of the rest.
+type sampledLogFormatter struct {
+	formatter middleware.LogFormatter
+	sampling  LogSampling
+}
+
+// NewSampledLogFormatter creates a LogFormatter that reduces access log volume by writing only a fraction of
+// requests that completed with a status below 400 in less than the slow threshold. Errors, slow requests and
+// panics are always written.
+func NewSampledLogFormatter( //nolint:ireturn
+	formatter middleware.LogFormatter,
+	sampling LogSampling,
+) middleware.LogFormatter {
+	if sampling.Random == nil {
+		sampling.Random = rand.Float64
+	}
+
+	return &sampledLogFormatter{formatter: formatter, sampling: sampling}
+}
+
+func (f *sampledLogFormatter) NewLogEntry(r *http.Request) middleware.LogEntry { //nolint:ireturn
+	return &sampledLogEntry{entry: f.formatter.NewLogEntry(r), sampling: f.sampling}
+}
+
+type sampledLogEntry struct {
+	entry    middleware.LogEntry
+	sampling LogSampling
+}
+
+func (e *sampledLogEntry) Write(status, bytes int, header http.Header, elapsed time.Duration, extra interface{}) {
+	// A status of 0 means the handler did not write a response, in which case net/http sends a 200.
+	if status < http.StatusBadRequest && elapsed < e.sampling.SlowThreshold &&
+		e.sampling.Random() >= e.sampling.Rate {
+		return
+	}
+
+	e.entry.Write(status, bytes, header, elapsed, extra)
+}
+
+func (e *sampledLogEntry) Panic(v interface{}, stack []byte) {
+	e.entry.Panic(v, stack)
+}
+
+// AccessLogMiddleware returns middleware that writes an access log entry for each request with f, like
+// middleware.RequestLogger, and records the route pattern matched by an http.ServeMux so that log entries can
+// include it. Use it instead of middleware.RequestLogger when routes are served by an http.ServeMux.
+func AccessLogMiddleware(f middleware.LogFormatter) func(next http.Handler) http.Handler {
+	requestLogger := middleware.RequestLogger(f)
+
+	return func(next http.Handler) http.Handler {
+		logged := requestLogger(RecordRoutePattern(next))
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r, _ = withRoutePatternHolder(r)
+			logged.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GetAccessLogFormatter returns the access log formatter selected by the ACCESS_LOG_FORMAT
+// environment variable (slog, common, or combined). CLF output is written to clfWriter. When
+// ACCESS_LOG_INCLUDE_SLOG is true, the structured slog output is emitted as well. It panics if
+// ACCESS_LOG_FORMAT is not one of the supported formats.
+//
+// Requests taking at least ACCESS_LOG_SLOW_THRESHOLD (defaults to 1s) are logged at WARN level by the slog
+// formatter. ACCESS_LOG_SAMPLE_RATE (between 0 and 1, defaults to 1) samples requests that complete with a
+// status below 400 in less than the threshold. Errors and slow requests are always logged.
+func GetAccessLogFormatter(logger *slog.Logger, clfWriter io.Writer) middleware.LogFormatter { //nolint:ireturn
+	slowThreshold, err := parser.ParseEnvDuration("ACCESS_LOG_SLOW_THRESHOLD", defaultAccessLogSlowThreshold)
+	if err != nil {
+		panic(err)
+	}
+
+	formatter, err := getAccessLogFormatter(logger, clfWriter, slowThreshold)
+	if err != nil {
+		panic(err)
+	}
+
+	rate, err := parser.ParseEnvFloat64("ACCESS_LOG_SAMPLE_RATE", 1)
+	if err != nil {
+		panic(err)
+	}
+
+	if rate >= 1 {
+		return formatter
+	}
+
+	return NewSampledLogFormatter(formatter, LogSampling{Rate: rate, SlowThreshold: slowThreshold, Random: nil})
+}
+
+func getAccessLogFormatter( //nolint:ireturn
+	logger *slog.Logger,
+	clfWriter io.Writer,
+	slowThreshold time.Duration,
+) (middleware.LogFormatter, error) {
+	slogFormatter := NewSlogLogFormatter(logger)
+	slogFormatter.SlowThreshold = slowThreshold
+
+	format := AccessLogFormat(strings.ToLower(parser.ParseEnvString("ACCESS_LOG_FORMAT", string(AccessLogFormatSlog))))
+
+	switch format {
+	case AccessLogFormatSlog:
+		return slogFormatter, nil
+	case AccessLogFormatCommon, AccessLogFormatCombined:
+	default:
+		return nil, fmt.Errorf("%w: %s, use slog, common or combined", ErrUnknownAccessLogFormat, format)
+	}
+
+	clfFormatter := NewCLFLogFormatter(clfWriter, format)
+
+	if parser.ParseEnvBool("ACCESS_LOG_INCLUDE_SLOG", false) {
+		return NewMultiLogFormatter(slogFormatter, clfFormatter), nil
+	}
+
+	return clfFormatter, nil
+}
@@ -0,0 +1,167 @@
+package httputils_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestCLFLogFormatter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		format   httputils.AccessLogFormat
+		bytes    int
+		expected string
+	}{
+		{
+			name:     "common",
+			format:   httputils.AccessLogFormatCommon,
+			bytes:    512,
+			expected: `^192\.0\.2\.1 - - \[[^\]]+\] "GET /tenants\?page=2 HTTP/1\.1" 200 512\n$`,
+		},
+		{
+			name:   "combined",
+			format: httputils.AccessLogFormatCombined,
+			bytes:  512,
+			expected: `^192\.0\.2\.1 - - \[[^\]]+\] "GET /tenants\?page=2 HTTP/1\.1" 200 512 ` +
+				`"https://example\.com/" "test-agent"\n$`,
+		},
+		{
+			name:     "empty body",
+			format:   httputils.AccessLogFormatCommon,
+			bytes:    0,
+			expected: `^192\.0\.2\.1 - - \[[^\]]+\] "GET /tenants\?page=2 HTTP/1\.1" 200 -\n$`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			r := httptest.NewRequest(http.MethodGet, "/tenants?page=2", nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+			r.Header.Set("Referer", "https://example.com/")
+			r.Header.Set("User-Agent", "test-agent")
+
+			formatter := httputils.NewCLFLogFormatter(&buf, tt.format)
+			formatter.NewLogEntry(r).Write(http.StatusOK, tt.bytes, nil, time.Millisecond, nil)
+
+			if !regexp.MustCompile(tt.expected).MatchString(buf.String()) {
+				t.Errorf("expected log line to match %q, got %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestSampledLogFormatter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rate     float64
+		random   float64
+		status   int
+		elapsed  time.Duration
+		expected bool
+	}{
+		{name: "sampled success", rate: 0.1, random: 0.05, status: http.StatusOK, expected: true},
+		{name: "dropped success", rate: 0.1, random: 0.5, status: http.StatusOK, expected: false},
+		{name: "dropped unwritten response", rate: 0.1, random: 0.5, status: 0, expected: false},
+		{name: "dropped redirect", rate: 0.1, random: 0.5, status: http.StatusFound, expected: false},
+		{name: "rate of zero", rate: 0, random: 0, status: http.StatusOK, expected: false},
+		{name: "rate of one", rate: 1, random: 0.999, status: http.StatusOK, expected: true},
+		{name: "client error", rate: 0, random: 0.999, status: http.StatusNotFound, expected: true},
+		{name: "server error", rate: 0, random: 0.999, status: http.StatusInternalServerError, expected: true},
+		{name: "unavailable", rate: 0, random: 0.999, status: http.StatusServiceUnavailable, expected: true},
+		{
+			name: "slow success", rate: 0, random: 0.999, status: http.StatusOK, elapsed: time.Second,
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			formatter := httputils.NewSampledLogFormatter(
+				httputils.NewCLFLogFormatter(&buf, httputils.AccessLogFormatCommon),
+				httputils.LogSampling{
+					Rate:          tt.rate,
+					SlowThreshold: time.Second,
+					Random:        func() float64 { return tt.random },
+				},
+			)
+
+			entry := formatter.NewLogEntry(httptest.NewRequest(http.MethodGet, "/tenants", nil))
+			entry.Write(tt.status, 0, nil, tt.elapsed, nil)
+
+			if logged := buf.Len() > 0; logged != tt.expected {
+				t.Errorf("expected logged to be %v, got %v", tt.expected, logged)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestGetAccessLogFormatterSampling(t *testing.T) {
+	t.Setenv("ACCESS_LOG_SAMPLE_RATE", "0")
+
+	var buf bytes.Buffer
+
+	formatter := httputils.GetAccessLogFormatter(slog.New(slog.NewJSONHandler(&buf, nil)), io.Discard)
+
+	for _, status := range []int{http.StatusOK, http.StatusInternalServerError} {
+		entry := formatter.NewLogEntry(httptest.NewRequest(http.MethodGet, "/tenants", nil))
+		entry.Write(status, 0, nil, time.Millisecond, nil)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"status":500`) {
+		t.Errorf("expected only the 500 to be logged, got %q", buf.String())
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestGetAccessLogFormatterCLFWriter(t *testing.T) {
+	t.Setenv("ACCESS_LOG_FORMAT", "combined")
+
+	var buf bytes.Buffer
+
+	formatter := httputils.GetAccessLogFormatter(slog.New(slog.NewJSONHandler(io.Discard, nil)), &buf)
+
+	entry := formatter.NewLogEntry(httptest.NewRequest(http.MethodGet, "/tenants", nil))
+	entry.Write(http.StatusOK, 0, nil, time.Millisecond, nil)
+
+	if !strings.Contains(buf.String(), `"GET /tenants HTTP/1.1" 200 - "-" "-"`) {
+		t.Errorf("expected combined log line to be written to the configured writer, got %q", buf.String())
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestGetAccessLogFormatterUnknownFormat(t *testing.T) {
+	t.Setenv("ACCESS_LOG_FORMAT", "apache")
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, httputils.ErrUnknownAccessLogFormat) {
+			t.Errorf("expected panic with ErrUnknownAccessLogFormat, got %v", err)
+		}
+	}()
+
+	httputils.GetAccessLogFormatter(slog.Default(), io.Discard)
+}
@@ -0,0 +1,62 @@
+package httputils
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// AfterResponseHook is called once a response has been written. status is the final response status and
+// duration is the time taken to serve the request.
+type AfterResponseHook func(r *http.Request, status int, duration time.Duration)
+
+// AfterResponseMiddleware invokes hooks after the next handler returns so that post-processing such as
+// recording metrics, flushing audit records or emitting events happens in one place.
+//
+// Hooks also run if the handler panics. If no response was written before the panic, hooks receive a
+// 500 status. The panic is then re-raised so that it is handled by the recovery middleware.
+func AfterResponseMiddleware(hooks ...AfterResponseHook) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			start := time.Now()
+
+			defer func() {
+				p := recover()
+
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+					if p != nil {
+						status = http.StatusInternalServerError
+					}
+				}
+
+				duration := time.Since(start)
+				for _, hook := range hooks {
+					runAfterResponseHook(hook, r, status, duration)
+				}
+
+				if p != nil {
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
+
+// runAfterResponseHook runs a single hook, logging rather than propagating any panic so that one
+// failing hook does not prevent the others from running.
+func runAfterResponseHook(hook AfterResponseHook, r *http.Request, status int, duration time.Duration) {
+	defer func() {
+		if p := recover(); p != nil {
+			logError(r, fmt.Errorf("after response hook panicked: %v", p)) //nolint: err113
+		}
+	}()
+
+	hook(r, status, duration)
+}
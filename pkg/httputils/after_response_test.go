@@ -0,0 +1,91 @@
+package httputils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestAfterResponseMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		handler        http.HandlerFunc
+		expectedStatus int
+		expectPanic    bool
+	}{
+		{
+			name: "explicit status",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "implicit status",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "no response written",
+			handler: func(_ http.ResponseWriter, _ *http.Request) {
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "panic",
+			handler: func(_ http.ResponseWriter, _ *http.Request) {
+				panic("boom")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectPanic:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls []int
+
+			hook := func(_ *http.Request, status int, duration time.Duration) {
+				if duration < 0 {
+					t.Errorf("expected non-negative duration, got %v", duration)
+				}
+
+				calls = append(calls, status)
+			}
+			panickingHook := func(_ *http.Request, _ int, _ time.Duration) {
+				panic("hook failed")
+			}
+
+			handler := httputils.AfterResponseMiddleware(panickingHook, hook, hook)(tt.handler)
+
+			func() {
+				defer func() {
+					if p := recover(); (p != nil) != tt.expectPanic {
+						t.Errorf("expected panic %v, got %v", tt.expectPanic, p)
+					}
+				}()
+
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			}()
+
+			if len(calls) != 2 {
+				t.Fatalf("expected both hooks to be called, got %d calls", len(calls))
+			}
+
+			for _, status := range calls {
+				if status != tt.expectedStatus {
+					t.Errorf("expected status %d, got %d", tt.expectedStatus, status)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package httputils
+
+import (
+	"net/http"
+
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+// BatchResult is the outcome of a single operation in a batch request.
+type BatchResult struct {
+	// Index is the position of the operation in the batch request.
+	Index int `json:"index"`
+	// Status is the HTTP status code the operation would have received on its own.
+	Status int `json:"status"`
+	// Data is the response body of a successful operation.
+	Data any `json:"data,omitempty"`
+	// Errors describes why the operation failed, using the same shape as single-request error responses.
+	Errors any `json:"errors,omitempty"`
+}
+
+// BatchSuccess returns the result of a successful batch operation.
+func BatchSuccess(index, status int, data any) BatchResult {
+	return BatchResult{Index: index, Status: status, Data: data} //nolint: exhaustruct
+}
+
+// BatchValidationFailure returns the result of a batch operation that failed validation.
+func BatchValidationFailure(index int, errs []validation.Error) BatchResult {
+	return BatchResult{Index: index, Status: http.StatusBadRequest, Errors: errs} //nolint: exhaustruct
+}
+
+// BatchFailure returns the result of a failed batch operation. The error is mapped to a status code the
+// same way HandleErrorResponse maps it; unexpected errors are logged and reported as 500s.
+func BatchFailure(r *http.Request, index int, err error) BatchResult {
+	status, body := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		logError(r, err)
+	}
+
+	return BatchResult{Index: index, Status: status, Errors: body} //nolint: exhaustruct
+}
+
+// WriteBatchResponse writes the results of a batch request. If every operation succeeded, the response
+// status is 200 OK. If every operation failed with the same status, the response has that status; otherwise it
+// is 207 Multi-Status so clients can retry only the failed operations. Validation error fields are cased the
+// same way as in single-request error responses. The response body has the following shape:
+//
+//	{
+//		"results": [
+//			{"index": 0, "status": 201, "data": {"id": 3}},
+//			{"index": 1, "status": 400, "errors": [{"field": "plan", "message": "Invalid plan"}]}
+//		]
+//	}
+func WriteBatchResponse(w http.ResponseWriter, r *http.Request, results []BatchResult) {
+	keyCase := contextGetKeyCase(r.Context())
+	cased := make([]BatchResult, len(results))
+
+	for i, result := range results {
+		result.Errors = caseErrorFields(result.Errors, keyCase)
+		cased[i] = result
+	}
+
+	RespondJSON(w, r, batchStatus(results), map[string]any{"results": cased}, nil)
+}
+
+// batchStatus returns the status of a batch response: 200 OK if every operation succeeded, the common status
+// if every operation failed with the same status and 207 Multi-Status otherwise.
+func batchStatus(results []BatchResult) int {
+	failed := 0
+	failedStatus := 0
+
+	for _, result := range results {
+		if result.Status >= http.StatusOK && result.Status < http.StatusMultipleChoices {
+			continue
+		}
+
+		if failed > 0 && result.Status != failedStatus {
+			return http.StatusMultiStatus
+		}
+
+		failed++
+		failedStatus = result.Status
+	}
+
+	switch failed {
+	case 0:
+		return http.StatusOK
+	case len(results):
+		return failedStatus
+	default:
+		return http.StatusMultiStatus
+	}
+}
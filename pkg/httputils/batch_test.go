@@ -0,0 +1,75 @@
+package httputils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+func TestWriteBatchResponseStatus(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	invalid := []validation.Error{{Field: "plan", Message: "Invalid plan"}}
+
+	tests := []struct {
+		name           string
+		results        []httputils.BatchResult
+		expectedStatus int
+	}{
+		{
+			name: "all succeeded",
+			results: []httputils.BatchResult{
+				httputils.BatchSuccess(0, http.StatusCreated, nil),
+				httputils.BatchSuccess(1, http.StatusCreated, nil),
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "partial success",
+			results: []httputils.BatchResult{
+				httputils.BatchSuccess(0, http.StatusCreated, nil),
+				httputils.BatchValidationFailure(1, invalid),
+			},
+			expectedStatus: http.StatusMultiStatus,
+		},
+		{
+			name: "all failed with the same status",
+			results: []httputils.BatchResult{
+				httputils.BatchValidationFailure(0, invalid),
+				httputils.BatchValidationFailure(1, invalid),
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "all failed with different statuses",
+			results: []httputils.BatchResult{
+				httputils.BatchValidationFailure(0, invalid),
+				httputils.BatchFailure(r, 1, dbutils.ErrRecordNotFound),
+			},
+			expectedStatus: http.StatusMultiStatus,
+		},
+		{
+			name:           "empty batch",
+			results:        []httputils.BatchResult{},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			httputils.WriteBatchResponse(rr, httptest.NewRequest(http.MethodPost, "/", nil), tt.results)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
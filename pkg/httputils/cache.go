@@ -0,0 +1,283 @@
+package httputils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// CacheStatusHeader is set to HIT on responses served from the CacheStore and MISS on responses that
+	// were stored.
+	CacheStatusHeader = "X-Cache"
+
+	defaultCacheTTL      = time.Minute
+	cacheCleanupInterval = time.Minute
+)
+
+// CachedResponse is a response stored by CacheMiddleware.
+type CachedResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// CacheStore stores responses for CacheMiddleware. Implementations must be safe for concurrent use.
+// MemoryCacheStore keeps responses in process and is suitable for a single instance.
+type CacheStore interface {
+	// Get returns the response stored for key, or nil if there is none or it has expired.
+	Get(ctx context.Context, key string) (*CachedResponse, error)
+	// Set stores the response for key until ttl elapses.
+	Set(ctx context.Context, key string, response CachedResponse, ttl time.Duration) error
+}
+
+// CacheConfig configures CacheMiddleware.
+type CacheConfig struct {
+	// Store persists responses. Defaults to a MemoryCacheStore.
+	Store CacheStore
+	// TTL is how long a response is served from the cache. Defaults to 1 minute.
+	TTL time.Duration
+	// Vary lists request headers whose values are part of the cache key, e.g. Accept or Accept-Language.
+	Vary []string
+	// Scope returns a value identifying whose view of the resource a request is for, e.g. the tenant or user
+	// ID, so that responses are not served to other tenants or users. Defaults to no scope.
+	Scope func(r *http.Request) string
+}
+
+// CacheMiddleware serves repeated GET and HEAD requests for the same URL from a short-lived server-side cache,
+// e.g.:
+//
+//	httputils.With(router, httputils.CacheMiddleware(cfg)).Get("/tenants/{id}", c.GetTenantHandler)
+//
+// Responses are keyed by method, path, query string, the Vary headers and the Scope. Only 2xx responses
+// are stored. Responses with a Set-Cookie header or Cache-Control: no-store are never stored. Requests with
+// Cache-Control: no-store bypass the cache. Since cached responses are not invalidated when the resource
+// changes, the TTL should be short enough for stale reads to be acceptable.
+func CacheMiddleware(cfg CacheConfig) func(next http.Handler) http.Handler {
+	if cfg.TTL == 0 {
+		cfg.TTL = defaultCacheTTL
+	}
+
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryCacheStore()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if (r.Method != http.MethodGet && r.Method != http.MethodHead) || hasNoStore(r.Header) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			key := cacheKey(r, cfg)
+
+			cached, err := cfg.Store.Get(r.Context(), key)
+			if err != nil {
+				logError(r, fmt.Errorf("failed to read cached response: %w", err))
+			} else if cached != nil {
+				for name, values := range cached.Header {
+					w.Header()[name] = values
+				}
+
+				w.Header().Set(CacheStatusHeader, "HIT")
+				w.WriteHeader(cached.Status)
+				_, _ = w.Write(cached.Body)
+
+				return
+			}
+
+			// Headers set by outer middleware, such as the request ID, describe this request rather than the
+			// cached response, so only headers set by the handler are stored.
+			outerHeader := w.Header().Clone()
+			cw := &cacheWriter{ResponseWriter: w, body: bytes.Buffer{}, header: nil, status: 0}
+
+			next.ServeHTTP(cw, r)
+
+			if cw.header == nil || !isCacheable(cw.status, cw.header) {
+				return
+			}
+
+			response := CachedResponse{
+				Status: cw.status,
+				Header: handlerHeader(outerHeader, cw.header),
+				Body:   cw.body.Bytes(),
+			}
+			if err := cfg.Store.Set(context.WithoutCancel(r.Context()), key, response, cfg.TTL); err != nil {
+				logError(r, fmt.Errorf("failed to cache response: %w", err))
+			}
+		})
+	}
+}
+
+func cacheKey(r *http.Request, cfg CacheConfig) string {
+	key := strings.Builder{}
+	if cfg.Scope != nil {
+		key.WriteString(cfg.Scope(r))
+		key.WriteString(" ")
+	}
+
+	key.WriteString(r.Method)
+	key.WriteString(" ")
+	key.WriteString(r.URL.RequestURI())
+
+	for _, name := range cfg.Vary {
+		key.WriteString(" ")
+		key.WriteString(name)
+		key.WriteString("=")
+		key.WriteString(strings.Join(r.Header.Values(name), ","))
+	}
+
+	return key.String()
+}
+
+// isCacheable reports whether a response with the given status and headers may be stored.
+func isCacheable(status int, header http.Header) bool {
+	return status >= http.StatusOK && status < http.StatusMultipleChoices && !hasNoStore(header) &&
+		header.Get("Set-Cookie") == ""
+}
+
+func hasNoStore(header http.Header) bool {
+	for _, directive := range strings.Split(strings.Join(header.Values("Cache-Control"), ","), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cacheWriter writes the response through to the client while keeping a copy of its status, headers and body
+// to be cached. The cache status header is added to the response before it is written.
+type cacheWriter struct {
+	http.ResponseWriter
+	body   bytes.Buffer
+	header http.Header
+	status int
+}
+
+func (cw *cacheWriter) WriteHeader(status int) {
+	if cw.header != nil {
+		return
+	}
+
+	cw.status = status
+	cw.header = cw.Header().Clone()
+
+	if isCacheable(status, cw.header) {
+		cw.Header().Set(CacheStatusHeader, "MISS")
+	}
+
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *cacheWriter) Write(b []byte) (int, error) {
+	if cw.header == nil {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	cw.body.Write(b)
+
+	return cw.ResponseWriter.Write(b) //nolint: wrapcheck
+}
+
+// MemoryCacheStore is an in-memory CacheStore. Expired responses are evicted periodically until the store is
+// closed.
+type MemoryCacheStore struct {
+	clock     Clock
+	mu        sync.Mutex
+	entries   map[string]*cacheEntry
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type cacheEntry struct {
+	response  CachedResponse
+	expiresAt time.Time
+}
+
+// NewMemoryCacheStore creates a MemoryCacheStore.
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return NewMemoryCacheStoreWithClock(RealClock{})
+}
+
+// NewMemoryCacheStoreWithClock creates a MemoryCacheStore that reads the current time from clock when
+// expiring responses.
+func NewMemoryCacheStoreWithClock(clock Clock) *MemoryCacheStore {
+	store := &MemoryCacheStore{
+		clock:     clock,
+		mu:        sync.Mutex{},
+		entries:   make(map[string]*cacheEntry),
+		done:      make(chan struct{}),
+		closeOnce: sync.Once{},
+	}
+
+	go func() {
+		ticker := time.NewTicker(cacheCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				store.EvictExpired()
+			case <-store.done:
+				return
+			}
+		}
+	}()
+
+	return store
+}
+
+// Close stops evicting expired responses. The store can still be used, but responses are only evicted when
+// EvictExpired is called.
+func (s *MemoryCacheStore) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// Get returns the response stored for key.
+func (s *MemoryCacheStore) Get(_ context.Context, key string) (*CachedResponse, error) {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		return nil, nil //nolint: nilnil
+	}
+
+	return &entry.response, nil
+}
+
+// Set stores the response for key.
+func (s *MemoryCacheStore) Set(_ context.Context, key string, response CachedResponse, ttl time.Duration) error {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &cacheEntry{response: response, expiresAt: now.Add(ttl)}
+
+	return nil
+}
+
+// EvictExpired removes responses whose TTL has elapsed.
+func (s *MemoryCacheStore) EvictExpired() {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
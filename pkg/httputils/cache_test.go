@@ -0,0 +1,210 @@
+package httputils_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func newCachedHandler(t *testing.T, cfg httputils.CacheConfig, next http.HandlerFunc) http.Handler {
+	t.Helper()
+
+	if cfg.Store == nil {
+		store := httputils.NewMemoryCacheStore()
+		t.Cleanup(store.Close)
+		cfg.Store = store
+	}
+
+	return httputils.CacheMiddleware(cfg)(next)
+}
+
+func doCachedRequest(handler http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for name, values := range header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestCacheMiddlewareServesCachedResponse(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	//nolint: exhaustruct
+	handler := newCachedHandler(t, httputils.CacheConfig{}, func(w http.ResponseWriter, _ *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"call":` + strconv.Itoa(int(n)) + `}`))
+	})
+
+	first := doCachedRequest(handler, http.MethodGet, "/tenants/1", nil)
+	second := doCachedRequest(handler, http.MethodGet, "/tenants/1", nil)
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected handler to be called once, got %d", calls.Load())
+	}
+
+	if second.Code != http.StatusOK || second.Body.String() != `{"call":1}` {
+		t.Errorf("expected cached response, got %d %s", second.Code, second.Body)
+	}
+
+	if second.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("expected cached headers, got %v", second.Header())
+	}
+
+	if first.Header().Get(httputils.CacheStatusHeader) != "MISS" ||
+		second.Header().Get(httputils.CacheStatusHeader) != "HIT" {
+		t.Errorf("expected MISS then HIT, got %q then %q",
+			first.Header().Get(httputils.CacheStatusHeader), second.Header().Get(httputils.CacheStatusHeader))
+	}
+
+	doCachedRequest(handler, http.MethodGet, "/tenants/2", nil)
+	doCachedRequest(handler, http.MethodGet, "/tenants/1?verbose=true", nil)
+
+	if calls.Load() != 3 {
+		t.Errorf("expected other paths and query strings to miss the cache, got %d calls", calls.Load())
+	}
+}
+
+func TestCacheMiddlewareDefaultConfig(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	//nolint: exhaustruct
+	handler := httputils.CacheMiddleware(httputils.CacheConfig{})(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			_, _ = w.Write([]byte(`{}`))
+		}))
+
+	doCachedRequest(handler, http.MethodGet, "/tenants/1", nil)
+	rr := doCachedRequest(handler, http.MethodGet, "/tenants/1", nil)
+
+	if calls.Load() != 1 || rr.Header().Get(httputils.CacheStatusHeader) != "HIT" {
+		t.Errorf("expected the zero config to cache in memory, got %d calls", calls.Load())
+	}
+}
+
+func TestCacheMiddlewareSkipsUncacheableRequests(t *testing.T) {
+	t.Parallel()
+
+	noStore := http.Header{"Cache-Control": {"no-cache, no-store"}}
+
+	tests := []struct {
+		name          string
+		method        string
+		requestHeader http.Header
+		status        int
+		header        http.Header
+	}{
+		{"unsafe method", http.MethodPost, nil, http.StatusOK, nil},
+		{"request no-store", http.MethodGet, noStore, http.StatusOK, nil},
+		{"response no-store", http.MethodGet, nil, http.StatusOK, noStore},
+		{"response sets cookie", http.MethodGet, nil, http.StatusOK, http.Header{"Set-Cookie": {"session=1"}}},
+		{"client error", http.MethodGet, nil, http.StatusNotFound, nil},
+		{"server error", http.MethodGet, nil, http.StatusInternalServerError, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+
+			//nolint: exhaustruct
+			handler := newCachedHandler(t, httputils.CacheConfig{}, func(w http.ResponseWriter, _ *http.Request) {
+				calls.Add(1)
+
+				for name, values := range tt.header {
+					for _, value := range values {
+						w.Header().Add(name, value)
+					}
+				}
+
+				w.WriteHeader(tt.status)
+			})
+
+			doCachedRequest(handler, tt.method, "/tenants/1", tt.requestHeader)
+			rr := doCachedRequest(handler, tt.method, "/tenants/1", tt.requestHeader)
+
+			if calls.Load() != 2 {
+				t.Errorf("expected handler to be called twice, got %d", calls.Load())
+			}
+
+			if rr.Header().Get(httputils.CacheStatusHeader) != "" {
+				t.Errorf("expected no cache status, got %q", rr.Header().Get(httputils.CacheStatusHeader))
+			}
+		})
+	}
+}
+
+func TestCacheMiddlewareVaryAndScope(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	//nolint: exhaustruct
+	handler := newCachedHandler(t, httputils.CacheConfig{
+		Vary:  []string{"Accept-Language"},
+		Scope: func(r *http.Request) string { return r.Header.Get(httputils.TenantIDHeader) },
+	}, func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	requests := []http.Header{
+		{"Accept-Language": {"en"}, httputils.TenantIDHeader: {"1"}},
+		{"Accept-Language": {"fr"}, httputils.TenantIDHeader: {"1"}},
+		{"Accept-Language": {"en"}, httputils.TenantIDHeader: {"2"}},
+		{"Accept-Language": {"en"}, httputils.TenantIDHeader: {"1"}},
+	}
+
+	for _, header := range requests {
+		doCachedRequest(handler, http.MethodGet, "/tenants", header)
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("expected each language and tenant to be cached separately, got %d calls", calls.Load())
+	}
+}
+
+func TestMemoryCacheStoreExpiry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := testutils.NewFakeClock(time.Now())
+	store := httputils.NewMemoryCacheStoreWithClock(clock)
+	defer store.Close()
+
+	//nolint: exhaustruct
+	if err := store.Set(ctx, "key", httputils.CachedResponse{Status: http.StatusOK}, time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clock.Advance(59 * time.Second)
+
+	if cached, err := store.Get(ctx, "key"); err != nil || cached == nil {
+		t.Fatalf("expected cached response before expiry, got %+v, %v", cached, err)
+	}
+
+	clock.Advance(time.Second)
+
+	if cached, err := store.Get(ctx, "key"); err != nil || cached != nil {
+		t.Errorf("expected no response after expiry, got %+v, %v", cached, err)
+	}
+}
@@ -0,0 +1,26 @@
+package httputils
+
+import "time"
+
+// Clock returns the current time. Time-dependent components such as the in-memory rate limiter and signed
+// cookies accept a Clock so that tests can control time, e.g. with testutils.FakeClock.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is a Clock that returns the system time.
+type RealClock struct{}
+
+// Now returns time.Now().
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// clockOrDefault returns clock, or RealClock if clock is nil.
+func clockOrDefault(clock Clock) Clock { //nolint: ireturn
+	if clock == nil {
+		return RealClock{}
+	}
+
+	return clock
+}
@@ -0,0 +1,293 @@
+package httputils
+
+import (
+	"compress/gzip"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const compressionLevel = 5
+
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		gz, err := gzip.NewWriterLevel(nil, compressionLevel)
+		if err != nil {
+			panic(err)
+		}
+
+		return gz
+	},
+}
+
+// incompressibleContentTypes lists content types that are already compressed.
+var incompressibleContentTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"font/woff",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-bzip2",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/zstd",
+}
+
+// CompressionMiddleware gzips responses for clients that accept gzip encoding. Responses that are already
+// compressed (by content type or an existing Content-Encoding header) and responses without a body are
+// passed through unchanged. If the next handler panics after compressed output has been sent, the rest of the
+// gzip stream is discarded and RecoveryMiddleware aborts the response after logging the panic, since a 500
+// can no longer be sent and appending one would corrupt the body.
+func CompressionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w}
+
+		defer func() {
+			if p := recover(); p != nil {
+				panic(gw.abort(p))
+			}
+
+			err := gw.Close()
+			if err != nil {
+				slog.ErrorContext(r.Context(), "failed to close gzip writer", "error", err)
+			}
+		}()
+
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows a gzip response. A gzip coding takes precedence
+// over the * wildcard regardless of their order, and a coding with a quality value of 0 is not acceptable, so
+// "*, gzip;q=0" refuses gzip.
+func acceptsGzip(acceptEncoding string) bool {
+	quality := 0.0
+	specificity := specificityNone
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		var s int
+
+		switch coding = strings.ToLower(strings.TrimSpace(coding)); coding {
+		case "gzip", "x-gzip":
+			s = specificityExact
+		case "*":
+			s = specificityAny
+		default:
+			continue
+		}
+
+		if s <= specificity {
+			continue
+		}
+
+		quality, specificity = codingQuality(params), s
+	}
+
+	return quality > 0
+}
+
+// codingQuality returns the q parameter of an Accept-Encoding coding's parameters, defaulting to 1.
+func codingQuality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, _ := strings.Cut(param, "=")
+		if !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return parsed
+		}
+	}
+
+	return 1
+}
+
+func isCompressibleContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+
+	if mediaType == "image/svg+xml" {
+		return true
+	}
+
+	for _, incompressible := range incompressibleContentTypes {
+		if strings.HasPrefix(mediaType, incompressible) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// gzipResponseWriter decides whether to compress on the first non-empty Write, or on Flush, once the response
+// headers are known. The status is held back until then so that a response without a body is sent without a
+// Content-Encoding header or an empty gzip stream.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz          *gzip.Writer
+	status      int
+	wroteHeader bool
+	committed   bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+
+	// Informational responses are sent as they are written; the final status follows later.
+	if status >= http.StatusContinue && status < http.StatusOK {
+		w.ResponseWriter.WriteHeader(status)
+
+		return
+	}
+
+	w.wroteHeader = true
+	w.status = status
+}
+
+// commit writes the held back status, compressing the response if compress is set and the response allows it.
+func (w *gzipResponseWriter) commit(compress bool) {
+	if w.committed {
+		return
+	}
+
+	w.committed = true
+
+	header := w.Header()
+	if compress && w.shouldCompress(w.status) {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+
+		gz, _ := gzipWriterPool.Get().(*gzip.Writer)
+		gz.Reset(w.ResponseWriter)
+		w.gz = gz
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+func (w *gzipResponseWriter) shouldCompress(status int) bool {
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	return isCompressibleContentType(header.Get(ContentTypeHeader))
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get(ContentTypeHeader) == "" && len(b) > 0 {
+			w.Header().Set(ContentTypeHeader, http.DetectContentType(b))
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if len(b) == 0 {
+		return 0, nil
+	}
+
+	w.commit(true)
+
+	if w.gz == nil {
+		return w.ResponseWriter.Write(b) //nolint: wrapcheck
+	}
+
+	return w.gz.Write(b) //nolint: wrapcheck
+}
+
+// Flush sends the status and any buffered compressed data to the client.
+func (w *gzipResponseWriter) Flush() {
+	w.WriteHeader(http.StatusOK)
+	w.commit(true)
+
+	if w.gz != nil {
+		err := w.gz.Flush()
+		if err != nil {
+			return
+		}
+	}
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter for use with http.ResponseController.
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// abort handles a panic p raised by the next handler and returns the value to re-panic with. If compression has
+// not started the response is closed as usual; otherwise the gzip stream is dropped without being finished and
+// the panic is marked so that RecoveryMiddleware aborts the response.
+func (w *gzipResponseWriter) abort(p any) any {
+	if w.gz == nil {
+		_ = w.Close()
+
+		return p
+	}
+
+	gz := w.gz
+	w.gz = nil
+
+	gz.Reset(io.Discard)
+	gzipWriterPool.Put(gz)
+
+	if p == http.ErrAbortHandler { //nolint: errorlint
+		return p
+	}
+
+	hp, ok := p.(*handlerPanic)
+	if !ok {
+		hp = &handlerPanic{value: p, stack: debug.Stack()}
+	}
+
+	hp.abort = true
+
+	return hp
+}
+
+// Close sends the status of a response without a body uncompressed, or finishes the gzip stream and returns the
+// gzip writer to the pool.
+func (w *gzipResponseWriter) Close() error {
+	if w.wroteHeader {
+		w.commit(false)
+	}
+
+	if w.gz == nil {
+		return nil
+	}
+
+	gz := w.gz
+	w.gz = nil
+
+	defer gzipWriterPool.Put(gz)
+
+	return gz.Close() //nolint: wrapcheck
+}
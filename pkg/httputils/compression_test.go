@@ -0,0 +1,199 @@
+package httputils_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestCompressionMiddleware(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat(`{"tenantName":"Acme","plan":"free"}`, 100)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		contentType    string
+		expectGzip     bool
+	}{
+		{name: "gzip accepted", acceptEncoding: "gzip, deflate, br", contentType: "application/json", expectGzip: true},
+		{name: "gzip with quality", acceptEncoding: "br;q=1.0, gzip;q=0.8", contentType: "application/json", expectGzip: true},
+		{name: "gzip refused", acceptEncoding: "gzip;q=0", contentType: "application/json", expectGzip: false},
+		{name: "gzip refused with spaces", acceptEncoding: "gzip; q=0.0", contentType: "application/json",
+			expectGzip: false},
+		{name: "gzip refused after wildcard", acceptEncoding: "*, gzip;q=0", contentType: "application/json",
+			expectGzip: false},
+		{name: "wildcard refused", acceptEncoding: "br, *;q=0", contentType: "application/json", expectGzip: false},
+		{name: "wildcard accepted", acceptEncoding: "br;q=0, *", contentType: "application/json", expectGzip: true},
+		{name: "gzip overrides refused wildcard", acceptEncoding: "*;q=0, GZIP;Q=0.5", contentType: "application/json",
+			expectGzip: true},
+		{name: "no accept encoding", acceptEncoding: "", contentType: "application/json", expectGzip: false},
+		{name: "already compressed content type", acceptEncoding: "gzip", contentType: "image/png", expectGzip: false},
+		{name: "svg is compressible", acceptEncoding: "gzip", contentType: "image/svg+xml", expectGzip: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := httputils.CompressionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = w.Write([]byte(body))
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/tenants", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if vary := rr.Header().Get("Vary"); vary != "Accept-Encoding" {
+				t.Errorf("expected Vary Accept-Encoding, got %q", vary)
+			}
+
+			isGzip := rr.Header().Get("Content-Encoding") == "gzip"
+			if isGzip != tt.expectGzip {
+				t.Fatalf("expected gzip %v, got %v", tt.expectGzip, isGzip)
+			}
+
+			actual := rr.Body.String()
+			if isGzip {
+				actual = gunzip(t, rr.Body)
+			}
+
+			if actual != body {
+				t.Errorf("expected body to round-trip, got %q", actual)
+			}
+		})
+	}
+}
+
+func TestCompressionMiddlewareWithoutBody(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range []int{http.StatusOK, http.StatusCreated, http.StatusNoContent} {
+		handler := httputils.CompressionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/tenants", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != status {
+			t.Errorf("expected status %d, got %d", status, rr.Code)
+		}
+
+		if encoding := rr.Header().Get("Content-Encoding"); encoding != "" {
+			t.Errorf("expected no Content-Encoding for status %d, got %q", status, encoding)
+		}
+
+		if rr.Body.Len() != 0 {
+			t.Errorf("expected an empty body for status %d, got %d bytes", status, rr.Body.Len())
+		}
+	}
+}
+
+func TestCompressionMiddlewarePanic(t *testing.T) {
+	t.Parallel()
+
+	handler := httputils.CompressionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("partial"))
+
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rr := httptest.NewRecorder()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic to propagate")
+			}
+		}()
+
+		handler.ServeHTTP(rr, req)
+	}()
+
+	gz, err := gzip.NewReader(rr.Body)
+	if err != nil {
+		t.Fatalf("failed to create gzip reader: %v", err)
+	}
+
+	if _, err := io.ReadAll(gz); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected the gzip stream to be left unfinished, got %v", err)
+	}
+}
+
+func TestCompressionMiddlewarePanicAfterPartialWrite(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	handler := httputils.RecoveryMiddleware(logger)(httputils.CompressionMiddleware(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("partial"))
+
+			panic("boom")
+		}),
+	))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	rr := httptest.NewRecorder()
+
+	func() {
+		defer func() {
+			if p := recover(); p != http.ErrAbortHandler { //nolint: errorlint
+				t.Errorf("expected the response to be aborted, got panic %v", p)
+			}
+		}()
+
+		handler.ServeHTTP(rr, req)
+	}()
+
+	if strings.Contains(rr.Body.String(), "server encountered a problem") {
+		t.Errorf("expected no error response to be appended to the gzip body, got %q", rr.Body.String())
+	}
+
+	if !strings.Contains(buf.String(), `"panic":"boom"`) {
+		t.Errorf("expected the panic to be logged, got %q", buf.String())
+	}
+}
+
+func gunzip(t *testing.T, r io.Reader) string {
+	t.Helper()
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		t.Fatalf("failed to create gzip reader: %v", err)
+	}
+
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("failed to read gzip body: %v", err)
+	}
+
+	return string(data)
+}
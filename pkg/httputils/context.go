@@ -0,0 +1,41 @@
+package httputils
+
+import (
+	"context"
+	"net/http"
+)
+
+type (
+	userContextKey   struct{}
+	tenantContextKey struct{}
+)
+
+// ContextSetUser returns a shallow copy of r whose context carries user, e.g. the account authenticated by
+// session middleware.
+func ContextSetUser[T any](r *http.Request, user T) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), userContextKey{}, user))
+}
+
+// ContextGetUser returns the user stored by ContextSetUser. ok is false if no user was stored or it is not a T.
+func ContextGetUser[T any](r *http.Request) (T, bool) { //nolint: ireturn
+	return UserFromContext[T](r.Context())
+}
+
+// UserFromContext returns the user stored by ContextSetUser in a request's context, for code that only has the
+// context, such as a dbutils.AuditLog Actor. ok is false if no user was stored or it is not a T.
+func UserFromContext[T any](ctx context.Context) (T, bool) { //nolint: ireturn
+	user, ok := ctx.Value(userContextKey{}).(T)
+
+	return user, ok
+}
+
+// ContextSetTenant returns a shallow copy of r whose context carries tenantID. TenantMiddleware calls it with the
+// resolved tenant.
+func ContextSetTenant(r *http.Request, tenantID int64) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), tenantContextKey{}, tenantID))
+}
+
+// ContextGetTenant returns the tenant ID stored by ContextSetTenant. ok is false if no tenant was stored.
+func ContextGetTenant(r *http.Request) (int64, bool) {
+	return TenantIDFromContext(r.Context())
+}
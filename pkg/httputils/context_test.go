@@ -0,0 +1,59 @@
+package httputils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+type contextTestUser struct {
+	ID   int64
+	Name string
+}
+
+func TestContextUser(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if user, ok := httputils.ContextGetUser[contextTestUser](req); ok || user != (contextTestUser{}) {
+		t.Errorf("expected no user, got %+v", user)
+	}
+
+	req = httputils.ContextSetUser(req, contextTestUser{ID: 1, Name: "admin"})
+
+	user, ok := httputils.ContextGetUser[contextTestUser](req)
+	if !ok || user.ID != 1 || user.Name != "admin" {
+		t.Errorf("expected the stored user, got %+v (ok=%v)", user, ok)
+	}
+
+	if _, ok := httputils.ContextGetUser[*contextTestUser](req); ok {
+		t.Error("expected a user of a different type not to be returned")
+	}
+
+	if user, ok := httputils.UserFromContext[contextTestUser](req.Context()); !ok || user.ID != 1 {
+		t.Errorf("expected UserFromContext to return the stored user, got %+v (ok=%v)", user, ok)
+	}
+}
+
+func TestContextTenant(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if tenantID, ok := httputils.ContextGetTenant(req); ok || tenantID != 0 {
+		t.Errorf("expected no tenant, got %d", tenantID)
+	}
+
+	req = httputils.ContextSetTenant(req, 2)
+
+	if tenantID, ok := httputils.ContextGetTenant(req); !ok || tenantID != 2 {
+		t.Errorf("expected tenant 2, got %d (ok=%v)", tenantID, ok)
+	}
+
+	if tenantID, ok := httputils.TenantIDFromContext(req.Context()); !ok || tenantID != 2 {
+		t.Errorf("expected TenantIDFromContext to return tenant 2, got %d (ok=%v)", tenantID, ok)
+	}
+}
@@ -0,0 +1,154 @@
+package httputils_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+var cookieSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func signedCookie(t *testing.T, name, value string, opts httputils.CookieOptions) *http.Cookie {
+	t.Helper()
+
+	rr := httptest.NewRecorder()
+	if err := httputils.SetSignedCookie(rr, name, value, cookieSecret, opts); err != nil {
+		t.Fatalf("failed to set signed cookie: %v", err)
+	}
+
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected 1 cookie, got %d", len(cookies))
+	}
+
+	return cookies[0]
+}
+
+func TestSignedCookieRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	//nolint: exhaustruct
+	cookie := signedCookie(t, "prefs", "theme=dark", httputils.CookieOptions{
+		MaxAge:   time.Hour,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	if !cookie.Secure || !cookie.HttpOnly || cookie.SameSite != http.SameSiteStrictMode || cookie.Path != "/" {
+		t.Errorf("unexpected cookie attributes: %+v", cookie)
+	}
+
+	if cookie.MaxAge != 3600 {
+		t.Errorf("expected max age 3600, got %d", cookie.MaxAge)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+
+	value, err := httputils.ReadSignedCookie(req, "prefs", cookieSecret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if value != "theme=dark" {
+		t.Errorf("expected %q, got %q", "theme=dark", value)
+	}
+}
+
+func TestReadSignedCookieErrors(t *testing.T) {
+	t.Parallel()
+
+	valid := signedCookie(t, "prefs", "theme=dark", httputils.CookieOptions{}) //nolint: exhaustruct
+	payload, signature, _ := strings.Cut(valid.Value, ".")
+	other := signedCookie(t, "prefs", "theme=light", httputils.CookieOptions{}) //nolint: exhaustruct
+	otherPayload, otherSignature, _ := strings.Cut(other.Value, ".")
+
+	//nolint: exhaustruct
+	expired := signedCookie(t, "prefs", "theme=dark", httputils.CookieOptions{
+		MaxAge: time.Hour,
+		Clock:  testutils.NewFakeClock(time.Now().Add(-2 * time.Hour)),
+	})
+
+	tests := []struct {
+		name          string
+		cookie        *http.Cookie
+		secret        []byte
+		expectedError error
+	}{
+		{name: "missing cookie", cookie: nil, secret: cookieSecret, expectedError: http.ErrNoCookie},
+		{
+			name:          "tampered value",
+			cookie:        &http.Cookie{Name: "prefs", Value: otherPayload + "." + signature},
+			secret:        cookieSecret,
+			expectedError: httputils.ErrInvalidCookieSignature,
+		},
+		{
+			name:          "tampered signature",
+			cookie:        &http.Cookie{Name: "prefs", Value: payload + "." + otherSignature},
+			secret:        cookieSecret,
+			expectedError: httputils.ErrInvalidCookieSignature,
+		},
+		{
+			name:          "unsigned value",
+			cookie:        &http.Cookie{Name: "prefs", Value: "theme=dark"},
+			secret:        cookieSecret,
+			expectedError: httputils.ErrInvalidCookieSignature,
+		},
+		{
+			name:          "renamed cookie",
+			cookie:        &http.Cookie{Name: "session", Value: valid.Value},
+			secret:        cookieSecret,
+			expectedError: httputils.ErrInvalidCookieSignature,
+		},
+		{
+			name:          "wrong secret",
+			cookie:        valid,
+			secret:        []byte("another-secret"),
+			expectedError: httputils.ErrInvalidCookieSignature,
+		},
+		{name: "expired", cookie: expired, secret: cookieSecret, expectedError: httputils.ErrCookieExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+
+			name := "prefs"
+			if tt.cookie != nil {
+				name = tt.cookie.Name
+			}
+
+			_, err := httputils.ReadSignedCookie(req, name, tt.secret)
+
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestSetSignedCookieTooLarge(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+
+	//nolint: exhaustruct
+	err := httputils.SetSignedCookie(rr, "prefs", strings.Repeat("a", 4096), cookieSecret, httputils.CookieOptions{})
+	if !errors.Is(err, httputils.ErrCookieTooLarge) {
+		t.Errorf("expected ErrCookieTooLarge, got %v", err)
+	}
+
+	if len(rr.Result().Cookies()) != 0 {
+		t.Error("expected no cookie to be set")
+	}
+}
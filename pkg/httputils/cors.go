@@ -0,0 +1,146 @@
+package httputils
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const corsWildcard = "*"
+
+// CORSConfig configures CORSMiddleware.
+type CORSConfig struct {
+	// AllowedOrigins is the list of origins permitted to make cross-origin requests.
+	// Use "*" to allow any origin.
+	AllowedOrigins []string
+	// AllowedMethods is the list of methods permitted in preflight requests.
+	// Defaults to GET, POST, PUT, PATCH and DELETE.
+	AllowedMethods []string
+	// AllowedHeaders is the list of request headers permitted in preflight requests.
+	// Defaults to Authorization and Content-Type.
+	AllowedHeaders []string
+	// ExposedHeaders is the list of response headers the browser may expose to the client.
+	ExposedHeaders []string
+	// AllowCredentials allows cookies and authorization headers to be sent cross-origin.
+	// When set, a wildcard origin is echoed back as the request origin since browsers
+	// reject "Access-Control-Allow-Origin: *" for credentialed requests.
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache preflight responses. Zero omits the header.
+	MaxAge time.Duration
+}
+
+func (c CORSConfig) allowsAnyOrigin() bool {
+	return slices.Contains(c.AllowedOrigins, corsWildcard)
+}
+
+func (c CORSConfig) allowsOrigin(origin string) bool {
+	return c.allowsAnyOrigin() || slices.Contains(c.AllowedOrigins, origin)
+}
+
+func (c CORSConfig) allowsMethod(method string) bool {
+	return slices.ContainsFunc(c.AllowedMethods, func(allowed string) bool {
+		return strings.EqualFold(allowed, method)
+	})
+}
+
+func (c CORSConfig) allowsHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+
+		if !slices.ContainsFunc(c.AllowedHeaders, func(allowed string) bool {
+			return allowed == corsWildcard || strings.EqualFold(allowed, header)
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CORSMiddleware handles cross-origin requests. Preflight OPTIONS requests are answered directly
+// with the allowed methods and headers; all other requests from allowed origins have the
+// Access-Control-Allow-Origin header set before being passed to the next handler.
+func CORSMiddleware(cfg CORSConfig) func(next http.Handler) http.Handler {
+	if len(cfg.AllowedMethods) == 0 {
+		cfg.AllowedMethods = []string{
+			http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+		}
+	}
+
+	if len(cfg.AllowedHeaders) == 0 {
+		cfg.AllowedHeaders = []string{"Authorization", ContentTypeHeader}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Origin")
+
+			origin := r.Header.Get("Origin")
+
+			isPreflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if isPreflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+				handlePreflight(w, r, cfg, origin)
+
+				return
+			}
+
+			if origin != "" && cfg.allowsOrigin(origin) {
+				setAllowOriginHeaders(w, cfg, origin)
+
+				if len(cfg.ExposedHeaders) > 0 {
+					w.Header().Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ", "))
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// handlePreflight responds to a preflight request. Disallowed preflights receive a response without
+// CORS headers so the browser blocks the actual request.
+func handlePreflight(w http.ResponseWriter, r *http.Request, cfg CORSConfig, origin string) {
+	requestedMethod := r.Header.Get("Access-Control-Request-Method")
+	requestedHeaders := r.Header.Get("Access-Control-Request-Headers")
+
+	if origin == "" ||
+		!cfg.allowsOrigin(origin) ||
+		!cfg.allowsMethod(requestedMethod) ||
+		!cfg.allowsHeaders(requestedHeaders) {
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	setAllowOriginHeaders(w, cfg, origin)
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
+
+	if requestedHeaders != "" {
+		w.Header().Set("Access-Control-Allow-Headers", requestedHeaders)
+	}
+
+	if cfg.MaxAge > 0 {
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func setAllowOriginHeaders(w http.ResponseWriter, cfg CORSConfig, origin string) {
+	if cfg.allowsAnyOrigin() && !cfg.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Origin", corsWildcard)
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+
+	if cfg.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
@@ -0,0 +1,164 @@
+package httputils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                string
+		config              httputils.CORSConfig
+		method              string
+		headers             map[string]string
+		expectedStatus      int
+		expectedHeaders     map[string]string
+		expectNextCalled    bool
+		expectNoAllowOrigin bool
+	}{
+		{
+			name:             "simple request from allowed origin",
+			config:           httputils.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}},
+			method:           http.MethodGet,
+			headers:          map[string]string{"Origin": "https://app.example.com"},
+			expectedStatus:   http.StatusOK,
+			expectedHeaders:  map[string]string{"Access-Control-Allow-Origin": "https://app.example.com"},
+			expectNextCalled: true,
+		},
+		{
+			name:                "simple request from disallowed origin",
+			config:              httputils.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}},
+			method:              http.MethodGet,
+			headers:             map[string]string{"Origin": "https://evil.example.com"},
+			expectedStatus:      http.StatusOK,
+			expectNextCalled:    true,
+			expectNoAllowOrigin: true,
+		},
+		{
+			name:             "wildcard origin without credentials",
+			config:           httputils.CORSConfig{AllowedOrigins: []string{"*"}},
+			method:           http.MethodGet,
+			headers:          map[string]string{"Origin": "https://any.example.com"},
+			expectedStatus:   http.StatusOK,
+			expectedHeaders:  map[string]string{"Access-Control-Allow-Origin": "*"},
+			expectNextCalled: true,
+		},
+		{
+			name:           "wildcard origin with credentials echoes origin",
+			config:         httputils.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			method:         http.MethodGet,
+			headers:        map[string]string{"Origin": "https://any.example.com"},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://any.example.com",
+				"Access-Control-Allow-Credentials": "true",
+			},
+			expectNextCalled: true,
+		},
+		{
+			name: "preflight from allowed origin",
+			config: httputils.CORSConfig{
+				AllowedOrigins: []string{"https://app.example.com"},
+				AllowedMethods: []string{http.MethodGet, http.MethodPost},
+				AllowedHeaders: []string{"Content-Type", "X-Request-ID"},
+				MaxAge:         10 * time.Minute,
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  http.MethodPost,
+				"Access-Control-Request-Headers": "content-type, x-request-id",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://app.example.com",
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Allow-Headers": "content-type, x-request-id",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name: "preflight with disallowed method",
+			config: httputils.CORSConfig{
+				AllowedOrigins: []string{"https://app.example.com"},
+				AllowedMethods: []string{http.MethodGet},
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": http.MethodDelete,
+			},
+			expectedStatus:      http.StatusNoContent,
+			expectNoAllowOrigin: true,
+		},
+		{
+			name:   "preflight with disallowed header",
+			config: httputils.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  http.MethodGet,
+				"Access-Control-Request-Headers": "X-Custom",
+			},
+			expectedStatus:      http.StatusNoContent,
+			expectNoAllowOrigin: true,
+		},
+		{
+			name:   "preflight from disallowed origin",
+			config: httputils.CORSConfig{AllowedOrigins: []string{"https://app.example.com"}},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://evil.example.com",
+				"Access-Control-Request-Method": http.MethodGet,
+			},
+			expectedStatus:      http.StatusNoContent,
+			expectNoAllowOrigin: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			nextCalled := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				nextCalled = true
+
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/api/tenants", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			rr := httptest.NewRecorder()
+			httputils.CORSMiddleware(tt.config)(next).ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if nextCalled != tt.expectNextCalled {
+				t.Errorf("expected next called to be %v, got %v", tt.expectNextCalled, nextCalled)
+			}
+
+			for key, expected := range tt.expectedHeaders {
+				if actual := rr.Header().Get(key); actual != expected {
+					t.Errorf("expected header %s to be %q, got %q", key, expected, actual)
+				}
+			}
+
+			if tt.expectNoAllowOrigin && rr.Header().Get("Access-Control-Allow-Origin") != "" {
+				t.Errorf("expected no Access-Control-Allow-Origin header, got %q",
+					rr.Header().Get("Access-Control-Allow-Origin"))
+			}
+		})
+	}
+}
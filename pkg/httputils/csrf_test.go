@@ -0,0 +1,138 @@
+package httputils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	t.Parallel()
+
+	const token = "valid-token"
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		cookie         string
+		header         string
+		formField      string
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "safe method without token", method: http.MethodGet, path: "/tenants", expectedStatus: http.StatusOK},
+		{
+			name: "valid POST with header", method: http.MethodPost, path: "/tenants",
+			cookie: token, header: token, expectedStatus: http.StatusOK,
+		},
+		{
+			name: "valid POST with form field", method: http.MethodPost, path: "/tenants",
+			cookie: token, formField: token, expectedStatus: http.StatusOK,
+		},
+		{
+			name: "missing token", method: http.MethodPost, path: "/tenants",
+			cookie: token, expectedStatus: http.StatusForbidden, expectedError: "CSRF token missing",
+		},
+		{
+			name: "missing cookie", method: http.MethodDelete, path: "/tenants/1",
+			header: token, expectedStatus: http.StatusForbidden, expectedError: "CSRF token missing",
+		},
+		{
+			name: "mismatched token", method: http.MethodPost, path: "/tenants",
+			cookie: token, header: "forged-token", expectedStatus: http.StatusForbidden,
+			expectedError: "CSRF token mismatch",
+		},
+		{name: "exempt path", method: http.MethodPost, path: "/webhooks", expectedStatus: http.StatusOK},
+		{name: "exempt path prefix", method: http.MethodPost, path: "/api/hooks/stripe", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			//nolint: exhaustruct
+			handler := httputils.CSRFMiddleware(httputils.CSRFConfig{
+				ExemptPaths: []string{"/webhooks", "/api/hooks/*"},
+			})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			var req *http.Request
+			if tt.formField != "" {
+				form := url.Values{httputils.DefaultCSRFFieldName: {tt.formField}}
+				req = httptest.NewRequest(tt.method, tt.path, strings.NewReader(form.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			} else {
+				req = httptest.NewRequest(tt.method, tt.path, nil)
+			}
+
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: httputils.DefaultCSRFCookieName, Value: tt.cookie}) //nolint: exhaustruct
+			}
+
+			if tt.header != "" {
+				req.Header.Set(httputils.DefaultCSRFHeaderName, tt.header)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedError == "" {
+				return
+			}
+
+			var body map[string]any
+			if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if body["errors"] != tt.expectedError {
+				t.Errorf("expected error %q, got %v", tt.expectedError, body["errors"])
+			}
+		})
+	}
+}
+
+func TestCSRFMiddlewareSetsCookie(t *testing.T) {
+	t.Parallel()
+
+	var contextToken string
+
+	handler := httputils.CSRFMiddleware(httputils.CSRFConfig{})( //nolint: exhaustruct
+		http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			contextToken = httputils.CSRFToken(r)
+		}),
+	)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != httputils.DefaultCSRFCookieName {
+		t.Fatalf("expected a %s cookie, got %v", httputils.DefaultCSRFCookieName, cookies)
+	}
+
+	if cookies[0].Value == "" || cookies[0].Value != contextToken {
+		t.Errorf("expected cookie token %q to match request token %q", cookies[0].Value, contextToken)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if len(rr.Result().Cookies()) != 0 {
+		t.Error("expected the existing token cookie to be reused")
+	}
+}
@@ -0,0 +1,38 @@
+package httputils
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+)
+
+type dataEnvelopeContextKey struct{}
+
+// GetDataEnvelope reports whether the JSON_DATA_ENVELOPE environment variable enables wrapping successful
+// responses in a data envelope. It defaults to false.
+func GetDataEnvelope() bool {
+	return parser.ParseEnvBool("JSON_DATA_ENVELOPE", false)
+}
+
+// DataEnvelopeMiddleware sets whether RespondJSON wraps 2xx response bodies as {"data": ...} for the request,
+// mirroring the {"errors": ...} envelope of error responses. Error responses are not affected.
+func DataEnvelopeMiddleware(enabled bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), dataEnvelopeContextKey{}, enabled)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// wrapDataEnvelope returns data wrapped in a data envelope if DataEnvelopeMiddleware enabled it for the request
+// and status is a 2xx status.
+func wrapDataEnvelope(ctx context.Context, status int, data interface{}) interface{} {
+	enabled, _ := ctx.Value(dataEnvelopeContextKey{}).(bool)
+	if !enabled || status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return data
+	}
+
+	return map[string]interface{}{"data": data}
+}
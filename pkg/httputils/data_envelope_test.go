@@ -0,0 +1,58 @@
+package httputils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestDataEnvelopeMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		enabled  bool
+		status   int
+		expected string
+	}{
+		{"disabled", false, http.StatusOK, "{\n\t\"id\": 1\n}\n"},
+		{"enabled", true, http.StatusOK, "{\n\t\"data\": {\n\t\t\"id\": 1\n\t}\n}\n"},
+		{"enabled created", true, http.StatusCreated, "{\n\t\"data\": {\n\t\t\"id\": 1\n\t}\n}\n"},
+		{"enabled redirect", true, http.StatusMultipleChoices, "{\n\t\"id\": 1\n}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := httputils.DataEnvelopeMiddleware(tt.enabled)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					httputils.RespondJSON(w, r, tt.status, map[string]int{"id": 1}, nil)
+				}),
+			)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rr.Body.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestDataEnvelopeMiddlewareLeavesErrorsUnwrapped(t *testing.T) {
+	t.Parallel()
+
+	handler := httputils.DataEnvelopeMiddleware(true)(http.HandlerFunc(httputils.NotFoundResponse))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	expected := "{\n\t\"errors\": \"the requested resource could not be found\"\n}\n"
+	if rr.Body.String() != expected {
+		t.Errorf("expected %q, got %q", expected, rr.Body.String())
+	}
+}
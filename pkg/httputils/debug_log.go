@@ -0,0 +1,203 @@
+package httputils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+)
+
+const defaultDebugLogMaxBodyBytes = 4096
+
+// DebugLogConfig configures DebugLogMiddleware.
+type DebugLogConfig struct {
+	// Logger receives the request and response records. slog.Default() is used if it is nil.
+	Logger *slog.Logger
+	// MaxBodyBytes is the number of bytes of each body that are logged. Defaults to 4096.
+	MaxBodyBytes int
+	// Redactor masks sensitive headers and JSON body fields. Defaults to DefaultRedactedFields.
+	Redactor *Redactor
+}
+
+// GetDebugLogMiddleware returns a DebugLogMiddleware writing to logger if the DEBUG_HTTP environment variable
+// is true, or nil otherwise. Bodies are truncated to DEBUG_HTTP_MAX_BODY_BYTES, which defaults to 4096, and
+// the fields in LOG_REDACT_FIELDS are redacted along with DefaultRedactedFields.
+func GetDebugLogMiddleware(logger *slog.Logger) func(next http.Handler) http.Handler {
+	if !parser.ParseEnvBool("DEBUG_HTTP", false) {
+		return nil
+	}
+
+	maxBodyBytes, err := parser.ParseEnvInt("DEBUG_HTTP_MAX_BODY_BYTES", defaultDebugLogMaxBodyBytes)
+	if err != nil {
+		panic(err)
+	}
+
+	return DebugLogMiddleware(DebugLogConfig{
+		Logger:       logger,
+		MaxBodyBytes: maxBodyBytes,
+		Redactor:     NewRedactor(getRedactedFields()...),
+	})
+}
+
+// DebugLogMiddleware logs each request's headers and body and the response's status, headers and body at
+// DEBUG level, for debugging integrations. Only the first MaxBodyBytes of each body are kept; the handler
+// still reads the full request body and the response is written through to the client as it is produced,
+// so streaming and flushing are unaffected.
+//
+// Sensitive headers and JSON fields are redacted. JSON bodies that cannot be parsed, including truncated
+// ones, are omitted since they cannot be redacted.
+func DebugLogMiddleware(cfg DebugLogConfig) func(next http.Handler) http.Handler {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = defaultDebugLogMaxBodyBytes
+	}
+
+	if cfg.Redactor == nil {
+		cfg.Redactor = NewRedactor(DefaultRedactedFields...)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cfg.Logger.Enabled(r.Context(), slog.LevelDebug) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			requestBody, requestTruncated := captureRequestBody(r, cfg.MaxBodyBytes)
+
+			cfg.Logger.LogAttrs(r.Context(), slog.LevelDebug, "http request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.RequestURI()),
+				slog.Any("headers", cfg.Redactor.redactHeader(r.Header)),
+				slog.String("body", cfg.Redactor.redactBody(r.Header, requestBody, requestTruncated)),
+				slog.Bool("body_truncated", requestTruncated),
+			)
+
+			dw := &debugLogWriter{ResponseWriter: w, body: bytes.Buffer{}, maxBodyBytes: cfg.MaxBodyBytes}
+
+			defer func() {
+				status := dw.status
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				cfg.Logger.LogAttrs(r.Context(), slog.LevelDebug, "http response",
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.RequestURI()),
+					slog.Int("status", status),
+					slog.Any("headers", cfg.Redactor.redactHeader(w.Header())),
+					slog.String("body", cfg.Redactor.redactBody(w.Header(), dw.body.Bytes(), dw.truncated)),
+					slog.Bool("body_truncated", dw.truncated),
+				)
+			}()
+
+			next.ServeHTTP(dw, r)
+		})
+	}
+}
+
+// captureRequestBody reads up to maxBytes of the request body and replaces the body with one that replays
+// the captured bytes before the rest of the original body.
+func captureRequestBody(r *http.Request, maxBytes int) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, false
+	}
+
+	captured, err := io.ReadAll(io.LimitReader(r.Body, int64(maxBytes)+1))
+
+	var rest io.Reader = r.Body
+	if err != nil {
+		rest = errReader{err}
+	}
+
+	r.Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(captured), rest), Closer: r.Body}
+
+	if len(captured) > maxBytes {
+		return captured[:maxBytes], true
+	}
+
+	return captured, false
+}
+
+// replayBody reads from a reader that replays captured bytes and closes the original body.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// errReader returns the error hit while capturing the request body so that the handler sees it at the same
+// point in the body.
+type errReader struct {
+	err error
+}
+
+func (e errReader) Read([]byte) (int, error) {
+	return 0, e.err
+}
+
+// redactBody returns body as a string for logging. JSON bodies have sensitive fields redacted.
+func (r *Redactor) redactBody(header http.Header, body []byte, truncated bool) string {
+	if len(body) == 0 || !strings.Contains(header.Get(ContentTypeHeader), "json") {
+		return string(body)
+	}
+
+	if truncated || !json.Valid(body) {
+		return "[unparseable JSON body omitted]"
+	}
+
+	return string(r.redactRawJSON(body))
+}
+
+// debugLogWriter writes the response through to the client while keeping its status and the first
+// maxBodyBytes of its body.
+type debugLogWriter struct {
+	http.ResponseWriter
+	body         bytes.Buffer
+	maxBodyBytes int
+	status       int
+	truncated    bool
+}
+
+func (dw *debugLogWriter) WriteHeader(status int) {
+	if dw.status == 0 {
+		dw.status = status
+	}
+
+	dw.ResponseWriter.WriteHeader(status)
+}
+
+func (dw *debugLogWriter) Write(b []byte) (int, error) {
+	if dw.status == 0 {
+		dw.status = http.StatusOK
+	}
+
+	remaining := dw.maxBodyBytes - dw.body.Len()
+	if len(b) > remaining {
+		dw.body.Write(b[:max(remaining, 0)])
+		dw.truncated = true
+	} else {
+		dw.body.Write(b)
+	}
+
+	return dw.ResponseWriter.Write(b) //nolint: wrapcheck
+}
+
+// Flush flushes buffered data to the client so that streamed responses are not held back.
+func (dw *debugLogWriter) Flush() {
+	if flusher, ok := dw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter for use with http.ResponseController.
+func (dw *debugLogWriter) Unwrap() http.ResponseWriter {
+	return dw.ResponseWriter
+}
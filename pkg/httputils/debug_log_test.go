@@ -0,0 +1,202 @@
+package httputils_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func newDebugLogHandler(t *testing.T, buf *bytes.Buffer, maxBodyBytes int, next http.HandlerFunc) http.Handler {
+	t.Helper()
+
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})) //nolint: exhaustruct
+
+	//nolint: exhaustruct
+	return httputils.DebugLogMiddleware(httputils.DebugLogConfig{Logger: logger, MaxBodyBytes: maxBodyBytes})(next)
+}
+
+func decodeDebugLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var record map[string]any
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("failed to decode log record: %v", err)
+		}
+
+		records = append(records, record)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected a request and a response record, got %d", len(records))
+	}
+
+	return records
+}
+
+func TestDebugLogMiddlewareCapturesBodies(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	var handlerBody string
+
+	handler := newDebugLogHandler(t, &buf, 1024, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		handlerBody = string(body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1,"password":"secret"}`))
+	})
+
+	requestBody := `{"name":"acme","authorization":"token"}`
+	req := httptest.NewRequest(http.MethodPost, "/tenants?verbose=true", strings.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if handlerBody != requestBody {
+		t.Errorf("expected handler to read the full request body, got %q", handlerBody)
+	}
+
+	if rr.Code != http.StatusCreated || rr.Body.String() != `{"id":1,"password":"secret"}` {
+		t.Errorf("expected response to be written through, got %d %s", rr.Code, rr.Body)
+	}
+
+	records := decodeDebugLogRecords(t, &buf)
+	request, response := records[0], records[1]
+
+	if request["level"] != "DEBUG" || request["path"] != "/tenants?verbose=true" {
+		t.Errorf("unexpected request record: %v", request)
+	}
+
+	if request["body"] != `{"authorization":"***","name":"acme"}` {
+		t.Errorf("expected redacted request body, got %v", request["body"])
+	}
+
+	headers, _ := request["headers"].(map[string]any)
+	if authorization, _ := headers["Authorization"].([]any); len(authorization) != 1 || authorization[0] != "***" {
+		t.Errorf("expected Authorization header to be redacted, got %v", headers["Authorization"])
+	}
+
+	if response["status"] != float64(http.StatusCreated) || response["body"] != `{"id":1,"password":"secret"}` {
+		t.Errorf("unexpected response record: %v", response)
+	}
+}
+
+func TestDebugLogMiddlewareTruncatesBodies(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	var handlerBody string
+
+	handler := newDebugLogHandler(t, &buf, 5, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		handlerBody = string(body)
+
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("abc"))
+		_, _ = w.Write([]byte("defgh"))
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("0123456789"))
+	req.Header.Set("Content-Type", "text/plain")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if handlerBody != "0123456789" {
+		t.Errorf("expected handler to read the full request body, got %q", handlerBody)
+	}
+
+	if rr.Body.String() != "abcdefgh" {
+		t.Errorf("expected full response to be written, got %q", rr.Body)
+	}
+
+	records := decodeDebugLogRecords(t, &buf)
+
+	tests := []struct {
+		name   string
+		record map[string]any
+		body   string
+	}{
+		{"request", records[0], "01234"},
+		{"response", records[1], "abcde"},
+	}
+
+	for _, tt := range tests {
+		if tt.record["body"] != tt.body || tt.record["body_truncated"] != true {
+			t.Errorf("%s: expected truncated body %q, got %v (truncated: %v)",
+				tt.name, tt.body, tt.record["body"], tt.record["body_truncated"])
+		}
+	}
+}
+
+func TestDebugLogMiddlewareOmitsTruncatedJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	handler := newDebugLogHandler(t, &buf, 10, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"user":"a","password":"secret"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	records := decodeDebugLogRecords(t, &buf)
+	if body, _ := records[0]["body"].(string); strings.Contains(body, "secret") || strings.Contains(body, "user") {
+		t.Errorf("expected truncated JSON body to be omitted, got %q", body)
+	}
+}
+
+func TestDebugLogMiddlewareSkipsWhenDebugDisabled(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	//nolint: exhaustruct
+	handler := httputils.DebugLogMiddleware(httputils.DebugLogConfig{Logger: logger})(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing to be logged below DEBUG level, got %s", buf.String())
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestGetDebugLogMiddleware(t *testing.T) {
+	t.Setenv("DEBUG_HTTP", "false")
+
+	if httputils.GetDebugLogMiddleware(slog.Default()) != nil {
+		t.Error("expected no middleware when DEBUG_HTTP is false")
+	}
+
+	t.Setenv("DEBUG_HTTP", "true")
+
+	if httputils.GetDebugLogMiddleware(slog.Default()) == nil {
+		t.Error("expected middleware when DEBUG_HTTP is true")
+	}
+}
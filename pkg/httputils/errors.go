@@ -1,16 +1,31 @@
 package httputils
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"html/template"
+	"io"
 	"log/slog"
+	"math"
 	"net/http"
 	"runtime/debug"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/parser"
+	"github.com/gurch101/gowebutils/pkg/stringutils"
 	"github.com/gurch101/gowebutils/pkg/validation"
 )
 
+const (
+	serverErrorMessage  = "the server encountered a problem and could not process your request"
+	notFoundMessage     = "the requested resource could not be found"
+	editConflictMessage = "unable to update the record due to an edit conflict, please try again"
+)
+
 func logError(r *http.Request, err error) {
 	slog.ErrorContext(
 		r.Context(),
@@ -21,11 +36,51 @@ func logError(r *http.Request, err error) {
 	)
 }
 
+// errorPageTemplate renders error responses for clients that prefer HTML.
+var errorPageTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Status}} {{.StatusText}}</title>
+</head>
+<body>
+<h1>{{.Status}} {{.StatusText}}</h1>
+<ul>
+{{- range .Messages}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+</body>
+</html>
+`))
+
+// errorResponse writes message in the format the client prefers based on its Accept header. JSON is
+// used unless the client prefers HTML or plain text, in the structure selected by ErrorFormatMiddleware.
 func errorResponse(w http.ResponseWriter, r *http.Request, status int, message interface{}) {
-	// Write the response using the writeJSON() helper. If this happens to return an error
-	// then log it, and fall back to sending the client an empty response with a 500 Internal
-	// Server Error status code
-	err := WriteJSON(w, status, map[string]any{"errors": message}, nil)
+	var err error
+
+	message = caseErrorFields(message, contextGetKeyCase(r.Context()))
+
+	contentType := NegotiateContentType(
+		r.Header.Get("Accept"), mediaTypeJSON, mediaTypeProblemJSON, mediaTypeHTML, mediaTypePlain,
+	)
+	if contentType == mediaTypeJSON && contextGetErrorFormat(r.Context()) == ErrorFormatProblem {
+		contentType = mediaTypeProblemJSON
+	}
+
+	switch contentType {
+	case mediaTypeProblemJSON:
+		err = writeProblemJSON(w, r, status, message)
+	case mediaTypeHTML:
+		err = writeHTMLError(w, status, message)
+	case mediaTypePlain:
+		err = writePlainTextError(w, status, message)
+	default:
+		err = WriteJSON(w, status, map[string]any{"errors": message}, nil)
+	}
+
+	// If writing the response returns an error then log it, and fall back to sending the client
+	// an empty response with a 500 Internal Server Error status code
 	if err != nil {
 		logError(r, err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -37,13 +92,89 @@ func errorResponse(w http.ResponseWriter, r *http.Request, status int, message i
 	}
 }
 
-// serverErrorResponse method is used when our application encounters an unexpected problem
+// caseErrorFields converts the field names of validation errors in message to keyCase so that they match the
+// keys of JSON responses.
+func caseErrorFields(message interface{}, keyCase KeyCase) interface{} {
+	validationErrors, ok := message.([]validation.Error)
+	if !ok || keyCase != KeyCaseSnake {
+		return message
+	}
+
+	cased := make([]validation.Error, len(validationErrors))
+	for i, validationError := range validationErrors {
+		cased[i] = validation.Error{
+			Field:   stringutils.CamelToSnake(validationError.Field),
+			Message: validationError.Message,
+		}
+	}
+
+	return cased
+}
+
+func writeHTMLError(w http.ResponseWriter, status int, message interface{}) error {
+	var buf bytes.Buffer
+
+	err := errorPageTemplate.Execute(&buf, map[string]any{
+		"Status":     status,
+		"StatusText": http.StatusText(status),
+		"Messages":   errorMessages(message),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render error page: %w", err)
+	}
+
+	w.Header().Set(ContentTypeHeader, "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	if _, err := buf.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write error page: %w", err)
+	}
+
+	return nil
+}
+
+func writePlainTextError(w http.ResponseWriter, status int, message interface{}) error {
+	var sb strings.Builder
+
+	sb.WriteString(strconv.Itoa(status) + " " + http.StatusText(status) + "\n")
+
+	for _, line := range errorMessages(message) {
+		sb.WriteString(line + "\n")
+	}
+
+	w.Header().Set(ContentTypeHeader, "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("failed to write error: %w", err)
+	}
+
+	return nil
+}
+
+// errorMessages flattens an error response message into human readable lines.
+func errorMessages(message interface{}) []string {
+	switch m := message.(type) {
+	case string:
+		return []string{m}
+	case []validation.Error:
+		lines := make([]string, 0, len(m))
+		for _, e := range m {
+			lines = append(lines, e.Field+": "+e.Message)
+		}
+
+		return lines
+	default:
+		return []string{fmt.Sprint(m)}
+	}
+}
+
+// ServerErrorResponse method is used when our application encounters an unexpected problem
 // at runtime. it logs the detailed error message and returns a 500 Internal Server Error.
 func ServerErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
 	logError(r, err)
 
-	message := "the server encountered a problem and could not process your request"
-	errorResponse(w, r, http.StatusInternalServerError, message)
+	errorResponse(w, r, http.StatusInternalServerError, serverErrorMessage)
 }
 
 // UnprocessableEntityResponse method is used to send a 422 Unprocessable Entity status code.
@@ -51,6 +182,16 @@ func UnprocessableEntityResponse(w http.ResponseWriter, r *http.Request, err err
 	errorResponse(w, r, http.StatusUnprocessableEntity, err.Error())
 }
 
+// UnsupportedMediaTypeResponse sends a JSON-formatted error message with 415 Unsupported Media Type status code.
+func UnsupportedMediaTypeResponse(w http.ResponseWriter, r *http.Request, err error) {
+	errorResponse(w, r, http.StatusUnsupportedMediaType, err.Error())
+}
+
+// PayloadTooLargeResponse sends a JSON-formatted error message with 413 Content Too Large status code.
+func PayloadTooLargeResponse(w http.ResponseWriter, r *http.Request, err error) {
+	errorResponse(w, r, http.StatusRequestEntityTooLarge, err.Error())
+}
+
 // BadRequestResponse sends a JSON-formatted error message with 400 Bad Request status code.
 func BadRequestResponse(w http.ResponseWriter, r *http.Request, err error) {
 	errorResponse(w, r, http.StatusBadRequest, err.Error())
@@ -61,17 +202,28 @@ func FailedValidationResponse(w http.ResponseWriter, r *http.Request, errors []v
 	errorResponse(w, r, http.StatusBadRequest, errors)
 }
 
+// ForbiddenResponse sends a JSON-formatted error message with 403 Forbidden status code. It is used when
+// the client is identified but not allowed to perform the request.
+func ForbiddenResponse(w http.ResponseWriter, r *http.Request, err error) {
+	errorResponse(w, r, http.StatusForbidden, err.Error())
+}
+
 // NotFoundResponse method is used to send a 404 Not Found status code.
 func NotFoundResponse(w http.ResponseWriter, r *http.Request) {
-	message := "the requested resource could not be found"
-	errorResponse(w, r, http.StatusNotFound, message)
+	errorResponse(w, r, http.StatusNotFound, notFoundMessage)
+}
+
+// MethodNotAllowedResponse method is used to send a 405 Method Not Allowed status code when a route exists
+// for the requested path but not for the request method.
+func MethodNotAllowedResponse(w http.ResponseWriter, r *http.Request) {
+	message := fmt.Sprintf("the %s method is not supported for this resource", r.Method)
+	errorResponse(w, r, http.StatusMethodNotAllowed, message)
 }
 
 // EditConflictResponse method is used to send a 409 Conflict status code. This can occur
 // when we try to create a new record in the database and another user has updated the same record concurrently.
 func EditConflictResponse(w http.ResponseWriter, r *http.Request) {
-	message := "unable to update the record due to an edit conflict, please try again"
-	errorResponse(w, r, http.StatusConflict, message)
+	errorResponse(w, r, http.StatusConflict, editConflictMessage)
 }
 
 // RateLimitExceededResponse method is used to send a 429 Too Many Requests status code.
@@ -81,6 +233,22 @@ func RateLimitExceededResponse(w http.ResponseWriter, r *http.Request) {
 	errorResponse(w, r, http.StatusTooManyRequests, message)
 }
 
+// QuotaExceededResponse method is used to send a 429 Too Many Requests status code when a tenant has used
+// up its request quota. The Retry-After header is set to the number of seconds until the quota resets.
+func QuotaExceededResponse(w http.ResponseWriter, r *http.Request, resetIn time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(resetIn.Seconds()))))
+
+	message := "request quota exceeded"
+	errorResponse(w, r, http.StatusTooManyRequests, message)
+}
+
+// TimeoutResponse method is used to send a 503 Service Unavailable status code when a request
+// takes longer than the configured request timeout.
+func TimeoutResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the request timed out, please try again"
+	errorResponse(w, r, http.StatusServiceUnavailable, message)
+}
+
 // UnauthorizedResponse method is used to send a 401 Unauthorized status code.
 // This can occur if a user tries to access a protected resource without supplying valid credentials.
 // If the request is made to an api endpoint, we will return a JSON response. Otherwise, we will redirect
@@ -97,16 +265,35 @@ func UnauthorizedResponse(w http.ResponseWriter, r *http.Request) {
 // HandleErrorResponse method is a utility function that will return the appropriate
 // error from the service layer of our application.
 func HandleErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	status, body := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		logError(r, err)
+	}
+
+	errorResponse(w, r, status, body)
+}
+
+// errorStatus returns the status code and error body that an error from the service layer is reported with,
+// so that HandleErrorResponse and BatchFailure agree. Unrecognized errors are reported as server errors
+// without exposing their message.
+func errorStatus(err error) (int, any) {
 	var validationErr validation.Error
 
 	switch {
 	case errors.As(err, &validationErr):
-		FailedValidationResponse(w, r, []validation.Error{validationErr})
+		return http.StatusBadRequest, []validation.Error{validationErr}
+	case errors.Is(err, ErrUnsupportedMediaType), errors.Is(err, parser.ErrUnsupportedFileType):
+		return http.StatusUnsupportedMediaType, err.Error()
+	case errors.Is(err, parser.ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge, err.Error()
+	case errors.Is(err, parser.ErrMissingFile), errors.Is(err, parser.ErrInvalidMultipartForm),
+		errors.Is(err, parser.ErrInvalidPathParam), errors.Is(err, ErrInvalidJSON):
+		return http.StatusBadRequest, err.Error()
 	case errors.Is(err, dbutils.ErrRecordNotFound):
-		NotFoundResponse(w, r)
+		return http.StatusNotFound, notFoundMessage
 	case errors.Is(err, dbutils.ErrEditConflict):
-		EditConflictResponse(w, r)
+		return http.StatusConflict, editConflictMessage
 	default:
-		ServerErrorResponse(w, r, err)
+		return http.StatusInternalServerError, serverErrorMessage
 	}
 }
@@ -0,0 +1,368 @@
+package httputils_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/parser"
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+func TestErrorResponseContentNegotiation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{name: "missing accept", accept: "", contentType: "application/json"},
+		{name: "any", accept: "*/*", contentType: "application/json"},
+		{name: "json", accept: "application/json", contentType: "application/json"},
+		{name: "html", accept: "text/html", contentType: "text/html; charset=utf-8"},
+		{
+			name:        "browser",
+			accept:      "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			contentType: "text/html; charset=utf-8",
+		},
+		{name: "html over any", accept: "*/*, text/html", contentType: "text/html; charset=utf-8"},
+		{name: "plain text", accept: "text/plain", contentType: "text/plain; charset=utf-8"},
+		{name: "json preferred by quality", accept: "text/html;q=0.5, application/json", contentType: "application/json"},
+		{name: "unacceptable", accept: "image/png", contentType: "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rr := httptest.NewRecorder()
+			httputils.NotFoundResponse(rr, req)
+
+			if rr.Code != http.StatusNotFound {
+				t.Errorf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+			}
+
+			if got := rr.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("expected Content-Type %q, got %q", tt.contentType, got)
+			}
+		})
+	}
+}
+
+func TestErrorResponseJSON(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rr := httptest.NewRecorder()
+	httputils.RateLimitExceededResponse(rr, req)
+
+	var body map[string]any
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if body["errors"] != "rate limit exceeded" {
+		t.Errorf("expected rate limit error message, got %v", body["errors"])
+	}
+}
+
+func TestStandardErrorResponses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		path           string
+		respond        func(w http.ResponseWriter, r *http.Request)
+		expectedStatus int
+		expectedBody   map[string]any
+	}{
+		{
+			name: "bad request",
+			path: "/api/tenants",
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				httputils.BadRequestResponse(w, r, errors.New("invalid request"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]any{"errors": "invalid request"},
+		},
+		{
+			name:           "not found",
+			path:           "/api/tenants/1",
+			respond:        httputils.NotFoundResponse,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   map[string]any{"errors": "the requested resource could not be found"},
+		},
+		{
+			name:           "unauthorized",
+			path:           "/api/tenants",
+			respond:        httputils.UnauthorizedResponse,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   map[string]any{"errors": "You must be authenticated to access this resource"},
+		},
+		{
+			name: "forbidden",
+			path: "/api/tenants",
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				httputils.ForbiddenResponse(w, r, errors.New("you do not have access to this tenant"))
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   map[string]any{"errors": "you do not have access to this tenant"},
+		},
+		{
+			name: "failed validation",
+			path: "/api/tenants",
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				httputils.FailedValidationResponse(w, r, []validation.Error{
+					{Field: "tenantName", Message: "Tenant Name is required"},
+					{Field: "plan", Message: "Invalid plan"},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]any{"errors": []any{
+				map[string]any{"field": "tenantName", "message": "Tenant Name is required"},
+				map[string]any{"field": "plan", "message": "Invalid plan"},
+			}},
+		},
+		{
+			name: "server error",
+			path: "/api/tenants",
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				httputils.ServerErrorResponse(w, r, errors.New("connection refused"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]any{
+				"errors": "the server encountered a problem and could not process your request",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			tt.respond(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if contentType := rr.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("expected JSON content type, got %q", contentType)
+			}
+
+			var body map[string]any
+			if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if !reflect.DeepEqual(body, tt.expectedBody) {
+				t.Errorf("expected body %v, got %v", tt.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestUnauthorizedResponseRedirectsPages(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+	httputils.UnauthorizedResponse(rr, httptest.NewRequest(http.MethodGet, "/dashboard", nil))
+
+	if rr.Code != http.StatusTemporaryRedirect || rr.Header().Get("Location") != "/login" {
+		t.Errorf("expected redirect to /login, got %d %q", rr.Code, rr.Header().Get("Location"))
+	}
+}
+
+func TestErrorResponseHTML(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Accept", "text/html")
+
+	rr := httptest.NewRecorder()
+	httputils.FailedValidationResponse(rr, req, []validation.Error{
+		{Field: "name", Message: "must be <b>provided</b>"},
+	})
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+
+	body := rr.Body.String()
+	if !strings.Contains(body, "<h1>400 Bad Request</h1>") {
+		t.Errorf("expected HTML heading, got %s", body)
+	}
+
+	if !strings.Contains(body, "<li>name: must be &lt;b&gt;provided&lt;/b&gt;</li>") {
+		t.Errorf("expected escaped validation message, got %s", body)
+	}
+}
+
+func TestErrorResponsePlainText(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/plain")
+
+	rr := httptest.NewRecorder()
+	httputils.ServerErrorResponse(rr, req, http.ErrAbortHandler)
+
+	expected := "500 Internal Server Error\n" +
+		"the server encountered a problem and could not process your request\n"
+	if rr.Body.String() != expected {
+		t.Errorf("expected %q, got %q", expected, rr.Body.String())
+	}
+}
+
+func TestErrorResponseProblemJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		format         httputils.ErrorFormat
+		accept         string
+		respond        func(w http.ResponseWriter, r *http.Request)
+		expectedStatus int
+		expectedDetail string
+		expectedErrors []validation.Error
+	}{
+		{
+			name:           "problem format",
+			format:         httputils.ErrorFormatProblem,
+			respond:        httputils.NotFoundResponse,
+			expectedStatus: http.StatusNotFound,
+			expectedDetail: "the requested resource could not be found",
+		},
+		{
+			name:   "validation errors",
+			format: httputils.ErrorFormatProblem,
+			respond: func(w http.ResponseWriter, r *http.Request) {
+				httputils.FailedValidationResponse(w, r, []validation.Error{{Field: "name", Message: "must be provided"}})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedDetail: "one or more fields failed validation",
+			expectedErrors: []validation.Error{{Field: "name", Message: "must be provided"}},
+		},
+		{
+			name:           "requested by client",
+			format:         httputils.ErrorFormatLegacy,
+			accept:         "application/problem+json",
+			respond:        httputils.EditConflictResponse,
+			expectedStatus: http.StatusConflict,
+			expectedDetail: "unable to update the record due to an edit conflict, please try again",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := httputils.ErrorFormatMiddleware(tt.format)(http.HandlerFunc(tt.respond))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/tenants/1", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if got := rr.Header().Get("Content-Type"); got != "application/problem+json" {
+				t.Errorf("expected Content-Type application/problem+json, got %q", got)
+			}
+
+			var problem httputils.ProblemDetails
+			if err := json.NewDecoder(rr.Body).Decode(&problem); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			expected := httputils.ProblemDetails{
+				Type:     "about:blank",
+				Title:    http.StatusText(tt.expectedStatus),
+				Status:   tt.expectedStatus,
+				Detail:   tt.expectedDetail,
+				Instance: "/api/tenants/1",
+				Errors:   tt.expectedErrors,
+			}
+			if !reflect.DeepEqual(problem, expected) {
+				t.Errorf("expected %+v, got %+v", expected, problem)
+			}
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestErrorResponseLegacyFormat(t *testing.T) {
+	t.Parallel()
+
+	handler := httputils.ErrorFormatMiddleware(httputils.ErrorFormatLegacy)(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			httputils.FailedValidationResponse(w, r, []validation.Error{{Field: "name", Message: "must be provided"}})
+		},
+	))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/tenants", nil))
+
+	if got := rr.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %q", got)
+	}
+
+	expected := "{\n\t\"errors\": [\n\t\t{\n\t\t\t\"field\": \"name\",\n\t\t\t\"message\": \"must be provided\"\n\t\t}\n\t]\n}\n"
+	if rr.Body.String() != expected {
+		t.Errorf("expected %q, got %q", expected, rr.Body.String())
+	}
+}
+
+func TestHandleErrorResponseClientErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err            error
+		expectedStatus int
+	}{
+		{err: parser.ErrFileTooLarge, expectedStatus: http.StatusRequestEntityTooLarge},
+		{err: parser.ErrUnsupportedFileType, expectedStatus: http.StatusUnsupportedMediaType},
+		{err: parser.ErrMissingFile, expectedStatus: http.StatusBadRequest},
+		{err: parser.ErrInvalidMultipartForm, expectedStatus: http.StatusBadRequest},
+		{err: parser.ErrInvalidPathParam, expectedStatus: http.StatusBadRequest},
+		{err: httputils.ErrInvalidJSON, expectedStatus: http.StatusBadRequest},
+		{err: httputils.ErrUnsupportedMediaType, expectedStatus: http.StatusUnsupportedMediaType},
+		{err: dbutils.ErrRecordNotFound, expectedStatus: http.StatusNotFound},
+		{err: dbutils.ErrEditConflict, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			httputils.HandleErrorResponse(rr, httptest.NewRequest(http.MethodPost, "/", nil), tt.err)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			result := httputils.BatchFailure(httptest.NewRequest(http.MethodPost, "/", nil), 0, tt.err)
+			if result.Status != tt.expectedStatus {
+				t.Errorf("expected batch status %d, got %d", tt.expectedStatus, result.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package httputils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	etagHeader            = "ETag"
+	ifNoneMatchHeader     = "If-None-Match"
+	lastModifiedHeader    = "Last-Modified"
+	ifModifiedSinceHeader = "If-Modified-Since"
+	etagHashBytes         = 16
+)
+
+// ETag returns a strong entity tag derived from a hash of body.
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(sum[:etagHashBytes]) + `"`
+}
+
+// VersionETag returns a weak entity tag derived from a record's version column. Because the version
+// is incremented on every update, it changes whenever the representation does without having to
+// render the response first.
+func VersionETag(version int64) string {
+	return `W/"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// IfNoneMatch reports whether the request's If-None-Match header matches etag. Entity tags are compared
+// using the weak comparison function required for If-None-Match by RFC 9110.
+func IfNoneMatch(r *http.Request, etag string) bool {
+	header := r.Header.Get(ifNoneMatchHeader)
+	if header == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckNotModified sets the ETag response header and, if the request is a GET or HEAD whose If-None-Match
+// header matches etag, writes a 304 Not Modified response. It returns true if the 304 was written, in which
+// case the handler should return without writing a body.
+func CheckNotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set(etagHeader, etag)
+
+	if (r.Method != http.MethodGet && r.Method != http.MethodHead) || !IfNoneMatch(r, etag) {
+		return false
+	}
+
+	writeNotModified(w)
+
+	return true
+}
+
+// NotModifiedSince reports whether the request's If-Modified-Since header is at or after lastModified, e.g. a
+// record's updated_at column. HTTP dates have a resolution of one second, so lastModified is truncated to the
+// second before comparing. As required by RFC 9110, If-Modified-Since is ignored when the request has an
+// If-None-Match header, since entity tags are the more precise validator.
+func NotModifiedSince(r *http.Request, lastModified time.Time) bool {
+	header := r.Header.Get(ifModifiedSinceHeader)
+	if header == "" || r.Header.Get(ifNoneMatchHeader) != "" || lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(header)
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// CheckNotModifiedSince sets the Last-Modified response header and, if the request is a GET or HEAD that
+// has not been modified since its If-Modified-Since header, writes a 304 Not Modified response. It returns
+// true if the 304 was written, in which case the handler should return without writing a body. It can be
+// called after CheckNotModified to support clients that only send If-Modified-Since.
+func CheckNotModifiedSince(w http.ResponseWriter, r *http.Request, lastModified time.Time) bool {
+	if lastModified.IsZero() {
+		return false
+	}
+
+	w.Header().Set(lastModifiedHeader, lastModified.UTC().Format(http.TimeFormat))
+
+	if (r.Method != http.MethodGet && r.Method != http.MethodHead) || !NotModifiedSince(r, lastModified) {
+		return false
+	}
+
+	writeNotModified(w)
+
+	return true
+}
+
+// writeNotModified writes a 304 Not Modified response. Representation headers describing the omitted body
+// are removed.
+func writeNotModified(w http.ResponseWriter) {
+	w.Header().Del(ContentTypeHeader)
+	w.Header().Del("Content-Length")
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// ETagMiddleware buffers successful GET responses, sets an ETag header computed from the body if the handler
+// did not set one, and responds with 304 Not Modified when it matches the request's If-None-Match header.
+// HEAD requests are passed through, since their empty bodies would produce a different ETag than the GET;
+// handlers that support HEAD should use CheckNotModified with an ETag that does not depend on the body.
+// Responses are buffered, so it should only be applied to routes with small bodies, e.g.
+// router.With(httputils.ETagMiddleware).Get("/tenants/{id}", handler). A handler that calls Flush streams
+// its response without an ETag.
+func ETagMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		ew := &etagWriter{ResponseWriter: w, status: http.StatusOK} //nolint: exhaustruct
+		next.ServeHTTP(ew, r)
+
+		if ew.streaming {
+			return
+		}
+
+		if ew.status != http.StatusOK {
+			w.WriteHeader(ew.status)
+			_, _ = ew.body.WriteTo(w)
+
+			return
+		}
+
+		etag := w.Header().Get(etagHeader)
+		if etag == "" {
+			etag = ETag(ew.body.Bytes())
+			w.Header().Set(etagHeader, etag)
+		}
+
+		if IfNoneMatch(r, etag) {
+			writeNotModified(w)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = ew.body.WriteTo(w)
+	})
+}
+
+// etagWriter records the status and buffers the body of a response so that its ETag can be computed
+// before anything is sent to the client. Headers are written through to the underlying ResponseWriter.
+type etagWriter struct {
+	http.ResponseWriter
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+	streaming   bool
+}
+
+func (ew *etagWriter) WriteHeader(status int) {
+	if ew.wroteHeader {
+		return
+	}
+
+	ew.status = status
+	ew.wroteHeader = true
+}
+
+func (ew *etagWriter) Write(b []byte) (int, error) {
+	ew.wroteHeader = true
+
+	if ew.streaming {
+		return ew.ResponseWriter.Write(b) //nolint: wrapcheck
+	}
+
+	return ew.body.Write(b) //nolint: wrapcheck
+}
+
+// Flush sends the buffered response and switches to writing through to the client, since a streamed body
+// cannot be hashed before it is sent.
+func (ew *etagWriter) Flush() {
+	if !ew.streaming {
+		ew.streaming = true
+		ew.ResponseWriter.WriteHeader(ew.status)
+		_, _ = ew.body.WriteTo(ew.ResponseWriter)
+	}
+
+	if flusher, ok := ew.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying ResponseWriter for use with http.ResponseController.
+func (ew *etagWriter) Unwrap() http.ResponseWriter {
+	return ew.ResponseWriter
+}
@@ -0,0 +1,238 @@
+package httputils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestIfNoneMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		etag        string
+		expected    bool
+	}{
+		{name: "missing header", ifNoneMatch: "", etag: `"abc"`, expected: false},
+		{name: "exact match", ifNoneMatch: `"abc"`, etag: `"abc"`, expected: true},
+		{name: "mismatch", ifNoneMatch: `"def"`, etag: `"abc"`, expected: false},
+		{name: "list", ifNoneMatch: `"def", "abc"`, etag: `"abc"`, expected: true},
+		{name: "weak comparison", ifNoneMatch: `W/"1"`, etag: `"1"`, expected: true},
+		{name: "weak etag", ifNoneMatch: `"1"`, etag: httputils.VersionETag(1), expected: true},
+		{name: "wildcard", ifNoneMatch: "*", etag: `"abc"`, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			if got := httputils.IfNoneMatch(req, tt.etag); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestETagMiddleware(t *testing.T) {
+	t.Parallel()
+
+	body := `{"id":1}`
+	etag := httputils.ETag([]byte(body))
+
+	tests := []struct {
+		name           string
+		method         string
+		ifNoneMatch    string
+		status         int
+		expectedStatus int
+		expectedBody   string
+		expectedETag   string
+	}{
+		{
+			name: "no If-None-Match", method: http.MethodGet, status: http.StatusOK,
+			expectedStatus: http.StatusOK, expectedBody: body, expectedETag: etag,
+		},
+		{
+			name: "matching If-None-Match", method: http.MethodGet, ifNoneMatch: etag, status: http.StatusOK,
+			expectedStatus: http.StatusNotModified, expectedBody: "", expectedETag: etag,
+		},
+		{
+			name: "stale If-None-Match", method: http.MethodGet, ifNoneMatch: `"stale"`, status: http.StatusOK,
+			expectedStatus: http.StatusOK, expectedBody: body, expectedETag: etag,
+		},
+		{
+			name: "error response", method: http.MethodGet, ifNoneMatch: etag, status: http.StatusNotFound,
+			expectedStatus: http.StatusNotFound, expectedBody: body, expectedETag: "",
+		},
+		{
+			name: "head passed through", method: http.MethodHead, ifNoneMatch: etag, status: http.StatusOK,
+			expectedStatus: http.StatusOK, expectedBody: body, expectedETag: "",
+		},
+		{
+			name: "unsafe method", method: http.MethodPost, ifNoneMatch: etag, status: http.StatusOK,
+			expectedStatus: http.StatusOK, expectedBody: body, expectedETag: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := httputils.ETagMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				httputils.SetJSONContentTypeResponseHeader(w)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(body))
+			}))
+
+			req := httptest.NewRequest(tt.method, "/tenants/1", nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if rr.Body.String() != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, rr.Body.String())
+			}
+
+			if got := rr.Header().Get("ETag"); got != tt.expectedETag {
+				t.Errorf("expected ETag %q, got %q", tt.expectedETag, got)
+			}
+		})
+	}
+}
+
+func TestETagMiddlewareFlush(t *testing.T) {
+	t.Parallel()
+
+	handler := httputils.ETagMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("data: 1\n\n"))
+		http.NewResponseController(w).Flush() //nolint: errcheck
+		_, _ = w.Write([]byte("data: 2\n\n"))
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+	if !rr.Flushed {
+		t.Error("expected the response to be flushed")
+	}
+
+	if rr.Body.String() != "data: 1\n\ndata: 2\n\n" {
+		t.Errorf("expected the streamed body, got %q", rr.Body.String())
+	}
+
+	if etag := rr.Header().Get("ETag"); etag != "" {
+		t.Errorf("expected no ETag for a streamed response, got %q", etag)
+	}
+}
+
+func TestCheckNotModified(t *testing.T) {
+	t.Parallel()
+
+	etag := httputils.VersionETag(3)
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants/1", nil)
+	req.Header.Set("If-None-Match", etag)
+
+	rr := httptest.NewRecorder()
+	if !httputils.CheckNotModified(rr, req, etag) {
+		t.Fatal("expected CheckNotModified to report a match")
+	}
+
+	if rr.Code != http.StatusNotModified {
+		t.Errorf("expected status %d, got %d", http.StatusNotModified, rr.Code)
+	}
+
+	if rr.Body.Len() != 0 {
+		t.Errorf("expected empty body, got %q", rr.Body.String())
+	}
+
+	if got := rr.Header().Get("ETag"); got != etag {
+		t.Errorf("expected ETag %q, got %q", etag, got)
+	}
+}
+
+func TestNotModifiedSince(t *testing.T) {
+	t.Parallel()
+
+	lastModified := time.Date(2024, 5, 1, 12, 0, 0, 500_000_000, time.UTC)
+
+	tests := []struct {
+		name            string
+		ifModifiedSince string
+		ifNoneMatch     string
+		expected        bool
+	}{
+		{"no header", "", "", false},
+		{"same second", "Wed, 01 May 2024 12:00:00 GMT", "", true},
+		{"later", "Wed, 01 May 2024 13:00:00 GMT", "", true},
+		{"earlier", "Wed, 01 May 2024 11:59:59 GMT", "", false},
+		{"invalid date", "yesterday", "", false},
+		{"ignored with If-None-Match", "Wed, 01 May 2024 13:00:00 GMT", `"abc"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/tenants/1", nil)
+			if tt.ifModifiedSince != "" {
+				req.Header.Set("If-Modified-Since", tt.ifModifiedSince)
+			}
+
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			if got := httputils.NotModifiedSince(req, lastModified); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckNotModifiedSince(t *testing.T) {
+	t.Parallel()
+
+	lastModified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants/1", nil)
+	req.Header.Set("If-Modified-Since", "Wed, 01 May 2024 17:30:00 GMT")
+
+	rr := httptest.NewRecorder()
+	if !httputils.CheckNotModifiedSince(rr, req, lastModified) {
+		t.Fatal("expected CheckNotModifiedSince to report the resource is unmodified")
+	}
+
+	if rr.Code != http.StatusNotModified || rr.Body.Len() != 0 {
+		t.Errorf("expected empty 304 response, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	if got := rr.Header().Get("Last-Modified"); got != "Wed, 01 May 2024 17:00:00 GMT" {
+		t.Errorf("expected Last-Modified in GMT, got %q", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/tenants/1", nil)
+	req.Header.Set("If-Modified-Since", "Wed, 01 May 2024 16:00:00 GMT")
+
+	rr = httptest.NewRecorder()
+	if httputils.CheckNotModifiedSince(rr, req, lastModified) {
+		t.Error("expected a modified resource not to be reported as unmodified")
+	}
+}
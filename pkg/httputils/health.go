@@ -0,0 +1,98 @@
+package httputils
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+const (
+	healthStatusOK          = "ok"
+	healthStatusError       = "error"
+	healthStatusUnavailable = "unavailable"
+)
+
+// HealthCheck is a named dependency check run by HealthHandler, e.g. a database ping using dbutils.HealthCheck.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type healthResponse struct {
+	Status string                       `json:"status"`
+	Checks map[string]healthCheckResult `json:"checks,omitempty"`
+}
+
+type healthCheckResult struct {
+	Status string `json:"status"`
+}
+
+// LivenessHandler responds with 200 OK as long as the process is able to serve requests. It does not check
+// dependencies so that an orchestrator does not restart the process when, for example, the database is down.
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		RespondJSON(w, r, http.StatusOK, healthResponse{Status: healthStatusOK, Checks: nil}, nil)
+	}
+}
+
+// HealthHandler is a readiness handler that runs checks concurrently and responds with the status of each.
+// It responds with 200 OK if every check passes and 503 Service Unavailable if any fail. A check that panics is
+// reported as failed. Check errors are logged rather than returned to avoid exposing internal details.
+func HealthHandler(checks ...HealthCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		results := make(map[string]healthCheckResult, len(checks))
+
+		var mu sync.Mutex
+
+		var wg sync.WaitGroup
+
+		for _, check := range checks {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				result := healthCheckResult{Status: healthStatusOK}
+				if err := runHealthCheck(r.Context(), check); err != nil {
+					slog.ErrorContext(r.Context(), "health check failed", "check", check.Name, "error", err)
+
+					result.Status = healthStatusError
+				}
+
+				mu.Lock()
+				results[check.Name] = result
+				mu.Unlock()
+			}()
+		}
+
+		wg.Wait()
+
+		response := healthResponse{Status: healthStatusOK, Checks: results}
+		status := http.StatusOK
+
+		for _, result := range results {
+			if result.Status != healthStatusOK {
+				response.Status = healthStatusUnavailable
+				status = http.StatusServiceUnavailable
+
+				break
+			}
+		}
+
+		RespondJSON(w, r, status, response, nil)
+	}
+}
+
+// runHealthCheck runs check, converting a panic into an error. Checks run in their own goroutines, where a panic
+// would otherwise crash the process rather than reach RecoveryMiddleware.
+func runHealthCheck(ctx context.Context, check HealthCheck) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("health check panicked: %v", p) //nolint: err113
+		}
+	}()
+
+	return check.Check(ctx)
+}
@@ -0,0 +1,114 @@
+package httputils_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+var errCacheUnavailable = errors.New("cache unavailable")
+
+type healthBody struct {
+	Status string                       `json:"status"`
+	Checks map[string]map[string]string `json:"checks"`
+}
+
+func TestLivenessHandler(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+	httputils.LivenessHandler()(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	t.Parallel()
+
+	failingCheck := httputils.HealthCheck{
+		Name:  "cache",
+		Check: func(context.Context) error { return errCacheUnavailable },
+	}
+
+	tests := []struct {
+		name           string
+		checks         []httputils.HealthCheck
+		expectedStatus int
+		expectedBody   healthBody
+	}{
+		{
+			name:           "healthy",
+			checks:         nil,
+			expectedStatus: http.StatusOK,
+			expectedBody: healthBody{
+				Status: "ok",
+				Checks: map[string]map[string]string{"database": {"status": "ok"}},
+			},
+		},
+		{
+			name:           "degraded",
+			checks:         []httputils.HealthCheck{failingCheck},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: healthBody{
+				Status: "unavailable",
+				Checks: map[string]map[string]string{"database": {"status": "ok"}, "cache": {"status": "error"}},
+			},
+		},
+		{
+			name: "panicking check",
+			checks: []httputils.HealthCheck{{
+				Name:  "cache",
+				Check: func(context.Context) error { panic("cache client is nil") },
+			}},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: healthBody{
+				Status: "unavailable",
+				Checks: map[string]map[string]string{"database": {"status": "ok"}, "cache": {"status": "error"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			db := testutils.SetupTestDB(t)
+			defer func() {
+				if closeErr := db.Close(); closeErr != nil {
+					t.Fatalf("Failed to close database connection: %v", closeErr)
+				}
+			}()
+
+			dbCheck := httputils.HealthCheck{
+				Name:  "database",
+				Check: func(ctx context.Context) error { return dbutils.HealthCheck(ctx, db) },
+			}
+
+			rr := httptest.NewRecorder()
+			httputils.HealthHandler(append([]httputils.HealthCheck{dbCheck}, tt.checks...)...)(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			var body healthBody
+			if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+
+			if !reflect.DeepEqual(body, tt.expectedBody) {
+				t.Errorf("expected %+v, got %+v", tt.expectedBody, body)
+			}
+		})
+	}
+}
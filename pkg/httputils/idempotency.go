@@ -0,0 +1,367 @@
+package httputils
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header clients use to make a request safe to retry.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to true on responses replayed from the IdempotencyStore.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	defaultIdempotencyTTL      = 24 * time.Hour
+	maxIdempotencyKeyLength    = 255
+	maxIdempotentBodyBytes     = 1 << 20
+	idempotencyCleanupInterval = time.Minute
+)
+
+var (
+	// ErrIdempotencyKeyInUse is returned by an IdempotencyStore when another request with the same key is
+	// still being processed.
+	ErrIdempotencyKeyInUse = errors.New("a request with this Idempotency-Key is already being processed")
+	// ErrIdempotencyKeyReused is returned by an IdempotencyStore when a key is reused with a different
+	// request body.
+	ErrIdempotencyKeyReused = errors.New("this Idempotency-Key was already used with a different request")
+)
+
+// IdempotentResponse is a response stored by IdempotencyMiddleware so that it can be replayed.
+type IdempotentResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore records the responses of requests made with an Idempotency-Key. Implementations must be
+// safe for concurrent use. MemoryIdempotencyStore keeps responses in process and is suitable for a single
+// instance.
+type IdempotencyStore interface {
+	// Reserve claims key for a request whose body hashes to fingerprint until the response is saved, the
+	// reservation is released, or ttl elapses. It returns the stored response if the key has already
+	// completed, ErrIdempotencyKeyInUse if another request holds the reservation, and ErrIdempotencyKeyReused
+	// if the key was used with a different fingerprint.
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (*IdempotentResponse, error)
+	// Save stores the response for a reserved key so that it is replayed until ttl elapses.
+	Save(ctx context.Context, key string, response IdempotentResponse, ttl time.Duration) error
+	// Release removes the reservation for key so that the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// IdempotencyConfig configures IdempotencyMiddleware.
+type IdempotencyConfig struct {
+	// Store persists responses.
+	Store IdempotencyStore
+	// TTL is how long a response is replayed for. Defaults to 24 hours.
+	TTL time.Duration
+	// Scope returns a value identifying the client making the request, e.g. the user's ID, so that clients
+	// that choose the same key cannot replay each other's responses. Defaults to no scope.
+	Scope func(r *http.Request) string
+}
+
+// IdempotencyMiddleware makes requests with an Idempotency-Key header safe to retry. The first response for a
+// key, method and path is stored and replayed with an Idempotent-Replayed header to retries within the TTL,
+// so a client that times out waiting for POST /tenants can retry without creating a duplicate tenant.
+// While the first request is in progress, retries receive a 409 Conflict. Reusing a key with a different
+// request body results in a 422 Unprocessable Entity. Server errors are not stored so that they can be
+// retried. Requests without the header are passed through. Requests with the header are read into memory to
+// be fingerprinted, so their bodies are limited to 1MB and larger requests receive a 413 Content Too Large.
+func IdempotencyMiddleware(cfg IdempotencyConfig) func(next http.Handler) http.Handler {
+	if cfg.TTL == 0 {
+		cfg.TTL = defaultIdempotencyTTL
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+			if idempotencyKey == "" {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if len(idempotencyKey) > maxIdempotencyKeyLength {
+				BadRequestResponse(w, r, fmt.Errorf("%s must be at most %d characters", IdempotencyKeyHeader,
+					maxIdempotencyKeyLength))
+
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodyBytes))
+			if err != nil {
+				var maxBytesError *http.MaxBytesError
+				if errors.As(err, &maxBytesError) {
+					PayloadTooLargeResponse(w, r, fmt.Errorf("request body must not be larger than %d bytes",
+						maxBytesError.Limit))
+
+					return
+				}
+
+				BadRequestResponse(w, r, fmt.Errorf("failed to read request body: %w", err))
+
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			key := r.Method + " " + r.URL.Path + " " + idempotencyKey
+			if cfg.Scope != nil {
+				key = cfg.Scope(r) + " " + key
+			}
+
+			fingerprint := sha256.Sum256(body)
+
+			stored, err := cfg.Store.Reserve(r.Context(), key, hex.EncodeToString(fingerprint[:]), cfg.TTL)
+
+			switch {
+			case errors.Is(err, ErrIdempotencyKeyInUse):
+				errorResponse(w, r, http.StatusConflict, err.Error())
+
+				return
+			case errors.Is(err, ErrIdempotencyKeyReused):
+				UnprocessableEntityResponse(w, r, err)
+
+				return
+			case err != nil:
+				ServerErrorResponse(w, r, fmt.Errorf("failed to reserve idempotency key: %w", err))
+
+				return
+			case stored != nil:
+				replayIdempotentResponse(w, stored)
+
+				return
+			}
+
+			// Headers set by outer middleware, such as the request ID, describe this request rather than the
+			// stored response, so only headers set by the handler are replayed.
+			outerHeader := w.Header().Clone()
+			iw := &idempotencyWriter{ResponseWriter: w, status: http.StatusOK} //nolint: exhaustruct
+
+			// The reservation must be released if the handler panics, otherwise retries would receive a 409
+			// until the TTL elapses.
+			defer func() {
+				if iw.saved {
+					return
+				}
+
+				if err := cfg.Store.Release(context.WithoutCancel(r.Context()), key); err != nil {
+					logError(r, fmt.Errorf("failed to release idempotency key: %w", err))
+				}
+			}()
+
+			next.ServeHTTP(iw, r)
+
+			if iw.status >= http.StatusInternalServerError {
+				return
+			}
+
+			response := IdempotentResponse{
+				Status: iw.status,
+				Header: handlerHeader(outerHeader, iw.header),
+				Body:   iw.body.Bytes(),
+			}
+			if err := cfg.Store.Save(context.WithoutCancel(r.Context()), key, response, cfg.TTL); err != nil {
+				logError(r, fmt.Errorf("failed to save idempotent response: %w", err))
+
+				return
+			}
+
+			iw.saved = true
+		})
+	}
+}
+
+func replayIdempotentResponse(w http.ResponseWriter, response *IdempotentResponse) {
+	for name, values := range response.Header {
+		w.Header()[name] = values
+	}
+
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(response.Status)
+	_, _ = w.Write(response.Body)
+}
+
+// handlerHeader returns the headers in header that were added or changed since outerHeader.
+func handlerHeader(outerHeader, header http.Header) http.Header {
+	result := make(http.Header)
+
+	for name, values := range header {
+		if !slices.Equal(outerHeader[name], values) {
+			result[name] = values
+		}
+	}
+
+	return result
+}
+
+// idempotencyWriter writes the response through to the client while keeping a copy of its status, headers
+// and body to be stored.
+type idempotencyWriter struct {
+	http.ResponseWriter
+	body        bytes.Buffer
+	header      http.Header
+	status      int
+	wroteHeader bool
+	saved       bool
+}
+
+func (iw *idempotencyWriter) WriteHeader(status int) {
+	if iw.wroteHeader {
+		return
+	}
+
+	iw.status = status
+	iw.header = iw.Header().Clone()
+	iw.wroteHeader = true
+	iw.ResponseWriter.WriteHeader(status)
+}
+
+func (iw *idempotencyWriter) Write(b []byte) (int, error) {
+	if !iw.wroteHeader {
+		iw.WriteHeader(http.StatusOK)
+	}
+
+	iw.body.Write(b)
+
+	return iw.ResponseWriter.Write(b) //nolint: wrapcheck
+}
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore. Expired keys are evicted periodically until the
+// store is closed.
+type MemoryIdempotencyStore struct {
+	clock     Clock
+	mu        sync.Mutex
+	entries   map[string]*idempotencyEntry
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type idempotencyEntry struct {
+	fingerprint string
+	response    *IdempotentResponse
+	expiresAt   time.Time
+}
+
+// NewMemoryIdempotencyStore creates a MemoryIdempotencyStore.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return NewMemoryIdempotencyStoreWithClock(RealClock{})
+}
+
+// NewMemoryIdempotencyStoreWithClock creates a MemoryIdempotencyStore that reads the current time from clock
+// when expiring keys. Tests can call EvictExpired directly after advancing a fake clock.
+func NewMemoryIdempotencyStoreWithClock(clock Clock) *MemoryIdempotencyStore {
+	store := &MemoryIdempotencyStore{
+		clock:     clock,
+		mu:        sync.Mutex{},
+		entries:   make(map[string]*idempotencyEntry),
+		done:      make(chan struct{}),
+		closeOnce: sync.Once{},
+	}
+
+	go func() {
+		ticker := time.NewTicker(idempotencyCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				store.EvictExpired()
+			case <-store.done:
+				return
+			}
+		}
+	}()
+
+	return store
+}
+
+// Close stops evicting expired keys. The store can still be used, but keys are only evicted when
+// EvictExpired is called.
+func (s *MemoryIdempotencyStore) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// Reserve claims key for a request whose body hashes to fingerprint.
+func (s *MemoryIdempotencyStore) Reserve(
+	_ context.Context,
+	key, fingerprint string,
+	ttl time.Duration,
+) (*IdempotentResponse, error) {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if ok && now.Before(entry.expiresAt) {
+		switch {
+		case entry.fingerprint != fingerprint:
+			return nil, ErrIdempotencyKeyReused
+		case entry.response == nil:
+			return nil, ErrIdempotencyKeyInUse
+		default:
+			return entry.response, nil
+		}
+	}
+
+	s.entries[key] = &idempotencyEntry{fingerprint: fingerprint, response: nil, expiresAt: now.Add(ttl)}
+
+	return nil, nil //nolint: nilnil
+}
+
+// Save stores the response for a reserved key.
+func (s *MemoryIdempotencyStore) Save(
+	_ context.Context,
+	key string,
+	response IdempotentResponse,
+	ttl time.Duration,
+) error {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fingerprint := ""
+	if entry, ok := s.entries[key]; ok {
+		fingerprint = entry.fingerprint
+	}
+
+	s.entries[key] = &idempotencyEntry{fingerprint: fingerprint, response: &response, expiresAt: now.Add(ttl)}
+
+	return nil
+}
+
+// Release removes the reservation for key.
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+
+	return nil
+}
+
+// EvictExpired removes keys whose TTL has elapsed.
+func (s *MemoryIdempotencyStore) EvictExpired() {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, entry := range s.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package httputils_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func newIdempotentHandler(t *testing.T, status int) (http.Handler, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Location", "/tenants/"+strings.Repeat("1", int(n)))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"call":` + strings.Repeat("1", int(n)) + `}`))
+	})
+
+	store := httputils.NewMemoryIdempotencyStore()
+	t.Cleanup(store.Close)
+
+	//nolint: exhaustruct
+	handler := httputils.IdempotencyMiddleware(httputils.IdempotencyConfig{Store: store})(next)
+
+	return handler, &calls
+}
+
+func doIdempotentRequest(handler http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/tenants", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(httputils.IdempotencyKeyHeader, key)
+	}
+
+	rr := httptest.NewRecorder()
+	rr.Header().Set("X-Request-ID", key+body)
+	handler.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func TestIdempotencyMiddlewareReplaysResponse(t *testing.T) {
+	t.Parallel()
+
+	handler, calls := newIdempotentHandler(t, http.StatusCreated)
+
+	first := doIdempotentRequest(handler, "key-1", `{"name":"acme"}`)
+	second := doIdempotentRequest(handler, "key-1", `{"name":"acme"}`)
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected handler to be called once, got %d", calls.Load())
+	}
+
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+		t.Errorf("expected replay of %d %s, got %d %s", first.Code, first.Body, second.Code, second.Body)
+	}
+
+	if second.Header().Get("Location") != "/tenants/1" {
+		t.Errorf("expected Location to be replayed, got %q", second.Header().Get("Location"))
+	}
+
+	if second.Header().Get(httputils.IdempotentReplayedHeader) != "true" {
+		t.Error("expected replayed response to be marked")
+	}
+
+	if first.Header().Get(httputils.IdempotentReplayedHeader) != "" {
+		t.Error("expected original response not to be marked as replayed")
+	}
+
+	// Headers set outside the handler belong to the retry, not the stored response.
+	if second.Header().Get("X-Request-ID") != "key-1"+`{"name":"acme"}` {
+		t.Errorf("expected outer header to be kept, got %q", second.Header().Get("X-Request-ID"))
+	}
+
+	doIdempotentRequest(handler, "key-2", `{"name":"acme"}`)
+	doIdempotentRequest(handler, "", `{"name":"acme"}`)
+
+	if calls.Load() != 3 {
+		t.Errorf("expected a new key and a request without a key to reach the handler, got %d calls", calls.Load())
+	}
+}
+
+func TestIdempotencyMiddlewareRejectsReusedKey(t *testing.T) {
+	t.Parallel()
+
+	handler, calls := newIdempotentHandler(t, http.StatusCreated)
+
+	doIdempotentRequest(handler, "key-1", `{"name":"acme"}`)
+	rr := doIdempotentRequest(handler, "key-1", `{"name":"globex"}`)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got %d", rr.Code)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("expected handler to be called once, got %d", calls.Load())
+	}
+}
+
+func TestIdempotencyMiddlewareDoesNotStoreServerErrors(t *testing.T) {
+	t.Parallel()
+
+	handler, calls := newIdempotentHandler(t, http.StatusInternalServerError)
+
+	doIdempotentRequest(handler, "key-1", `{}`)
+	rr := doIdempotentRequest(handler, "key-1", `{}`)
+
+	if calls.Load() != 2 || rr.Header().Get(httputils.IdempotentReplayedHeader) != "" {
+		t.Errorf("expected server error to be retried, got %d calls", calls.Load())
+	}
+}
+
+func TestIdempotencyMiddlewareConcurrentRequests(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	//nolint: exhaustruct
+	handler := httputils.IdempotencyMiddleware(httputils.IdempotencyConfig{
+		Store: httputils.NewMemoryIdempotencyStore(),
+	})(next)
+
+	done := make(chan *httptest.ResponseRecorder)
+
+	go func() {
+		done <- doIdempotentRequest(handler, "key-1", `{}`)
+	}()
+
+	<-started
+
+	rr := doIdempotentRequest(handler, "key-1", `{}`)
+	if rr.Code != http.StatusConflict {
+		t.Errorf("expected status 409 while the first request is in progress, got %d", rr.Code)
+	}
+
+	close(release)
+
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("expected first request to succeed, got %d", first.Code)
+	}
+
+	rr = doIdempotentRequest(handler, "key-1", `{}`)
+	if rr.Code != http.StatusCreated || rr.Header().Get(httputils.IdempotentReplayedHeader) != "true" {
+		t.Errorf("expected replay once the first request completed, got %d", rr.Code)
+	}
+}
+
+func TestIdempotencyMiddlewareRejectsLargeBody(t *testing.T) {
+	t.Parallel()
+
+	handler, calls := newIdempotentHandler(t, http.StatusCreated)
+
+	rr := doIdempotentRequest(handler, "key-1", strings.Repeat("a", 1<<20+1))
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413, got %d", rr.Code)
+	}
+
+	if calls.Load() != 0 {
+		t.Errorf("expected handler not to be called, got %d calls", calls.Load())
+	}
+
+	rr = doIdempotentRequest(handler, "", strings.Repeat("a", 1<<20+1))
+	if rr.Code != http.StatusCreated {
+		t.Errorf("expected requests without a key not to be limited, got %d", rr.Code)
+	}
+}
+
+func TestIdempotencyMiddlewareReleasesKeyOnPanic(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			panic("boom")
+		}
+
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	//nolint: exhaustruct
+	handler := httputils.IdempotencyMiddleware(httputils.IdempotencyConfig{
+		Store: httputils.NewMemoryIdempotencyStore(),
+	})(next)
+
+	func() {
+		defer func() { _ = recover() }()
+
+		doIdempotentRequest(handler, "key-1", `{}`)
+	}()
+
+	rr := doIdempotentRequest(handler, "key-1", `{}`)
+	if rr.Code != http.StatusCreated || calls.Load() != 2 {
+		t.Errorf("expected retry after panic to reach the handler, got %d with %d calls", rr.Code, calls.Load())
+	}
+}
+
+func TestMemoryIdempotencyStoreExpiry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	clock := testutils.NewFakeClock(time.Now())
+	store := httputils.NewMemoryIdempotencyStoreWithClock(clock)
+	defer store.Close()
+
+	if _, err := store.Reserve(ctx, "key", "fingerprint", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	//nolint: exhaustruct
+	if err := store.Save(ctx, "key", httputils.IdempotentResponse{Status: http.StatusCreated}, time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clock.Advance(59 * time.Minute)
+
+	stored, err := store.Reserve(ctx, "key", "fingerprint", time.Hour)
+	if err != nil || stored == nil || stored.Status != http.StatusCreated {
+		t.Fatalf("expected stored response before expiry, got %+v, %v", stored, err)
+	}
+
+	clock.Advance(time.Minute)
+	store.EvictExpired()
+
+	stored, err = store.Reserve(ctx, "key", "other-fingerprint", time.Hour)
+	if err != nil || stored != nil {
+		t.Errorf("expected key to be reusable after expiry, got %+v, %v", stored, err)
+	}
+}
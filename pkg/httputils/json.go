@@ -1,10 +1,15 @@
 package httputils
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"strings"
 )
@@ -12,32 +17,154 @@ import (
 // ErrInvalidJSON is returned when the body is not valid JSON.
 var ErrInvalidJSON = errors.New("invalid JSON")
 
+// ErrUnsupportedMediaType is returned when the request body is not encoded as UTF-8 or has an unsupported
+// Content-Encoding.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// supportedContentEncodings lists the request body encodings DecodeJSON can decompress.
+const supportedContentEncodings = "gzip, deflate"
+
 // ReadJSON decodes request Body into corresponding Go type. It triages for any potential errors
 // and returns corresponding appropriate errors.
 func ReadJSON[T any](w http.ResponseWriter, r *http.Request) (T, error) {
 	var dst T
+
+	err := DecodeJSON(w, r, &dst)
+
+	return dst, err
+}
+
+// DecodeJSON decodes the request body into dst, which must be a non-nil pointer. Bodies that are
+// empty, malformed, larger than 1MB, contain unknown fields or fields of the wrong type, or contain
+// more than one JSON value are rejected with an error wrapping ErrInvalidJSON that describes the
+// problem and is safe to return to the client.
+//
+// Bodies sent with a Content-Encoding of gzip or deflate are decompressed before decoding, and the 1MB
+// limit applies both before and after decompression. Bodies with any other encoding are rejected with an
+// error wrapping ErrUnsupportedMediaType, and the supported encodings are listed in the Accept-Encoding
+// response header.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	// JSON must be encoded as UTF-8 (RFC 8259), so reject bodies declared with any other charset
+	// rather than decoding them into garbled strings.
+	if err := ensureUTF8Charset(r.Header.Get(ContentTypeHeader)); err != nil {
+		return err
+	}
+
 	// Use http.MaxBytesReader() to limit the size of the request body to 1MB to prevent
 	// any potential nefarious DoS attacks.
 	maxBytes := 1_048_576
 	r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
 
+	body, closeBody, err := decompressBody(r.Body, r.Header.Get("Content-Encoding"))
+	defer closeBody()
+
+	if err != nil {
+		if errors.Is(err, ErrUnsupportedMediaType) {
+			w.Header().Set("Accept-Encoding", supportedContentEncodings)
+		}
+
+		return err
+	}
+
+	// Limit the decompressed size as well, so that a small compressed body can't expand without bound.
+	if body != r.Body {
+		body = http.MaxBytesReader(w, io.NopCloser(body), int64(maxBytes))
+	}
+
+	// Accept the keys of the request's key case by renaming them to the json struct tag names before decoding.
+	if keyCase := contextGetKeyCase(r.Context()); keyCase != KeyCaseCamel {
+		payload, err := io.ReadAll(body)
+		if err == nil {
+			payload, err = convertRequestKeyCase(payload, dst, keyCase)
+		}
+
+		if err != nil {
+			return handleDecodeError(err, maxBytes)
+		}
+
+		body = bytes.NewReader(payload)
+	}
+
 	// Initialize the json.Decoder, and call the DisallowUnknownFields() method on it
 	// before decoding. So, if the JSON from the client includes any field which
 	// cannot be mapped to the target destination, the decoder will return an error
 	// instead of just ignoring the field.
-	dec := json.NewDecoder(r.Body)
+	dec := json.NewDecoder(body)
 	dec.DisallowUnknownFields()
 
 	// Decode the request body into the destination.
-	if err := dec.Decode(&dst); err != nil {
-		return dst, handleDecodeError(err, maxBytes)
+	if err := dec.Decode(dst); err != nil {
+		return handleDecodeError(err, maxBytes)
+	}
+
+	return ensureSingleJSONValue(dec)
+}
+
+// ensureUTF8Charset returns ErrUnsupportedMediaType if the Content-Type header declares a charset other than UTF-8.
+func ensureUTF8Charset(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w: malformed Content-Type header", ErrUnsupportedMediaType)
+	}
+
+	charset, ok := params["charset"]
+	if !ok || strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "utf8") {
+		return nil
+	}
+
+	return fmt.Errorf("%w: charset %s is not supported, use utf-8", ErrUnsupportedMediaType, charset)
+}
+
+// decompressBody returns a reader that decodes body according to the Content-Encoding header, which lists the
+// encodings in the order they were applied, and a function that closes the decoders. The function must be
+// called even if an error is returned, since decoders for the outer encodings may already have been opened.
+func decompressBody(body io.Reader, contentEncoding string) (io.Reader, func(), error) {
+	encodings := strings.Split(contentEncoding, ",")
+
+	var decoders []io.Closer
+
+	closeDecoders := func() {
+		for i := len(decoders) - 1; i >= 0; i-- {
+			_ = decoders[i].Close()
+		}
 	}
 
-	if err := ensureSingleJSONValue(dec); err != nil {
-		return dst, err
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var (
+			decoder io.ReadCloser
+			err     error
+		)
+
+		switch encoding := strings.ToLower(strings.TrimSpace(encodings[i])); encoding {
+		case "", "identity":
+			continue
+		case "gzip", "x-gzip":
+			decoder, err = gzip.NewReader(body)
+		case "deflate":
+			decoder, err = zlib.NewReader(body)
+		default:
+			return nil, closeDecoders, fmt.Errorf(
+				"%w: content encoding %s is not supported, use %s",
+				ErrUnsupportedMediaType, encoding, supportedContentEncodings,
+			)
+		}
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil, closeDecoders, fmt.Errorf("%w: body must not be empty", ErrInvalidJSON)
+		case err != nil:
+			return nil, closeDecoders, fmt.Errorf("%w: body is not validly compressed", ErrInvalidJSON)
+		}
+
+		decoders = append(decoders, decoder)
+		body = decoder
 	}
 
-	return dst, nil
+	return body, closeDecoders, nil
 }
 
 // handleDecodeError handles errors returned by json.Decoder.Decode and returns custom errors.
@@ -48,6 +175,8 @@ func handleDecodeError(err error, maxBytes int) error {
 
 	var invalidUnmarshalError *json.InvalidUnmarshalError
 
+	var corruptInputError flate.CorruptInputError
+
 	switch {
 	case errors.As(err, &syntaxError):
 		return fmt.Errorf("%w: body contains badly-formed JSON at (character %d)", ErrInvalidJSON, syntaxError.Offset)
@@ -66,6 +195,10 @@ func handleDecodeError(err error, maxBytes int) error {
 
 		return fmt.Errorf("%w: body contains unknown key %s", ErrInvalidJSON, fieldName)
 
+	case errors.Is(err, gzip.ErrChecksum), errors.Is(err, gzip.ErrHeader), errors.Is(err, zlib.ErrChecksum),
+		errors.As(err, &corruptInputError):
+		return fmt.Errorf("%w: body is not validly compressed", ErrInvalidJSON)
+
 	case err.Error() == "http: request body too large":
 		return fmt.Errorf("%w: body must not be larger than %d bytes", ErrInvalidJSON, maxBytes)
 
@@ -97,16 +230,78 @@ func ensureSingleJSONValue(dec *json.Decoder) error {
 
 // WriteJSON marshals data structure to encoded JSON response and writes it to the response body.
 func WriteJSON(w http.ResponseWriter, status int, data interface{}, headers http.Header) error {
+	return writeJSON(w, status, mediaTypeJSON, data, headers)
+}
+
+// RespondJSON writes data as a JSON response with the given status and headers. If data cannot be
+// marshaled, nothing has been written yet so a 500 Internal Server Error is sent with ServerErrorResponse
+// instead. Errors writing the response body are logged. Handlers should prefer RespondJSON over WriteJSON
+// so that every response takes the same error path. Object keys are cased as selected by KeyCaseMiddleware, and
+// successful responses are wrapped as {"data": ...} if DataEnvelopeMiddleware enabled it.
+func RespondJSON(w http.ResponseWriter, r *http.Request, status int, data interface{}, headers http.Header) {
+	data = wrapDataEnvelope(r.Context(), status, data)
+
+	jsonPayload, err := marshalJSONWithKeyCase(data, contextGetKeyCase(r.Context()))
+	if err != nil {
+		ServerErrorResponse(w, r, err)
+
+		return
+	}
+
+	if err := writeJSONPayload(w, status, mediaTypeJSON, jsonPayload, headers); err != nil {
+		logError(r, err)
+	}
+}
+
+// writeJSON is WriteJSON with the response Content-Type set to contentType.
+func writeJSON(w http.ResponseWriter, status int, contentType string, data interface{}, headers http.Header) error {
+	jsonPayload, err := marshalJSON(data)
+	if err != nil {
+		return err
+	}
+
+	return writeJSONPayload(w, status, contentType, jsonPayload, headers)
+}
+
+func marshalJSON(data interface{}) ([]byte, error) {
 	// Use the json.MarshalIndent() function so that whitespace is added to the encoded JSON. Use
 	// no line prefix and tab indents for each element.
 	jsonPayload, err := json.MarshalIndent(data, "", "\t")
 	if err != nil {
-		return fmt.Errorf("failed to marshal indent in write json: %w", err)
+		return nil, fmt.Errorf("failed to marshal indent in write json: %w", err)
 	}
 
 	// Append a newline to make it easier to view in terminal applications.
-	jsonPayload = append(jsonPayload, '\n')
+	return append(jsonPayload, '\n'), nil
+}
+
+// marshalJSONWithKeyCase is marshalJSON with object keys converted to keyCase.
+func marshalJSONWithKeyCase(data interface{}, keyCase KeyCase) ([]byte, error) {
+	if keyCase == KeyCaseCamel {
+		return marshalJSON(data)
+	}
+
+	jsonPayload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal in write json: %w", err)
+	}
+
+	jsonPayload, err = convertKeyCase(jsonPayload, data, keyCase)
+	if err != nil {
+		return nil, err
+	}
+
+	indented := bytes.Buffer{}
+	if err := json.Indent(&indented, jsonPayload, "", "\t"); err != nil {
+		return nil, fmt.Errorf("failed to indent in write json: %w", err)
+	}
+
+	return append(indented.Bytes(), '\n'), nil
+}
 
+func writeJSONPayload(
+	w http.ResponseWriter, status int, contentType string, jsonPayload []byte, headers http.Header,
+) error {
 	// At this point, we know that we won't encounter any more errors before writing the response,
 	// so it's safe to add any headers that we want to include. We loop through the header map
 	// and add each header to the http.ResponseWriter header map. Note that it's OK if the
@@ -116,7 +311,7 @@ func WriteJSON(w http.ResponseWriter, status int, data interface{}, headers http
 		w.Header()[key] = value
 	}
 
-	SetJSONContentTypeResponseHeader(w)
+	w.Header().Set(ContentTypeHeader, contentType)
 	w.WriteHeader(status)
 
 	if _, err := w.Write(jsonPayload); err != nil {
@@ -2,6 +2,10 @@ package httputils_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -52,6 +56,151 @@ func TestReadJSON(t *testing.T) {
 	}
 }
 
+func TestReadJSONCharset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		contentType    string
+		expectedStatus int
+	}{
+		{"no content type", "", http.StatusOK},
+		{"json without charset", "application/json", http.StatusOK},
+		{"utf-8 charset", "application/json; charset=utf-8", http.StatusOK},
+		{"uppercase utf-8 charset", "application/json; charset=UTF-8", http.StatusOK},
+		{"iso-8859-1 charset", "application/json; charset=iso-8859-1", http.StatusUnsupportedMediaType},
+		{"utf-16 charset", "application/json; charset=utf-16", http.StatusUnsupportedMediaType},
+		{"malformed content type", "application/json; charset", http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"name":"Jos\u00e9"}`))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			rr := httptest.NewRecorder()
+
+			type Dest struct {
+				Name string `json:"name"`
+			}
+
+			_, err := httputils.ReadJSON[Dest](rr, r)
+			if err != nil {
+				httputils.HandleErrorResponse(rr, r, err)
+			} else {
+				rr.WriteHeader(http.StatusOK)
+			}
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func compressBody(t *testing.T, encoding string, body string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	var writer io.WriteCloser
+
+	switch encoding {
+	case "gzip":
+		writer = gzip.NewWriter(&buf)
+	case "deflate":
+		writer = zlib.NewWriter(&buf)
+	default:
+		t.Fatalf("unsupported encoding %s", encoding)
+	}
+
+	if _, err := writer.Write([]byte(body)); err != nil {
+		t.Fatalf("failed to compress body: %v", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to compress body: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestReadJSONContentEncoding(t *testing.T) {
+	t.Parallel()
+
+	tooLarge := `{"name":"` + strings.Repeat("a", 1_048_577) + `"}`
+
+	tests := []struct {
+		name            string
+		contentEncoding string
+		body            []byte
+		expectedStatus  int
+		expectedError   string
+	}{
+		{"gzip", "gzip", compressBody(t, "gzip", `{"name":"John"}`), http.StatusOK, ""},
+		{"x-gzip", "x-gzip", compressBody(t, "gzip", `{"name":"John"}`), http.StatusOK, ""},
+		{"deflate", "deflate", compressBody(t, "deflate", `{"name":"John"}`), http.StatusOK, ""},
+		{"identity", "identity", []byte(`{"name":"John"}`), http.StatusOK, ""},
+		{
+			"unsupported encoding", "br", []byte(`{"name":"John"}`), http.StatusUnsupportedMediaType,
+			"content encoding br is not supported, use gzip, deflate",
+		},
+		{
+			"corrupt gzip", "gzip", []byte(`{"name":"John"}`), http.StatusBadRequest,
+			"body is not validly compressed",
+		},
+		{"empty gzip", "gzip", []byte{}, http.StatusBadRequest, "body must not be empty"},
+		{
+			"decompressed body too large", "gzip", compressBody(t, "gzip", tooLarge), http.StatusBadRequest,
+			"body must not be larger than 1048576 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tt.body))
+			r.Header.Set("Content-Encoding", tt.contentEncoding)
+
+			rr := httptest.NewRecorder()
+
+			type Dest struct {
+				Name string `json:"name"`
+			}
+
+			dest, err := httputils.ReadJSON[Dest](rr, r)
+			if err != nil {
+				httputils.HandleErrorResponse(rr, r, err)
+			} else {
+				rr.WriteHeader(http.StatusOK)
+			}
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedError == "" && dest.Name != "John" {
+				t.Errorf("expected name John, got %q", dest.Name)
+			}
+
+			if tt.expectedError != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedError)) {
+				t.Errorf("expected error %q, got %v", tt.expectedError, err)
+			}
+
+			if tt.expectedStatus == http.StatusUnsupportedMediaType {
+				if acceptEncoding := rr.Header().Get("Accept-Encoding"); acceptEncoding != "gzip, deflate" {
+					t.Errorf("expected Accept-Encoding gzip, deflate, got %q", acceptEncoding)
+				}
+			}
+		})
+	}
+}
+
 func TestWriteJSON(t *testing.T) {
 	t.Parallel()
 
@@ -161,3 +310,126 @@ func TestWriteJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestRespondJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		status     int
+		data       interface{}
+		headers    http.Header
+		wantStatus int
+		wantBody   string
+		wantHeader map[string]string
+	}{
+		{
+			name:       "valid JSON response with headers",
+			status:     http.StatusCreated,
+			data:       map[string]int{"id": 1},
+			headers:    http.Header{"Location": []string{"/tenants/1"}},
+			wantStatus: http.StatusCreated,
+			wantBody:   "{\n\t\"id\": 1\n}\n",
+			wantHeader: map[string]string{"Content-Type": "application/json", "Location": "/tenants/1"},
+		},
+		{
+			name:       "non-marshalable data falls back to server error",
+			status:     http.StatusCreated,
+			data:       make(chan int),
+			headers:    http.Header{"Location": []string{"/tenants/1"}},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "{\n\t\"errors\": \"the server encountered a problem and could not process your request\"\n}\n",
+			wantHeader: map[string]string{"Content-Type": "application/json", "Location": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			httputils.RespondJSON(rr, req, tt.status, tt.data, tt.headers)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("unexpected status: got %d, want %d", rr.Code, tt.wantStatus)
+			}
+
+			if gotBody := rr.Body.String(); gotBody != tt.wantBody {
+				t.Errorf("unexpected body: got %q, want %q", gotBody, tt.wantBody)
+			}
+
+			for key, value := range tt.wantHeader {
+				if got := rr.Header().Get(key); got != value {
+					t.Errorf("unexpected header %q: got %q, want %q", key, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	t.Parallel()
+
+	type Dest struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	tests := []struct {
+		name          string
+		body          string
+		expected      Dest
+		expectedError string
+	}{
+		{"valid JSON", `{"name":"John","age":30}`, Dest{Name: "John", Age: 30}, ""},
+		{"syntax error", `{"name":"John",}`, Dest{}, "body contains badly-formed JSON at (character 16)"},
+		{"truncated body", `{"name":"John"`, Dest{}, "body contains badly-formed JSON"},
+		{"field type mismatch", `{"age":"thirty"}`, Dest{}, "body contains incorrect JSON type for field \"age\""},
+		{"top level type mismatch", `["John"]`, Dest{}, "body contains incorrect JSON type (at character 1)"},
+		{"empty body", ``, Dest{}, "body must not be empty"},
+		{"unknown field", `{"nickname":"Johnny"}`, Dest{}, "body contains unknown key \"nickname\""},
+		{"multiple JSON values", `{"name":"John"}{"name":"Jane"}`, Dest{}, "body must only contain a single JSON value"},
+		{"trailing garbage", `{"name":"John"} garbage`, Dest{}, "body must only contain a single JSON value"},
+		{
+			"body too large",
+			`{"name":"` + strings.Repeat("a", 1_048_577) + `"}`,
+			Dest{},
+			"body must not be larger than 1048576 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+
+			var dst Dest
+
+			err := httputils.DecodeJSON(rr, r, &dst)
+
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+
+				if dst != tt.expected {
+					t.Errorf("expected %+v, got %+v", tt.expected, dst)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, httputils.ErrInvalidJSON) {
+				t.Errorf("expected ErrInvalidJSON, got %v", err)
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
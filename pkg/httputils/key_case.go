@@ -0,0 +1,350 @@
+package httputils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+	"github.com/gurch101/gowebutils/pkg/stringutils"
+)
+
+// KeyCase selects the casing of object keys in JSON responses written by RespondJSON.
+type KeyCase string
+
+const (
+	// KeyCaseCamel renders keys as declared in the json struct tags, e.g. tenantName.
+	KeyCaseCamel KeyCase = "camel"
+	// KeyCaseSnake renders keys in snake_case, e.g. tenant_name.
+	KeyCaseSnake KeyCase = "snake"
+)
+
+type keyCaseContextKey struct{}
+
+// GetKeyCase returns the key casing selected by the JSON_KEY_CASE environment variable (camel or snake).
+// It defaults to camel.
+func GetKeyCase() KeyCase {
+	keyCase := KeyCase(parser.ParseEnvString("JSON_KEY_CASE", string(KeyCaseCamel)))
+	if keyCase != KeyCaseSnake {
+		return KeyCaseCamel
+	}
+
+	return keyCase
+}
+
+// KeyCaseMiddleware sets the casing of object keys in JSON responses written by RespondJSON and in the field
+// names of validation errors for the request. Only keys derived from struct fields are cased; map keys are
+// written as they are. In snake mode, request bodies decoded by DecodeJSON may use snake_case keys as well as
+// the json struct tag names.
+func KeyCaseMiddleware(keyCase KeyCase) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), keyCaseContextKey{}, keyCase)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// contextGetKeyCase returns the key casing set by KeyCaseMiddleware, or KeyCaseCamel if it is not set.
+func contextGetKeyCase(ctx context.Context) KeyCase {
+	keyCase, ok := ctx.Value(keyCaseContextKey{}).(KeyCase)
+	if !ok {
+		return KeyCaseCamel
+	}
+
+	return keyCase
+}
+
+// convertKeyCase rewrites the object keys of the encoded JSON value jsonPayload that were derived from the
+// struct fields of data to keyCase, keeping keys in their encoded order. Map keys are payload data, so they
+// are left as they are. The result is compact.
+func convertKeyCase(jsonPayload []byte, data any, keyCase KeyCase) ([]byte, error) {
+	if keyCase != KeyCaseSnake {
+		return jsonPayload, nil
+	}
+
+	converter := newKeyCaseConverter(jsonPayload, func(key, name string) (string, bool) {
+		return stringutils.CamelToSnake(name), key == name
+	})
+
+	if err := converter.value(reflect.ValueOf(data)); err != nil {
+		return nil, fmt.Errorf("failed to convert json key case: %w", err)
+	}
+
+	return converter.out.Bytes(), nil
+}
+
+// convertRequestKeyCase rewrites the object keys of the first JSON value in body that name a struct field of
+// dst in keyCase to the field's json name, so that the body can be decoded into dst. Anything after the first
+// value is left as it is.
+func convertRequestKeyCase(body []byte, dst any, keyCase KeyCase) ([]byte, error) {
+	if keyCase != KeyCaseSnake {
+		return body, nil
+	}
+
+	converter := newKeyCaseConverter(body, func(key, name string) (string, bool) {
+		return name, key == name || key == stringutils.CamelToSnake(name)
+	})
+
+	if err := converter.value(reflect.ValueOf(dst)); err != nil {
+		return nil, fmt.Errorf("failed to convert json key case: %w", err)
+	}
+
+	return append(converter.out.Bytes(), body[converter.dec.InputOffset():]...), nil
+}
+
+// keyCaseConverter re-encodes a JSON value, renaming the keys of objects that were encoded from, or will be
+// decoded into, a struct. The Go value guides the conversion so that map keys and values with custom JSON
+// encodings are left unchanged.
+type keyCaseConverter struct {
+	dec *json.Decoder
+	out bytes.Buffer
+	// rename returns the key to write for an object key that names the struct field with the given json name,
+	// or false if the key does not name the field.
+	rename func(key, name string) (string, bool)
+}
+
+// jsonField is an exported struct field and the name it is encoded with.
+type jsonField struct {
+	name  string
+	index []int
+}
+
+//nolint:gochecknoglobals
+var (
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+)
+
+func newKeyCaseConverter(jsonPayload []byte, rename func(key, name string) (string, bool)) *keyCaseConverter {
+	dec := json.NewDecoder(bytes.NewReader(jsonPayload))
+	dec.UseNumber()
+
+	return &keyCaseConverter{dec: dec, out: bytes.Buffer{}, rename: rename}
+}
+
+// value re-encodes the next JSON value, using v to decide which object keys to rename.
+func (c *keyCaseConverter) value(v reflect.Value) error {
+	token, err := c.dec.Token()
+	if err != nil {
+		return err //nolint: wrapcheck
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		if value == '{' {
+			return c.object(indirectJSONValue(v))
+		}
+
+		return c.array(indirectJSONValue(v))
+	case string:
+		encoded, _ := json.Marshal(value) //nolint: errchkjson
+		c.out.Write(encoded)
+	case json.Number:
+		c.out.WriteString(value.String())
+	case bool:
+		fmt.Fprintf(&c.out, "%t", value)
+	case nil:
+		c.out.WriteString("null")
+	}
+
+	return nil
+}
+
+func (c *keyCaseConverter) object(v reflect.Value) error {
+	var fields []jsonField
+
+	isStruct := v.IsValid() && v.Kind() == reflect.Struct
+	if isStruct {
+		fields = structJSONFields(v.Type())
+	}
+
+	c.out.WriteByte('{')
+
+	for i := 0; c.dec.More(); i++ {
+		token, err := c.dec.Token()
+		if err != nil {
+			return err //nolint: wrapcheck
+		}
+
+		key, _ := token.(string)
+		elem := reflect.Value{}
+
+		switch {
+		case isStruct:
+			key, elem = c.structField(v, fields, key)
+		case v.IsValid() && v.Kind() == reflect.Map:
+			elem = mapJSONValue(v, key)
+		}
+
+		if i > 0 {
+			c.out.WriteByte(',')
+		}
+
+		encoded, _ := json.Marshal(key) //nolint: errchkjson
+		c.out.Write(encoded)
+		c.out.WriteByte(':')
+
+		if err := c.value(elem); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing delimiter.
+	if _, err := c.dec.Token(); err != nil {
+		return err //nolint: wrapcheck
+	}
+
+	c.out.WriteByte('}')
+
+	return nil
+}
+
+func (c *keyCaseConverter) array(v reflect.Value) error {
+	isList := v.IsValid() && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array)
+
+	c.out.WriteByte('[')
+
+	for i := 0; c.dec.More(); i++ {
+		elem := reflect.Value{}
+
+		switch {
+		case isList && i < v.Len():
+			elem = v.Index(i)
+		case isList:
+			elem = reflect.Zero(v.Type().Elem())
+		}
+
+		if i > 0 {
+			c.out.WriteByte(',')
+		}
+
+		if err := c.value(elem); err != nil {
+			return err
+		}
+	}
+
+	if _, err := c.dec.Token(); err != nil {
+		return err //nolint: wrapcheck
+	}
+
+	c.out.WriteByte(']')
+
+	return nil
+}
+
+// structField returns the key to write for key in an object encoded from the struct v and the value of the
+// field it names. Keys that do not name a field are returned unchanged with an invalid value.
+func (c *keyCaseConverter) structField(v reflect.Value, fields []jsonField, key string) (string, reflect.Value) {
+	for _, field := range fields {
+		if renamed, ok := c.rename(key, field.name); ok {
+			return renamed, structFieldValue(v, field.index)
+		}
+	}
+
+	return key, reflect.Value{}
+}
+
+// indirectJSONValue dereferences pointers and interfaces in v. Nil pointers are replaced with the zero value
+// of the type they point to. An invalid value is returned if v encodes itself, since its keys are not
+// derived from struct fields.
+func indirectJSONValue(v reflect.Value) reflect.Value {
+	for v.IsValid() {
+		if t := v.Type(); t.Implements(jsonMarshalerType) || t.Implements(jsonUnmarshalerType) ||
+			reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+			return reflect.Value{}
+		}
+
+		switch v.Kind() { //nolint: exhaustive
+		case reflect.Pointer:
+			if v.IsNil() {
+				v = reflect.Zero(v.Type().Elem())
+			} else {
+				v = v.Elem()
+			}
+		case reflect.Interface:
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+
+			v = v.Elem()
+		default:
+			return v
+		}
+	}
+
+	return v
+}
+
+// structJSONFields returns the exported fields of the struct type t with the names encoding/json uses for
+// them, including the fields promoted from embedded structs.
+func structJSONFields(t reflect.Type) []jsonField {
+	fields := []jsonField{}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				for _, promoted := range structJSONFields(embedded) {
+					fields = append(fields, jsonField{name: promoted.name, index: append([]int{i}, promoted.index...)})
+				}
+
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, jsonField{name: name, index: []int{i}})
+	}
+
+	return fields
+}
+
+// structFieldValue returns the field of the struct v at index, using zero values for nil embedded pointers.
+func structFieldValue(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		v = indirectJSONValue(v)
+		if !v.IsValid() || v.Kind() != reflect.Struct {
+			return reflect.Value{}
+		}
+
+		v = v.Field(i)
+	}
+
+	return v
+}
+
+// mapJSONValue returns the element of the map v stored at key, or the zero element if there is none.
+func mapJSONValue(v reflect.Value, key string) reflect.Value {
+	if v.Type().Key().Kind() == reflect.String {
+		if elem := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key())); elem.IsValid() {
+			return elem
+		}
+	}
+
+	return reflect.Zero(v.Type().Elem())
+}
@@ -0,0 +1,303 @@
+package httputils_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+type keyCaseTestTenant struct {
+	ID           int64    `json:"id"`
+	TenantName   string   `json:"tenantName"`
+	ContactEmail string   `json:"contactEmail"`
+	IsActive     bool     `json:"isActive"`
+	Tags         []string `json:"tags"`
+	Parent       *struct {
+		TenantName string `json:"tenantName"`
+	} `json:"parent"`
+}
+
+func respondWithKeyCase(t *testing.T, keyCase *httputils.KeyCase, data any) string {
+	t.Helper()
+
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httputils.RespondJSON(w, r, http.StatusOK, data, nil)
+	})
+
+	if keyCase != nil {
+		handler = httputils.KeyCaseMiddleware(*keyCase)(handler)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	return rr.Body.String()
+}
+
+func TestRespondJSONKeyCase(t *testing.T) {
+	t.Parallel()
+
+	camel := httputils.KeyCaseCamel
+	snake := httputils.KeyCaseSnake
+
+	//nolint: exhaustruct
+	tenant := keyCaseTestTenant{ID: 1, TenantName: "Acme", ContactEmail: "admin@acme.com", IsActive: true}
+	tenant.Tags = []string{"tenantName"}
+	tenant.Parent = &struct {
+		TenantName string `json:"tenantName"`
+	}{TenantName: "Globex"}
+
+	tests := []struct {
+		name     string
+		keyCase  *httputils.KeyCase
+		data     any
+		expected string
+	}{
+		{
+			name:    "default",
+			keyCase: nil,
+			data:    tenant,
+			expected: `{"id":1,"tenantName":"Acme","contactEmail":"admin@acme.com","isActive":true,` +
+				`"tags":["tenantName"],"parent":{"tenantName":"Globex"}}`,
+		},
+		{
+			name:    "camel",
+			keyCase: &camel,
+			data:    tenant,
+			expected: `{"id":1,"tenantName":"Acme","contactEmail":"admin@acme.com","isActive":true,` +
+				`"tags":["tenantName"],"parent":{"tenantName":"Globex"}}`,
+		},
+		{
+			name:    "snake",
+			keyCase: &snake,
+			data:    tenant,
+			expected: `{"id":1,"tenant_name":"Acme","contact_email":"admin@acme.com","is_active":true,` +
+				`"tags":["tenantName"],"parent":{"tenant_name":"Globex"}}`,
+		},
+		{
+			name:    "snake keeps map keys",
+			keyCase: &snake,
+			data: map[string]any{
+				"tenants":      []keyCaseTestTenant{tenant},
+				"totalRecords": 1,
+				"metadata":     map[string]string{"favoriteColor": "blue"},
+			},
+			expected: `{"tenants":[{"id":1,"tenant_name":"Acme","contact_email":"admin@acme.com","is_active":true,` +
+				`"tags":["tenantName"],"parent":{"tenant_name":"Globex"}}],"totalRecords":1,` +
+				`"metadata":{"favoriteColor":"blue"}}`,
+		},
+		{
+			name:     "snake scalar",
+			keyCase:  &snake,
+			data:     "tenantName",
+			expected: `"tenantName"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			body := respondWithKeyCase(t, tt.keyCase, tt.data)
+
+			var got, expected any
+			if err := json.Unmarshal([]byte(body), &got); err != nil {
+				t.Fatalf("failed to decode response %q: %v", body, err)
+			}
+
+			_ = json.Unmarshal([]byte(tt.expected), &expected)
+
+			gotJSON, _ := json.Marshal(got)
+			expectedJSON, _ := json.Marshal(expected)
+
+			if string(gotJSON) != string(expectedJSON) {
+				t.Errorf("expected %s, got %s", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestRespondJSONSnakeCaseKeepsKeyOrder(t *testing.T) {
+	t.Parallel()
+
+	snake := httputils.KeyCaseSnake
+
+	//nolint: exhaustruct
+	body := respondWithKeyCase(t, &snake, keyCaseTestTenant{ID: 1, TenantName: "Acme"})
+
+	expected := "{\n\t\"id\": 1,\n\t\"tenant_name\": \"Acme\",\n\t\"contact_email\": \"\",\n\t\"is_active\": false,\n" +
+		"\t\"tags\": null,\n\t\"parent\": null\n}\n"
+	if body != expected {
+		t.Errorf("expected %q, got %q", expected, body)
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestGetKeyCase(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected httputils.KeyCase
+	}{
+		{"", httputils.KeyCaseCamel},
+		{"camel", httputils.KeyCaseCamel},
+		{"snake", httputils.KeyCaseSnake},
+		{"kebab", httputils.KeyCaseCamel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("JSON_KEY_CASE", tt.value)
+
+			if keyCase := httputils.GetKeyCase(); keyCase != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, keyCase)
+			}
+		})
+	}
+}
+
+func TestKeyCaseValidationErrorFields(t *testing.T) {
+	t.Parallel()
+
+	handler := httputils.KeyCaseMiddleware(httputils.KeyCaseSnake)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			httputils.FailedValidationResponse(w, r, []validation.Error{
+				{Field: "tenantName", Message: "Tenant name is required"},
+			})
+		}),
+	)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	var response struct {
+		Errors []validation.Error `json:"errors"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(response.Errors) != 1 || response.Errors[0].Field != "tenant_name" {
+		t.Errorf("expected snake_case error field, got %+v", response.Errors)
+	}
+}
+
+func TestKeyCaseBatchValidationErrorFields(t *testing.T) {
+	t.Parallel()
+
+	handler := httputils.KeyCaseMiddleware(httputils.KeyCaseSnake)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			httputils.WriteBatchResponse(w, r, []httputils.BatchResult{
+				httputils.BatchSuccess(0, http.StatusCreated, nil),
+				httputils.BatchValidationFailure(1, []validation.Error{
+					{Field: "tenantName", Message: "Tenant name is required"},
+				}),
+			})
+		}),
+	)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	var response struct {
+		Results []struct {
+			Errors []validation.Error `json:"errors"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(response.Results) != 2 || len(response.Results[1].Errors) != 1 ||
+		response.Results[1].Errors[0].Field != "tenant_name" {
+		t.Errorf("expected snake_case batch error field, got %+v", response.Results)
+	}
+}
+
+func TestDecodeJSONKeyCase(t *testing.T) {
+	t.Parallel()
+
+	type request struct {
+		TenantName string            `json:"tenantName"`
+		Labels     map[string]string `json:"labels"`
+		Contacts   []struct {
+			ContactEmail string `json:"contactEmail"`
+		} `json:"contacts"`
+	}
+
+	tests := []struct {
+		name        string
+		keyCase     httputils.KeyCase
+		body        string
+		expectError bool
+	}{
+		{
+			name:    "snake accepts snake_case keys",
+			keyCase: httputils.KeyCaseSnake,
+			body:    `{"tenant_name":"Acme","labels":{"costCenter":"1"},"contacts":[{"contact_email":"a@acme.com"}]}`,
+		},
+		{
+			name:    "snake accepts json tag names",
+			keyCase: httputils.KeyCaseSnake,
+			body:    `{"tenantName":"Acme","labels":{"costCenter":"1"},"contacts":[{"contactEmail":"a@acme.com"}]}`,
+		},
+		{
+			name:        "snake rejects unknown keys",
+			keyCase:     httputils.KeyCaseSnake,
+			body:        `{"tenant":"Acme"}`,
+			expectError: true,
+		},
+		{
+			name:        "camel rejects snake_case keys",
+			keyCase:     httputils.KeyCaseCamel,
+			body:        `{"tenant_name":"Acme"}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				dst request
+				err error
+			)
+
+			handler := httputils.KeyCaseMiddleware(tt.keyCase)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					err = httputils.DecodeJSON(w, r, &dst)
+				}),
+			)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.expectError {
+				if !errors.Is(err, httputils.ErrInvalidJSON) {
+					t.Errorf("expected invalid JSON error, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if dst.TenantName != "Acme" || dst.Labels["costCenter"] != "1" ||
+				len(dst.Contacts) != 1 || dst.Contacts[0].ContactEmail != "a@acme.com" {
+				t.Errorf("unexpected decoded request %+v", dst)
+			}
+		})
+	}
+}
@@ -3,28 +3,41 @@ package httputils
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/gurch101/gowebutils/pkg/parser"
 )
 
 type contextKey string
 
 const (
 	LogUserIDKey = contextKey("user_id")
+	// LogRequestIDKey is the context key for a request ID that should be logged with every record, for
+	// work that does not pass through RequestIDMiddleware such as background jobs.
+	LogRequestIDKey = contextKey("request_id")
 )
 
 type idHandler struct {
 	slog.Handler
 }
 
+// NewContextHandler wraps h so that records logged with a request context include the request_id
+// and user_id attributes. The request ID is taken from RequestIDMiddleware, falling back to LogRequestIDKey.
+// NewLogger wraps its handler with it.
+func NewContextHandler(h slog.Handler) slog.Handler { //nolint: ireturn
+	return &idHandler{h}
+}
+
 func (h *idHandler) Handle(ctx context.Context, record slog.Record) error {
-	id, ok := ctx.Value(middleware.RequestIDKey).(string)
+	id, ok := RequestIDFromContext(ctx)
 	if ok {
 		record.AddAttrs(slog.String("request_id", id))
 	}
@@ -42,6 +55,16 @@ func (h *idHandler) Handle(ctx context.Context, record slog.Record) error {
 	return nil
 }
 
+// WithAttrs keeps the request context attributes on loggers derived with slog.Logger.With.
+func (h *idHandler) WithAttrs(attrs []slog.Attr) slog.Handler { //nolint: ireturn
+	return &idHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup keeps the request context attributes on loggers derived with slog.Logger.WithGroup.
+func (h *idHandler) WithGroup(name string) slog.Handler { //nolint: ireturn
+	return &idHandler{h.Handler.WithGroup(name)}
+}
+
 // slogErrorWriter adapts slog.Logger to io.Writer so that it can passed to http.Server struct ErrorLog.
 type slogErrorWriter struct {
 	logger *slog.Logger
@@ -77,59 +100,127 @@ func getLogLevelFromString(level string) slog.Level {
 	}
 }
 
-func InitializeSlog(level string) *slog.Logger {
-	handler := &idHandler{slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+// LogFormat selects the slog handler used by NewLogger.
+type LogFormat string
+
+const (
+	// LogFormatText writes logs as logfmt-style key=value pairs.
+	LogFormatText LogFormat = "text"
+	// LogFormatJSON writes logs as one JSON object per line.
+	LogFormatJSON LogFormat = "json"
+)
+
+// NewLogger creates a logger that writes records at or above level to w in the given format, with times
+// in UTC and the request_id and user_id attributes added from the request context. Unknown levels default
+// to info and unknown formats default to text. DefaultRedactedFields are redacted.
+func NewLogger(w io.Writer, level string, format LogFormat) *slog.Logger {
+	return NewLoggerWithRedactor(w, level, format, NewRedactor(DefaultRedactedFields...))
+}
+
+// NewLoggerWithRedactor is like NewLogger but masks the fields redacted by redactor.
+func NewLoggerWithRedactor(w io.Writer, level string, format LogFormat, redactor *Redactor) *slog.Logger {
+	options := &slog.HandlerOptions{
 		AddSource: false,
-		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
 			// Format time in UTC
 			if attr.Key == slog.TimeKey {
 				if t, ok := attr.Value.Any().(time.Time); ok {
 					attr.Value = slog.StringValue(t.UTC().Format(time.RFC3339))
 				}
+
+				return attr
 			}
 
-			return attr
+			return redactor.ReplaceAttr(groups, attr)
 		},
 		Level: getLogLevelFromString(level),
-	})}
+	}
 
-	logger := slog.New(handler)
+	var handler slog.Handler
+	if LogFormat(strings.ToLower(string(format))) == LogFormatJSON {
+		handler = slog.NewJSONHandler(w, options)
+	} else {
+		handler = slog.NewTextHandler(w, options)
+	}
+
+	return slog.New(NewContextHandler(handler))
+}
+
+// InitializeSlog installs a text logger writing to stdout at the given level as the default slog logger.
+func InitializeSlog(level string) *slog.Logger {
+	logger := NewLogger(os.Stdout, level, LogFormatText)
 	slog.SetDefault(logger)
 
 	return logger
 }
 
+// SetupLogger installs a logger writing to stdout as the default slog logger. The level is read from
+// the LOG_LEVEL environment variable (debug, info, warn or error; defaults to info), the format from
+// LOG_FORMAT (text or json; defaults to text), and header and JSON field names to redact in addition to
+// DefaultRedactedFields from the comma-separated LOG_REDACT_FIELDS.
+func SetupLogger() *slog.Logger {
+	level := parser.ParseEnvString("LOG_LEVEL", "info")
+	format := LogFormat(parser.ParseEnvString("LOG_FORMAT", string(LogFormatText)))
+	logger := NewLoggerWithRedactor(os.Stdout, level, format, NewRedactor(getRedactedFields()...))
+	slog.SetDefault(logger)
+
+	return logger
+}
+
+// getRedactedFields returns DefaultRedactedFields along with the comma-separated LOG_REDACT_FIELDS.
+func getRedactedFields() []string {
+	return slices.Concat(DefaultRedactedFields, parser.ParseEnvStringSlice("LOG_REDACT_FIELDS", nil))
+}
+
 type SlogLogFormatter struct {
 	Logger *slog.Logger
+	// SlowThreshold is the duration at or above which completed requests are logged at WARN rather than INFO
+	// level. Zero logs every request at INFO level.
+	SlowThreshold time.Duration
 }
 
 // NewSlogLogFormatter creates a new SlogLogFormatter.
 func NewSlogLogFormatter(logger *slog.Logger) *SlogLogFormatter {
-	return &SlogLogFormatter{Logger: logger}
+	return &SlogLogFormatter{Logger: logger, SlowThreshold: 0}
 }
 
 // NewLogEntry creates a new LogEntry for the request.
 func (f *SlogLogFormatter) NewLogEntry(r *http.Request) middleware.LogEntry { //nolint:ireturn
 	return &SlogLogEntry{
-		logger:  f.Logger,
-		request: r,
+		logger:        f.Logger,
+		request:       r,
+		slowThreshold: f.SlowThreshold,
 	}
 }
 
 type SlogLogEntry struct {
-	logger  *slog.Logger
-	request *http.Request
+	logger        *slog.Logger
+	request       *http.Request
+	slowThreshold time.Duration
 }
 
-// Write logs the request completion details.
+// Write logs the request completion details. A status of 0 means the handler did not write a response,
+// in which case net/http sends a 200. The route field holds the matched route pattern, which unlike the
+// path does not vary with path parameters. Requests taking at least the formatter's SlowThreshold are logged
+// at WARN level.
 func (e *SlogLogEntry) Write(status, bytes int, _ http.Header, elapsed time.Duration, _ interface{}) {
-	e.logger.InfoContext(e.request.Context(), "request completed",
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	level := slog.LevelInfo
+	if e.slowThreshold > 0 && elapsed >= e.slowThreshold {
+		level = slog.LevelWarn
+	}
+
+	e.logger.LogAttrs(e.request.Context(), level, "request completed",
 		slog.String("method", e.request.Method),
 		slog.String("path", e.request.URL.Path),
+		slog.String("route", RoutePattern(e.request)),
 		slog.Int("status", status),
-		slog.Int("bytes", bytes),
+		slog.Int("bytes_written", bytes),
 		slog.Duration("elapsed", elapsed),
-		slog.String("ip", e.request.RemoteAddr),
+		slog.String("client_ip", clientHost(e.request)),
 	)
 }
 
@@ -138,7 +229,7 @@ func (e *SlogLogEntry) Panic(v interface{}, stack []byte) {
 	e.logger.ErrorContext(e.request.Context(), "request panicked",
 		slog.String("method", e.request.Method),
 		slog.String("path", e.request.URL.Path),
-		slog.String("ip", e.request.RemoteAddr),
+		slog.String("client_ip", clientHost(e.request)),
 		slog.Any("panic", v),
 		slog.String("stack", string(stack)),
 	)
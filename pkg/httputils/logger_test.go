@@ -0,0 +1,288 @@
+package httputils_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestSlogLogFormatter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	handler := middleware.RequestLogger(httputils.NewSlogLogFormatter(logger))(http.NotFoundHandler())
+
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	var record map[string]any
+
+	err := json.Unmarshal(buf.Bytes(), &record)
+	if err != nil {
+		t.Fatalf("failed to unmarshal log record %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]any{
+		"msg":           "request completed",
+		"method":        http.MethodGet,
+		"path":          "/missing",
+		"status":        float64(http.StatusNotFound),
+		"bytes_written": float64(rr.Body.Len()),
+		"client_ip":     "192.0.2.1",
+	}
+
+	for key, value := range expected {
+		if record[key] != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, record[key])
+		}
+	}
+}
+
+func TestSlogLogFormatterRoutePattern(t *testing.T) {
+	t.Parallel()
+
+	ok := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	tests := []struct {
+		name     string
+		handler  func(logger func(http.Handler) http.Handler) http.Handler
+		path     string
+		expected string
+	}{
+		{
+			name: "chi route",
+			handler: func(logger func(http.Handler) http.Handler) http.Handler {
+				router := chi.NewRouter()
+				router.Use(logger)
+				router.Get("/tenants/{id}", ok)
+
+				return router
+			},
+			path:     "/tenants/42",
+			expected: "/tenants/{id}",
+		},
+		{
+			name: "serve mux route",
+			handler: func(logger func(http.Handler) http.Handler) http.Handler {
+				mux := http.NewServeMux()
+				mux.HandleFunc("GET /tenants/{id}", ok)
+
+				return logger(mux)
+			},
+			path:     "/tenants/42",
+			expected: "/tenants/{id}",
+		},
+		{
+			name: "unmatched route",
+			handler: func(logger func(http.Handler) http.Handler) http.Handler {
+				router := chi.NewRouter()
+				router.Use(logger)
+				router.Get("/tenants/{id}", ok)
+
+				return router
+			},
+			path:     "/missing",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			handler := tt.handler(httputils.AccessLogMiddleware(httputils.NewSlogLogFormatter(logger)))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("failed to unmarshal log record %q: %v", buf.String(), err)
+			}
+
+			if record["route"] != tt.expected {
+				t.Errorf("expected route %q, got %v", tt.expected, record["route"])
+			}
+
+			if record["path"] != tt.path {
+				t.Errorf("expected path %q, got %v", tt.path, record["path"])
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestSetupLogger(t *testing.T) {
+	tests := []struct {
+		level        string
+		debugEnabled bool
+	}{
+		{level: "debug", debugEnabled: true},
+		{level: "info", debugEnabled: false},
+		{level: "", debugEnabled: false},
+	}
+
+	previous := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			t.Setenv("LOG_LEVEL", tt.level)
+			t.Setenv("LOG_FORMAT", "json")
+
+			logger := httputils.SetupLogger()
+
+			if got := logger.Enabled(context.Background(), slog.LevelDebug); got != tt.debugEnabled {
+				t.Errorf("expected debug enabled to be %v, got %v", tt.debugEnabled, got)
+			}
+
+			if slog.Default() != logger {
+				t.Error("expected the logger to be installed as the default")
+			}
+		})
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := httputils.NewLogger(&buf, "debug", httputils.LogFormatJSON)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(httputils.RequestIDHeader, "req-123")
+
+	handler := httputils.RequestIDMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		logger.DebugContext(r.Context(), "loading tenant")
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to unmarshal log record %q: %v", buf.String(), err)
+	}
+
+	if record["level"] != "DEBUG" || record["msg"] != "loading tenant" {
+		t.Errorf("expected a debug record, got %v", record)
+	}
+
+	if record["request_id"] != "req-123" {
+		t.Errorf("expected request_id req-123, got %v", record["request_id"])
+	}
+}
+
+func TestContextHandlerRequestID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		log  func(logger *slog.Logger, ctx context.Context)
+	}{
+		{
+			name: "request ID middleware",
+			ctx:  context.WithValue(context.Background(), middleware.RequestIDKey, "req-123"),
+			log:  func(logger *slog.Logger, ctx context.Context) { logger.InfoContext(ctx, "hello") },
+		},
+		{
+			name: "log request ID key",
+			ctx:  context.WithValue(context.Background(), httputils.LogRequestIDKey, "req-123"),
+			log:  func(logger *slog.Logger, ctx context.Context) { logger.InfoContext(ctx, "hello") },
+		},
+		{
+			name: "derived logger",
+			ctx:  context.WithValue(context.Background(), httputils.LogRequestIDKey, "req-123"),
+			log: func(logger *slog.Logger, ctx context.Context) {
+				logger.With("tenant_id", 1).WithGroup("job").InfoContext(ctx, "hello")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			logger := slog.New(httputils.NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+			tt.log(logger, tt.ctx)
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("failed to unmarshal log record %q: %v", buf.String(), err)
+			}
+
+			requestID := record["request_id"]
+			if group, ok := record["job"].(map[string]any); ok {
+				requestID = group["request_id"]
+			}
+
+			if requestID != "req-123" {
+				t.Errorf("expected request_id req-123, got record %v", record)
+			}
+		})
+	}
+}
+
+func TestSlogLogFormatterSlowRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		sleep         time.Duration
+		expectedLevel string
+	}{
+		{name: "fast request", sleep: 0, expectedLevel: "INFO"},
+		{name: "slow request", sleep: 20 * time.Millisecond, expectedLevel: "WARN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			formatter := httputils.NewSlogLogFormatter(slog.New(slog.NewJSONHandler(&buf, nil)))
+			formatter.SlowThreshold = 10 * time.Millisecond
+
+			handler := middleware.RequestLogger(formatter)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				time.Sleep(tt.sleep)
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tenants", nil))
+
+			var record map[string]any
+
+			err := json.Unmarshal(buf.Bytes(), &record)
+			if err != nil {
+				t.Fatalf("failed to unmarshal log record %q: %v", buf.String(), err)
+			}
+
+			if record["level"] != tt.expectedLevel {
+				t.Errorf("expected level %s, got %v", tt.expectedLevel, record["level"])
+			}
+
+			if elapsed, _ := record["elapsed"].(float64); time.Duration(elapsed) < tt.sleep {
+				t.Errorf("expected elapsed to be at least %v, got %v", tt.sleep, record["elapsed"])
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package httputils
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// unmatchedRoute is the route label used for requests that did not match a route.
+	unmatchedRoute = "unmatched"
+	// otherMethod is the method label used for non-standard request methods.
+	otherMethod = "other"
+)
+
+// methodLabel returns the method label for r, mapping non-standard methods to "other" so that clients cannot
+// create unbounded series by sending arbitrary methods.
+func methodLabel(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return r.Method
+	default:
+		return otherMethod
+	}
+}
+
+// MetricsMiddleware registers the following Prometheus metrics with registerer and records them:
+//
+//   - http_requests_total: a counter of completed requests labeled by method, route and status.
+//   - http_request_duration_seconds: a histogram of request durations labeled by method, route and status.
+//   - http_requests_in_flight: a gauge of requests currently being served labeled by method.
+//
+// The route label is the matched route pattern (e.g. /tenants/{id}) rather than the request path, and
+// non-standard methods are labeled "other", to keep the number of series bounded. Serve the metrics with
+// promhttp.HandlerFor. It panics if the metrics are already registered with registerer.
+func MetricsMiddleware(registerer prometheus.Registerer) func(next http.Handler) http.Handler {
+	factory := promauto.With(registerer)
+
+	//nolint: exhaustruct
+	requests := factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests.",
+	}, []string{"method", "route", "status"})
+	//nolint: exhaustruct
+	durations := factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request durations in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	//nolint: exhaustruct
+	inFlight := factory.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "Number of HTTP requests currently being served.",
+	}, []string{"method"})
+
+	return func(next http.Handler) http.Handler {
+		next = RecordRoutePattern(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			r, _ = withRoutePatternHolder(r)
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			method := methodLabel(r)
+
+			inFlight.WithLabelValues(method).Inc()
+
+			defer func() {
+				p := recover()
+
+				inFlight.WithLabelValues(method).Dec()
+
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+					if p != nil {
+						status = http.StatusInternalServerError
+					}
+				}
+
+				route := RoutePattern(r)
+				if route == "" {
+					route = unmatchedRoute
+				}
+
+				statusLabel := strconv.Itoa(status)
+				requests.WithLabelValues(method, route, statusLabel).Inc()
+				durations.WithLabelValues(method, route, statusLabel).Observe(time.Since(start).Seconds())
+
+				if p != nil {
+					panic(p)
+				}
+			}()
+
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
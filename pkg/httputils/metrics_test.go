@@ -0,0 +1,61 @@
+package httputils_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+
+	router := testutils.NewRouter()
+	router.Use(httputils.MetricsMiddleware(registry))
+	router.Get("/tenants/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	router.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{})) //nolint: exhaustruct
+
+	for _, path := range []string{"/tenants/1", "/tenants/2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("FOOBAR", "/tenants/1", nil))
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, err := io.ReadAll(rr.Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics: %v", err)
+	}
+
+	expected := []string{
+		`http_requests_total{method="GET",route="/tenants/{id}",status="200"} 2`,
+		`http_request_duration_seconds_count{method="GET",route="/tenants/{id}",status="200"} 2`,
+		`http_requests_in_flight{method="GET"} 1`,
+		`http_requests_in_flight{method="other"} 0`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", line, body)
+		}
+	}
+
+	if strings.Contains(string(body), `route="/tenants/1"`) {
+		t.Error("expected route label to use the route pattern rather than the path")
+	}
+
+	if strings.Contains(string(body), `method="FOOBAR"`) {
+		t.Error("expected non-standard methods to be labeled other")
+	}
+}
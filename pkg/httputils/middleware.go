@@ -1,21 +1,49 @@
 package httputils
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
-	"net"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/gurch101/gowebutils/pkg/parser"
-	"golang.org/x/time/rate"
 )
 
+// RateLimitConfig configures the middleware returned by NewRateLimitMiddleware.
 type RateLimitConfig struct {
-	enabled bool
-	rate    float64
-	burst   int
+	// Enabled turns rate limiting on. When false the middleware passes every request through.
+	Enabled bool
+	// Rate is the number of requests per second each key is allowed on average.
+	Rate float64
+	// Burst is the number of requests each key may make at once.
+	Burst int
+	// ExcludedPaths are never rate limited. A trailing * matches any path with that prefix; see SkipPaths.
+	ExcludedPaths []string
+	// KeyFunc returns the key requests are rate limited by. Defaults to RemoteIPRateLimitKey.
+	KeyFunc RateLimitKeyFunc
+}
+
+// ErrInvalidRateLimitConfig is returned when the rate or burst is not positive or cannot be parsed from env.
+var ErrInvalidRateLimitConfig = errors.New("invalid rate limit config")
+
+// Validate reports whether an enabled config has a positive Rate and Burst. Disabled configs are always valid.
+func (c RateLimitConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Rate <= 0 {
+		return fmt.Errorf("%w: rate must be positive, got %g", ErrInvalidRateLimitConfig, c.Rate)
+	}
+
+	if c.Burst <= 0 {
+		return fmt.Errorf("%w: burst must be positive, got %d", ErrInvalidRateLimitConfig, c.Burst)
+	}
+
+	return nil
 }
 
 const (
@@ -24,122 +52,188 @@ const (
 	defaultRateLimitBurst = 20
 )
 
-func getRateLimitConfig() *RateLimitConfig {
-	rateLimitConfig := &RateLimitConfig{
-		enabled: parser.ParseEnvBool("RATE_LIMIT_ENABLED", true),
-		rate:    defaultRateLimitRate,
-		burst:   defaultRateLimitBurst,
+// getRateLimitConfig reads the rate limit config from env. It returns an error if RATE_LIMIT_RATE or
+// RATE_LIMIT_BURST is set but cannot be parsed.
+func getRateLimitConfig() (RateLimitConfig, error) {
+	rateLimitConfig := RateLimitConfig{
+		Enabled:       parser.ParseEnvBool("RATE_LIMIT_ENABLED", true),
+		Rate:          defaultRateLimitRate,
+		Burst:         defaultRateLimitBurst,
+		ExcludedPaths: parser.ParseEnvStringSlice("RATE_LIMIT_EXCLUDED_PATHS", nil),
+		KeyFunc:       RemoteIPRateLimitKey,
 	}
-	if !rateLimitConfig.enabled {
-		return rateLimitConfig
+	if !rateLimitConfig.Enabled {
+		return rateLimitConfig, nil
 	}
 
-	rateLimit, err := parser.ParseEnvFloat64("RATE_LIMIT_RATE", rateLimitConfig.rate)
+	rateLimit, err := parser.ParseEnvFloat64("RATE_LIMIT_RATE", rateLimitConfig.Rate)
 	if err != nil {
-		panic(err)
+		return rateLimitConfig, fmt.Errorf("%w: %w", ErrInvalidRateLimitConfig, err)
 	}
 
-	rateLimitConfig.rate = rateLimit
+	rateLimitConfig.Rate = rateLimit
 
-	burst, err := parser.ParseEnvInt("RATE_LIMIT_BURST", rateLimitConfig.burst)
+	burst, err := parser.ParseEnvInt("RATE_LIMIT_BURST", rateLimitConfig.Burst)
 	if err != nil {
-		panic(err)
+		return rateLimitConfig, fmt.Errorf("%w: %w", ErrInvalidRateLimitConfig, err)
 	}
 
-	rateLimitConfig.burst = burst
+	rateLimitConfig.Burst = burst
 
-	return rateLimitConfig
+	return rateLimitConfig, nil
 }
 
-func RateLimitMiddleware(next http.Handler) http.Handler {
-	rateLimitConfig := getRateLimitConfig()
+// ErrNoRateLimitKey is returned when a rate limit key cannot be determined for a request.
+var ErrNoRateLimitKey = errors.New("could not determine rate limit key")
 
-	if !rateLimitConfig.enabled {
-		return next
-	}
+const (
+	rateLimitCleanupInterval = time.Minute
+	rateLimitClientTTL       = 3 * time.Minute
+)
 
-	slog.Info("rate limit middleware enabled", "rate", rateLimitConfig.rate, "burst", rateLimitConfig.burst)
+// RateLimitKeyFunc returns the key a request is rate limited by, such as the client IP, an API key or a
+// user id. Requests with the same key share a limiter. An empty key results in a 500 response.
+type RateLimitKeyFunc func(r *http.Request) string
 
-	type client struct {
-		limiter  *rate.Limiter
-		lastSeen time.Time
+// RemoteIPRateLimitKey rate limits requests by the IP address in the request's RemoteAddr. A RemoteAddr
+// without a port is used as-is.
+func RemoteIPRateLimitKey(r *http.Request) string {
+	return clientHost(r)
+}
+
+// HeaderRateLimitKey returns a RateLimitKeyFunc that rate limits requests by the value of the given header,
+// such as an API key header. Requests without the header are rate limited by client IP.
+func HeaderRateLimitKey(header string) RateLimitKeyFunc {
+	return func(r *http.Request) string {
+		if value := r.Header.Get(header); value != "" {
+			return header + ":" + value
+		}
+
+		return RemoteIPRateLimitKey(r)
 	}
+}
 
-	var (
-		mu      sync.Mutex
-		clients = make(map[string]*client)
-	)
+// RateLimitMiddleware rate limits requests per client IP using the rate and burst configured via env.
+// It panics if the env config is invalid; use GetRateLimitMiddleware to handle the error instead.
+func RateLimitMiddleware(next http.Handler) http.Handler {
+	rateLimit, err := GetRateLimitMiddleware(RemoteIPRateLimitKey)
+	if err != nil {
+		panic(err)
+	}
 
-	go func() {
-		for {
-			time.Sleep(time.Minute)
+	return rateLimit(next)
+}
 
-			mu.Lock()
-			for ip, c := range clients {
-				if time.Since(c.lastSeen) > 3*time.Minute {
-					delete(clients, ip)
-				}
-			}
-			mu.Unlock()
-		}
-	}()
+// GetRateLimitMiddleware returns a middleware that rate limits requests per key returned by keyFunc using
+// an in-memory backend with the rate and burst configured via env. Requests for the comma-separated paths in
+// RATE_LIMIT_EXCLUDED_PATHS, e.g. /healthz,/metrics, are never rate limited; see SkipPaths. It returns an
+// error wrapping ErrInvalidRateLimitConfig if the env config is invalid.
+func GetRateLimitMiddleware(keyFunc RateLimitKeyFunc) (func(next http.Handler) http.Handler, error) {
+	rateLimitConfig, err := getRateLimitConfig()
+	if err != nil {
+		return nil, err
+	}
 
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ip, _, err := net.SplitHostPort(r.RemoteAddr)
-		if err != nil {
-			ServerErrorResponse(w, r, fmt.Errorf("could not parse remote address: %w", err))
-		}
+	rateLimitConfig.KeyFunc = keyFunc
 
-		mu.Lock()
-		if _, ok := clients[ip]; !ok {
-			limiter := rate.NewLimiter(
-				rate.Limit(rateLimitConfig.rate),
-				rateLimitConfig.burst,
-			)
-			clients[ip] = &client{limiter: limiter, lastSeen: time.Now()}
-		} else {
-			clients[ip].lastSeen = time.Now()
-		}
+	return NewRateLimitMiddleware(rateLimitConfig)
+}
 
-		if !clients[ip].limiter.Allow() {
-			mu.Unlock()
-			RateLimitExceededResponse(w, r)
+// NewRateLimitMiddleware returns a middleware that rate limits requests per key using an in-memory backend
+// configured by cfg rather than env, e.g.:
+//
+//	rateLimit, err := httputils.NewRateLimitMiddleware(httputils.RateLimitConfig{Enabled: true, Rate: 5, Burst: 10})
+//
+// It returns the error from cfg.Validate if cfg is invalid.
+func NewRateLimitMiddleware(cfg RateLimitConfig) (func(next http.Handler) http.Handler, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
 
-			return
-		}
+	if !cfg.Enabled {
+		return func(next http.Handler) http.Handler {
+			return next
+		}, nil
+	}
+
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = RemoteIPRateLimitKey
+	}
+
+	slog.Info("rate limit middleware enabled", "rate", cfg.Rate, "burst", cfg.Burst,
+		"excluded_paths", cfg.ExcludedPaths)
 
-		mu.Unlock()
+	backend := NewMemoryRateLimitBackend(cfg.Rate, cfg.Burst)
 
-		next.ServeHTTP(w, r)
-	})
+	return SkipPaths(cfg.ExcludedPaths, GetRateLimitMiddlewareWithBackend(backend, cfg.KeyFunc)), nil
 }
 
-func GetCORSMiddleware(trustedOrigins []string) func(next http.Handler) http.Handler {
+// GetRateLimitMiddlewareWithBackend returns a middleware that rate limits requests per key returned by
+// keyFunc using the given backend. Use a RedisRateLimitBackend to share limits across instances.
+func GetRateLimitMiddlewareWithBackend(
+	backend RateLimitBackend,
+	keyFunc RateLimitKeyFunc,
+) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Add the "Vary: Origin" header.
-			w.Header().Add("Vary", "Origin")
-			// Get the value of the request's Origin header.
-			origin := r.Header.Get("Origin")
-			// Only run this if there's an Origin request header present.
-			if origin != "" {
-				// Loop through the list of trusted origins, checking to see if the request
-				// origin exactly matches one of them. If there are no trusted origins, then
-				// the loop won't be iterated.
-				for i := range trustedOrigins {
-					if origin == trustedOrigins[i] {
-						// If there is a match, then set a "Access-Control-Allow-Origin"
-						// response header with the request origin as the value and break
-						// out of the loop.
-						w.Header().Set("Access-Control-Allow-Origin", origin)
-
-						break
-					}
-				}
+			key := keyFunc(r)
+			if key == "" {
+				ServerErrorResponse(w, r, fmt.Errorf("%w: remote address %q", ErrNoRateLimitKey, r.RemoteAddr))
+
+				return
+			}
+
+			if !allowRateLimited(w, r, backend, key) {
+				return
 			}
 
-			// Call the next handler in the chain.
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// allowRateLimited records a request for key with backend and sets the rate limit headers. It reports whether
+// the request may proceed; if not, an error response has already been written.
+func allowRateLimited(w http.ResponseWriter, r *http.Request, backend RateLimitBackend, key string) bool {
+	result, err := backend.Allow(r.Context(), key)
+	if err != nil {
+		ServerErrorResponse(w, r, err)
+
+		return false
+	}
+
+	setRateLimitHeaders(w, result)
+
+	if !result.Allowed {
+		RateLimitExceededResponse(w, r)
+
+		return false
+	}
+
+	return true
+}
+
+// RateLimit returns a middleware that rate limits requests per client IP with its own rate and burst,
+// independent of the global env-based configuration. Use it to give individual routes stricter or looser
+// limits, e.g.:
+//
+//	router.Post("/login", httputils.RateLimit(1, 5)(http.HandlerFunc(loginHandler)).ServeHTTP)
+//
+// The limiter's backend evicts stale clients for the life of the process. To stop it, e.g. in tests, create a
+// MemoryRateLimitBackend, defer its Close and pass it to GetRateLimitMiddlewareWithBackend instead.
+func RateLimit(ratePerSecond float64, burst int) func(next http.Handler) http.Handler {
+	return GetRateLimitMiddlewareWithBackend(NewMemoryRateLimitBackend(ratePerSecond, burst), RemoteIPRateLimitKey)
+}
+
+// setRateLimitHeaders sets the X-RateLimit-Limit, X-RateLimit-Remaining and Retry-After headers from the
+// key's limiter state. Retry-After is the number of seconds until another request can be made.
+func setRateLimitHeaders(w http.ResponseWriter, result RateLimitResult) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+}
+
+// GetCORSMiddleware returns a CORSMiddleware that allows requests from the given trusted origins.
+func GetCORSMiddleware(trustedOrigins []string) func(next http.Handler) http.Handler {
+	return CORSMiddleware(CORSConfig{AllowedOrigins: trustedOrigins}) //nolint: exhaustruct
+}
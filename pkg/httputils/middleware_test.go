@@ -0,0 +1,377 @@
+package httputils_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestRateLimitMiddlewareHeaders(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "true")
+	t.Setenv("RATE_LIMIT_RATE", "1")
+	t.Setenv("RATE_LIMIT_BURST", "3")
+
+	handler := httputils.RateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	expected := []struct {
+		status     int
+		remaining  string
+		retryAfter string
+	}{
+		{http.StatusOK, "2", "0"},
+		{http.StatusOK, "1", "0"},
+		{http.StatusOK, "0", "1"},
+		{http.StatusTooManyRequests, "0", "1"},
+	}
+
+	for i, exp := range expected {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != exp.status {
+			t.Errorf("request %d: expected status %d, got %d", i, exp.status, rr.Code)
+		}
+
+		if limit := rr.Header().Get("X-RateLimit-Limit"); limit != "3" {
+			t.Errorf("request %d: expected limit 3, got %s", i, limit)
+		}
+
+		if remaining := rr.Header().Get("X-RateLimit-Remaining"); remaining != exp.remaining {
+			t.Errorf("request %d: expected remaining %s, got %s", i, exp.remaining, remaining)
+		}
+
+		if retryAfter := rr.Header().Get("Retry-After"); retryAfter != exp.retryAfter {
+			t.Errorf("request %d: expected Retry-After %s, got %s", i, exp.retryAfter, retryAfter)
+		}
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestRateLimitMiddlewareExcludedPaths(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "true")
+	t.Setenv("RATE_LIMIT_RATE", "0.001")
+	t.Setenv("RATE_LIMIT_BURST", "1")
+	t.Setenv("RATE_LIMIT_EXCLUDED_PATHS", "/healthz, /metrics,/static/*")
+
+	handler := httputils.RateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	doRequest := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	for _, path := range []string{"/healthz", "/metrics", "/static/app.css"} {
+		for i := range 3 {
+			rr := doRequest(path)
+			if rr.Code != http.StatusOK {
+				t.Errorf("%s request %d: expected excluded path not to be rate limited, got %d", path, i, rr.Code)
+			}
+
+			if rr.Header().Get("X-RateLimit-Limit") != "" {
+				t.Errorf("%s request %d: expected no rate limit headers", path, i)
+			}
+		}
+	}
+
+	if rr := doRequest("/tenants"); rr.Code != http.StatusOK {
+		t.Errorf("expected first request to /tenants to be allowed, got %d", rr.Code)
+	}
+
+	for _, path := range []string{"/tenants", "/healthz/details", "/"} {
+		if rr := doRequest(path); rr.Code != http.StatusTooManyRequests {
+			t.Errorf("expected %s to be rate limited, got %d", path, rr.Code)
+		}
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestRateLimitMiddlewareRemoteAddrWithoutPort(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "true")
+	t.Setenv("RATE_LIMIT_RATE", "0.001")
+	t.Setenv("RATE_LIMIT_BURST", "1")
+
+	calls := 0
+	handler := httputils.RateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		expected   int
+	}{
+		{"portless address is allowed", "192.0.2.1", http.StatusOK},
+		{"portless address is limited", "192.0.2.1", http.StatusTooManyRequests},
+		{"same ip with a port shares the limit", "192.0.2.1:1234", http.StatusTooManyRequests},
+		{"empty address is rejected", "", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.remoteAddr
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != tt.expected {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.expected, rr.Code)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected handler to be called once, got %d", calls)
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestGetRateLimitMiddlewareKeyFunc(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "true")
+	t.Setenv("RATE_LIMIT_RATE", "1")
+	t.Setenv("RATE_LIMIT_BURST", "1")
+
+	rateLimit, err := httputils.GetRateLimitMiddleware(httputils.HeaderRateLimitKey("X-API-Key"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handler := rateLimit(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	doRequest := func(apiKey, remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	tests := []struct {
+		name       string
+		apiKey     string
+		remoteAddr string
+		expected   int
+	}{
+		{"first request for key a", "a", "192.0.2.1:1234", http.StatusOK},
+		{"same key from another ip is limited", "a", "192.0.2.2:1234", http.StatusTooManyRequests},
+		{"different key from same ip is allowed", "b", "192.0.2.1:1234", http.StatusOK},
+		{"missing key falls back to ip", "", "192.0.2.3:1234", http.StatusOK},
+		{"missing key from same ip is limited", "", "192.0.2.3:1234", http.StatusTooManyRequests},
+		{"missing key without port uses raw address", "", "192.0.2.4", http.StatusOK},
+		{"missing key without remote address", "", "", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		if code := doRequest(tt.apiKey, tt.remoteAddr); code != tt.expected {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.expected, code)
+		}
+	}
+}
+
+func TestRateLimitPerRoute(t *testing.T) {
+	t.Parallel()
+
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /login", httputils.RateLimit(1, 1)(okHandler))
+	mux.Handle("GET /tenants", httputils.RateLimit(10, 3)(okHandler))
+
+	doRequest := func(method, path string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		return rr.Code
+	}
+
+	tests := []struct {
+		method   string
+		path     string
+		expected int
+	}{
+		{http.MethodPost, "/login", http.StatusOK},
+		{http.MethodPost, "/login", http.StatusTooManyRequests},
+		{http.MethodGet, "/tenants", http.StatusOK},
+		{http.MethodGet, "/tenants", http.StatusOK},
+		{http.MethodGet, "/tenants", http.StatusOK},
+		{http.MethodGet, "/tenants", http.StatusTooManyRequests},
+	}
+
+	for i, tt := range tests {
+		if code := doRequest(tt.method, tt.path); code != tt.expected {
+			t.Errorf("request %d to %s: expected status %d, got %d", i, tt.path, tt.expected, code)
+		}
+	}
+}
+
+func TestNewRateLimitMiddleware(t *testing.T) {
+	t.Parallel()
+
+	rateLimit, err := httputils.NewRateLimitMiddleware(httputils.RateLimitConfig{
+		Enabled:       true,
+		Rate:          0.001,
+		Burst:         2,
+		ExcludedPaths: []string{"/healthz"},
+		KeyFunc:       httputils.HeaderRateLimitKey("X-API-Key"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handler := rateLimit(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name     string
+		path     string
+		apiKey   string
+		expected int
+	}{
+		{"first request for key a", "/", "a", http.StatusOK},
+		{"second request for key a", "/", "a", http.StatusOK},
+		{"burst exhausted for key a", "/", "a", http.StatusTooManyRequests},
+		{"key b has its own limit", "/", "b", http.StatusOK},
+		{"excluded path is not limited", "/healthz", "a", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("X-API-Key", tt.apiKey)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != tt.expected {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.expected, rr.Code)
+		}
+	}
+}
+
+func TestNewRateLimitMiddlewareDisabled(t *testing.T) {
+	t.Parallel()
+
+	//nolint: exhaustruct
+	rateLimit, err := httputils.NewRateLimitMiddleware(httputils.RateLimitConfig{Rate: 0.001, Burst: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	handler := rateLimit(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for i := range 3 {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rr.Code != http.StatusOK || rr.Header().Get("X-RateLimit-Limit") != "" {
+			t.Errorf("request %d: expected disabled limiter to pass requests through, got %d", i, rr.Code)
+		}
+	}
+}
+
+func TestRateLimitConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		enabled bool
+		rate    float64
+		burst   int
+		wantErr bool
+	}{
+		{"valid", true, 1, 1, false},
+		{"zero rate", true, 0, 1, true},
+		{"negative rate", true, -1, 1, true},
+		{"zero burst", true, 1, 0, true},
+		{"disabled with zero values", false, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			//nolint: exhaustruct
+			config := httputils.RateLimitConfig{Enabled: tt.enabled, Rate: tt.rate, Burst: tt.burst}
+
+			err := config.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %v, got %v", tt.wantErr, err)
+			}
+
+			if tt.wantErr && !errors.Is(err, httputils.ErrInvalidRateLimitConfig) {
+				t.Errorf("expected ErrInvalidRateLimitConfig, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewRateLimitMiddlewareInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	//nolint: exhaustruct
+	rateLimit, err := httputils.NewRateLimitMiddleware(httputils.RateLimitConfig{Enabled: true, Rate: 0, Burst: 1})
+	if !errors.Is(err, httputils.ErrInvalidRateLimitConfig) || rateLimit != nil {
+		t.Errorf("expected ErrInvalidRateLimitConfig, got %v", err)
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestGetRateLimitMiddlewareInvalidEnv(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{"unparseable rate", "RATE_LIMIT_RATE", "fast"},
+		{"unparseable burst", "RATE_LIMIT_BURST", "lots"},
+		{"zero rate", "RATE_LIMIT_RATE", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RATE_LIMIT_ENABLED", "true")
+			t.Setenv(tt.key, tt.value)
+
+			_, err := httputils.GetRateLimitMiddleware(httputils.RemoteIPRateLimitKey)
+			if !errors.Is(err, httputils.ErrInvalidRateLimitConfig) {
+				t.Errorf("expected ErrInvalidRateLimitConfig, got %v", err)
+			}
+
+			_, err = httputils.StandardMiddleware(httputils.MiddlewareConfig{}) //nolint: exhaustruct
+			if !errors.Is(err, httputils.ErrInvalidRateLimitConfig) {
+				t.Errorf("expected StandardMiddleware to return ErrInvalidRateLimitConfig, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package httputils
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+const (
+	mediaTypeJSON  = "application/json"
+	mediaTypeHTML  = "text/html"
+	mediaTypePlain = "text/plain"
+)
+
+// Specificity of an Accept media range, used to break ties between offers with the same quality.
+const (
+	specificityNone = iota
+	specificityAny
+	specificityType
+	specificityExact
+)
+
+// NegotiateContentType returns the offer that best matches the Accept header. Offers are compared by
+// quality value first, then by how specifically the client listed them, then by their order in offers.
+// The first offer is returned if the Accept header is empty or none of the offers are acceptable.
+func NegotiateContentType(accept string, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return offers[0]
+	}
+
+	best := offers[0]
+	bestQuality := 0.0
+	bestSpecificity := specificityNone
+
+	for _, offer := range offers {
+		quality, specificity := acceptQuality(accept, offer)
+		if quality > bestQuality || (quality == bestQuality && quality > 0 && specificity > bestSpecificity) {
+			best, bestQuality, bestSpecificity = offer, quality, specificity
+		}
+	}
+
+	return best
+}
+
+// acceptQuality returns the quality value and specificity of the most specific Accept media range matching offer.
+func acceptQuality(accept, offer string) (float64, int) {
+	offerType, offerSubtype, _ := strings.Cut(offer, "/")
+	quality := 0.0
+	specificity := specificityNone
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		rangeType, rangeSubtype, _ := strings.Cut(mediaType, "/")
+
+		var s int
+
+		switch {
+		case rangeType == "*" && rangeSubtype == "*":
+			s = specificityAny
+		case rangeType == offerType && rangeSubtype == "*":
+			s = specificityType
+		case rangeType == offerType && rangeSubtype == offerSubtype:
+			s = specificityExact
+		default:
+			continue
+		}
+
+		if s <= specificity {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		quality, specificity = q, s
+	}
+
+	return quality, specificity
+}
+
+// NegotiateLanguage returns the offer that best matches the Accept-Language header. A language range
+// matches an offer exactly or by primary language, so "fr-CA" matches an "fr" offer and vice versa.
+// Offers are compared by quality value first, then by how specifically they matched, then by their order.
+// The first offer is returned if the header is empty or none of the offers are acceptable.
+func NegotiateLanguage(acceptLanguage string, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+
+	best := offers[0]
+	bestQuality := 0.0
+	bestSpecificity := specificityNone
+
+	for _, offer := range offers {
+		quality, specificity := languageQuality(acceptLanguage, offer)
+		if quality > bestQuality || (quality == bestQuality && quality > 0 && specificity > bestSpecificity) {
+			best, bestQuality, bestSpecificity = offer, quality, specificity
+		}
+	}
+
+	return best
+}
+
+// languageQuality returns the quality value and specificity of the most specific language range matching offer.
+func languageQuality(acceptLanguage, offer string) (float64, int) {
+	offerPrimary, _, _ := strings.Cut(offer, "-")
+	quality := 0.0
+	specificity := specificityNone
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		languageRange, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		rangePrimary, _, _ := strings.Cut(languageRange, "-")
+
+		var s int
+
+		switch {
+		case languageRange == "*":
+			s = specificityAny
+		case strings.EqualFold(languageRange, offer):
+			s = specificityExact
+		case strings.EqualFold(rangePrimary, offerPrimary):
+			s = specificityType
+		default:
+			continue
+		}
+
+		if s <= specificity {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		quality, specificity = q, s
+	}
+
+	return quality, specificity
+}
+
+// NewRequestValidator creates a Validator that renders error messages from catalog in the language the
+// client prefers according to its Accept-Language header, defaulting to validation.DefaultLanguage.
+func NewRequestValidator(r *http.Request, catalog *validation.Catalog) *validation.Validator {
+	return catalog.NewValidator(NegotiateLanguage(r.Header.Get("Accept-Language"), catalog.Languages()...))
+}
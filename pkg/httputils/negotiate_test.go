@@ -0,0 +1,73 @@
+package httputils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	t.Parallel()
+
+	offers := []string{"en", "de", "fr"}
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{name: "empty header", acceptLanguage: "", expected: "en"},
+		{name: "exact match", acceptLanguage: "fr", expected: "fr"},
+		{name: "region matches primary language", acceptLanguage: "fr-CA", expected: "fr"},
+		{name: "case insensitive", acceptLanguage: "DE", expected: "de"},
+		{name: "quality values", acceptLanguage: "fr;q=0.5, de;q=0.8", expected: "de"},
+		{name: "unsupported falls back to next", acceptLanguage: "es, de;q=0.9", expected: "de"},
+		{name: "none acceptable", acceptLanguage: "es, it", expected: "en"},
+		{name: "wildcard", acceptLanguage: "*", expected: "en"},
+		{name: "excluded language", acceptLanguage: "fr;q=0, *;q=0.1", expected: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if result := httputils.NegotiateLanguage(tt.acceptLanguage, offers...); result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestNewRequestValidator(t *testing.T) {
+	t.Parallel()
+
+	catalog := validation.NewCatalog().Add("fr", map[string]string{"Invalid plan": "Forfait invalide"})
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{name: "default language", acceptLanguage: "", expected: "Invalid plan"},
+		{name: "catalog override", acceptLanguage: "fr-FR, en;q=0.5", expected: "Forfait invalide"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/tenants", nil)
+			req.Header.Set("Accept-Language", tt.acceptLanguage)
+
+			v := httputils.NewRequestValidator(req, catalog)
+			v.Check(false, "plan", "Invalid plan")
+
+			if v.Errors[0].Message != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, v.Errors[0].Message)
+			}
+		})
+	}
+}
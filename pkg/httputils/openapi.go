@@ -0,0 +1,387 @@
+package httputils
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const openAPIVersion = "3.0.3"
+
+//nolint:gochecknoglobals
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	pathParamRX       = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+	schemaPackageRX   = regexp.MustCompile(`[\w./-]*\.`)
+	schemaNameReplace = strings.NewReplacer("[", "_", "]", "", ",", "_", "*", "")
+)
+
+// OpenAPIOperation describes an endpoint in an OpenAPI document. Types are described by example values, e.g.
+// CreateTenantRequest{}, whose JSON encoding defines the schema.
+type OpenAPIOperation struct {
+	Summary string
+	Tags    []string
+	// Query is a struct whose fields tagged with query:"name", as bound by parser.BindQuery, are documented
+	// as query parameters.
+	Query any
+	// Request is the JSON request body.
+	Request any
+	// Responses maps status codes to their JSON response bodies, or to nil for responses without a body.
+	// Defaults to a 200 OK response without a body.
+	Responses map[int]any
+}
+
+// OpenAPI builds an OpenAPI 3 document from the operations added to it, so API docs are generated from the
+// routes and types they describe rather than maintained by hand. Operations are usually added by registering
+// routes through Describe, and the document is served by registering the OpenAPI as a handler:
+//
+//	spec := httputils.NewOpenAPI("Tenants API", "1.0.0")
+//	httputils.Describe(router, spec, httputils.OpenAPIOperation{
+//		Summary:   "Get a tenant",
+//		Responses: map[int]any{http.StatusOK: GetTenantResponse{}},
+//	}).Get("/tenants/{id}", c.GetTenantHandler)
+//	router.Get("/openapi.json", spec.ServeHTTP)
+//
+// Named struct types are added to the document's component schemas and referenced by name.
+type OpenAPI struct {
+	title   string
+	version string
+
+	mu      sync.Mutex
+	paths   map[string]map[string]any
+	schemas map[string]any
+}
+
+// NewOpenAPI creates an empty OpenAPI document for the API with the given title and version.
+func NewOpenAPI(title, version string) *OpenAPI {
+	return &OpenAPI{
+		title:   title,
+		version: version,
+		mu:      sync.Mutex{},
+		paths:   map[string]map[string]any{},
+		schemas: map[string]any{},
+	}
+}
+
+// Add adds the operation served by method on the route pattern, e.g. GET /tenants/{id}. Path parameters in
+// the pattern are documented as required string parameters.
+func (o *OpenAPI) Add(method, pattern string, op OpenAPIOperation) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	operation := map[string]any{"responses": o.responses(op.Responses)}
+
+	if op.Summary != "" {
+		operation["summary"] = op.Summary
+	}
+
+	if len(op.Tags) > 0 {
+		operation["tags"] = op.Tags
+	}
+
+	parameters := pathParameters(pattern)
+	if op.Query != nil {
+		parameters = append(parameters, o.queryParameters(reflect.TypeOf(op.Query))...)
+	}
+
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+
+	if op.Request != nil {
+		operation["requestBody"] = map[string]any{
+			"required": true,
+			"content":  jsonContent(o.schema(reflect.TypeOf(op.Request))),
+		}
+	}
+
+	path := pathParamRX.ReplaceAllString(pattern, "{$1}")
+	if o.paths[path] == nil {
+		o.paths[path] = map[string]any{}
+	}
+
+	o.paths[path][strings.ToLower(method)] = operation
+}
+
+// MarshalJSON encodes the OpenAPI document.
+func (o *OpenAPI) MarshalJSON() ([]byte, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	//nolint: wrapcheck
+	return json.Marshal(map[string]any{
+		"openapi":    openAPIVersion,
+		"info":       map[string]any{"title": o.title, "version": o.version},
+		"paths":      o.paths,
+		"components": map[string]any{"schemas": o.schemas},
+	})
+}
+
+// ServeHTTP responds with the OpenAPI document. The document is written as is, without the key case
+// conversion and data envelope applied by RespondJSON.
+func (o *OpenAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := WriteJSON(w, http.StatusOK, o, nil); err != nil {
+		logError(r, err)
+	}
+}
+
+func (o *OpenAPI) responses(bodies map[int]any) map[string]any {
+	if len(bodies) == 0 {
+		bodies = map[int]any{http.StatusOK: nil}
+	}
+
+	responses := make(map[string]any, len(bodies))
+
+	for status, body := range bodies {
+		response := map[string]any{"description": http.StatusText(status)}
+		if body != nil {
+			response["content"] = jsonContent(o.schema(reflect.TypeOf(body)))
+		}
+
+		responses[strconv.Itoa(status)] = response
+	}
+
+	return responses
+}
+
+func (o *OpenAPI) queryParameters(t reflect.Type) []any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var parameters []any
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := field.Tag.Lookup("query")
+		if !ok {
+			if field.Type.Kind() == reflect.Struct && field.Type != timeType {
+				parameters = append(parameters, o.queryParameters(field.Type)...)
+			}
+
+			continue
+		}
+
+		parameters = append(parameters, map[string]any{
+			"name":   name,
+			"in":     "query",
+			"schema": o.schema(field.Type),
+		})
+	}
+
+	return parameters
+}
+
+// schema returns the JSON schema of values of type t, adding named struct types to the component schemas.
+func (o *OpenAPI) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return map[string]any{}
+	}
+
+	switch t.Kind() { //nolint: exhaustive
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if t.Bits() == 64 { //nolint: mnd
+			return map[string]any{"type": "integer", "format": "int64"}
+		}
+
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+
+		return map[string]any{"type": "array", "items": o.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": o.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return o.structSchema(t)
+		}
+
+		name := schemaName(t)
+		if _, ok := o.schemas[name]; !ok {
+			// Register the name before building the schema so that recursive types refer to themselves.
+			o.schemas[name] = map[string]any{}
+			o.schemas[name] = o.structSchema(t)
+		}
+
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (o *OpenAPI) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+
+	var required []string
+
+	o.addStructProperties(t, properties, &required)
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// addStructProperties adds the properties of the fields of t, including those of embedded structs, as encoded
+// by encoding/json. Fields that are neither pointers nor omitempty are required.
+func (o *OpenAPI) addStructProperties(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				o.addStructProperties(embedded, properties, required)
+
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = o.schema(field.Type)
+
+		if field.Type.Kind() != reflect.Pointer && !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaName returns the component name of a named type, e.g. Page_SearchTenantResponse for
+// Page[SearchTenantResponse].
+func schemaName(t reflect.Type) string {
+	return schemaNameReplace.Replace(schemaPackageRX.ReplaceAllString(t.Name(), ""))
+}
+
+func pathParameters(pattern string) []any {
+	var parameters []any
+
+	for _, match := range pathParamRX.FindAllStringSubmatch(pattern, -1) {
+		parameters = append(parameters, map[string]any{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]any{"type": "string"},
+		})
+	}
+
+	return parameters
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// Describe returns a Router that registers handlers on router and adds an operation described by op to spec
+// for each of them. It composes with With, e.g.
+//
+//	httputils.Describe(httputils.With(router, idempotency), spec, createTenant).Post("/tenants", c.CreateTenantHandler)
+//
+// If spec is nil, router is returned as is, so controllers can describe their routes unconditionally.
+func Describe(router Router, spec *OpenAPI, op OpenAPIOperation) Router { //nolint: ireturn
+	if spec == nil {
+		return router
+	}
+
+	return &describedRouter{router: router, spec: spec, op: op}
+}
+
+type describedRouter struct {
+	router Router
+	spec   *OpenAPI
+	op     OpenAPIOperation
+}
+
+func (d *describedRouter) Connect(pattern string, h http.HandlerFunc) {
+	d.spec.Add(http.MethodConnect, pattern, d.op)
+	d.router.Connect(pattern, h)
+}
+
+func (d *describedRouter) Delete(pattern string, h http.HandlerFunc) {
+	d.spec.Add(http.MethodDelete, pattern, d.op)
+	d.router.Delete(pattern, h)
+}
+
+func (d *describedRouter) Get(pattern string, h http.HandlerFunc) {
+	d.spec.Add(http.MethodGet, pattern, d.op)
+	d.router.Get(pattern, h)
+}
+
+func (d *describedRouter) Head(pattern string, h http.HandlerFunc) {
+	d.spec.Add(http.MethodHead, pattern, d.op)
+	d.router.Head(pattern, h)
+}
+
+func (d *describedRouter) Options(pattern string, h http.HandlerFunc) {
+	d.spec.Add(http.MethodOptions, pattern, d.op)
+	d.router.Options(pattern, h)
+}
+
+func (d *describedRouter) Patch(pattern string, h http.HandlerFunc) {
+	d.spec.Add(http.MethodPatch, pattern, d.op)
+	d.router.Patch(pattern, h)
+}
+
+func (d *describedRouter) Post(pattern string, h http.HandlerFunc) {
+	d.spec.Add(http.MethodPost, pattern, d.op)
+	d.router.Post(pattern, h)
+}
+
+func (d *describedRouter) Put(pattern string, h http.HandlerFunc) {
+	d.spec.Add(http.MethodPut, pattern, d.op)
+	d.router.Put(pattern, h)
+}
+
+func (d *describedRouter) Trace(pattern string, h http.HandlerFunc) {
+	d.spec.Add(http.MethodTrace, pattern, d.op)
+	d.router.Trace(pattern, h)
+}
@@ -0,0 +1,178 @@
+package httputils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+type openAPITestAudit struct {
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type openAPITestUser struct {
+	ID       int64             `json:"id"`
+	Name     string            `json:"name"`
+	Nickname *string           `json:"nickname"`
+	Score    float64           `json:"score,omitempty"`
+	Tags     []string          `json:"tags"`
+	Manager  *openAPITestUser  `json:"manager"`
+	Labels   map[string]string `json:"labels"`
+	Secret   string            `json:"-"`
+	openAPITestAudit
+}
+
+type openAPITestQuery struct {
+	Name   *string  `query:"name"`
+	Plans  []string `query:"plan"`
+	Ignore string
+}
+
+func openAPIDocument(t *testing.T, spec *httputils.OpenAPI) map[string]any {
+	t.Helper()
+
+	body, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("failed to marshal spec: %v", err)
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(body, &document); err != nil {
+		t.Fatalf("failed to unmarshal spec: %v", err)
+	}
+
+	return document
+}
+
+func TestOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	spec := httputils.NewOpenAPI("Users API", "2.0.0")
+	called := false
+
+	router := testutils.NewRouter()
+	httputils.Describe(router, spec, httputils.OpenAPIOperation{
+		Summary:   "Get a user",
+		Tags:      []string{"users"},
+		Query:     openAPITestQuery{},
+		Responses: map[int]any{http.StatusOK: &openAPITestUser{}, http.StatusNotFound: nil},
+	}).Get("/users/{id}", func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	if !called {
+		t.Error("expected the described handler to be registered")
+	}
+
+	document := openAPIDocument(t, spec)
+
+	if document["openapi"] != "3.0.3" {
+		t.Errorf("expected OpenAPI 3.0.3, got %v", document["openapi"])
+	}
+
+	expectedInfo := map[string]any{"title": "Users API", "version": "2.0.0"}
+	if !reflect.DeepEqual(document["info"], expectedInfo) {
+		t.Errorf("expected info %v, got %v", expectedInfo, document["info"])
+	}
+
+	expectedOperation := map[string]any{
+		"summary": "Get a user",
+		"tags":    []any{"users"},
+		"parameters": []any{
+			map[string]any{"name": "id", "in": "path", "required": true, "schema": map[string]any{"type": "string"}},
+			map[string]any{"name": "name", "in": "query", "schema": map[string]any{"type": "string"}},
+			map[string]any{
+				"name": "plan", "in": "query",
+				"schema": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			},
+		},
+		"responses": map[string]any{
+			"200": map[string]any{
+				"description": "OK",
+				"content": map[string]any{"application/json": map[string]any{
+					"schema": map[string]any{"$ref": "#/components/schemas/openAPITestUser"},
+				}},
+			},
+			"404": map[string]any{"description": "Not Found"},
+		},
+	}
+
+	operation := document["paths"].(map[string]any)["/users/{id}"].(map[string]any)["get"]
+	if !reflect.DeepEqual(operation, expectedOperation) {
+		t.Errorf("expected operation %v, got %v", expectedOperation, operation)
+	}
+
+	expectedSchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"id":        map[string]any{"type": "integer", "format": "int64"},
+			"name":      map[string]any{"type": "string"},
+			"nickname":  map[string]any{"type": "string"},
+			"score":     map[string]any{"type": "number"},
+			"tags":      map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"manager":   map[string]any{"$ref": "#/components/schemas/openAPITestUser"},
+			"labels":    map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+			"createdAt": map[string]any{"type": "string", "format": "date-time"},
+		},
+		"required": []any{"id", "name", "tags", "labels", "createdAt"},
+	}
+
+	schema := document["components"].(map[string]any)["schemas"].(map[string]any)["openAPITestUser"]
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Errorf("expected schema %v, got %v", expectedSchema, schema)
+	}
+}
+
+func TestOpenAPI_RequestBody(t *testing.T) {
+	t.Parallel()
+
+	spec := httputils.NewOpenAPI("Users API", "1.0.0")
+	spec.Add(http.MethodPost, "/users/{id:[0-9]+}/notes", httputils.OpenAPIOperation{
+		Request: struct {
+			Name string `json:"name"`
+		}{},
+	})
+
+	operation := openAPIDocument(t, spec)["paths"].(map[string]any)["/users/{id}/notes"].(map[string]any)["post"]
+
+	expectedOperation := map[string]any{
+		"parameters": []any{
+			map[string]any{"name": "id", "in": "path", "required": true, "schema": map[string]any{"type": "string"}},
+		},
+		"requestBody": map[string]any{
+			"required": true,
+			"content": map[string]any{"application/json": map[string]any{
+				"schema": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"name": map[string]any{"type": "string"}},
+					"required":   []any{"name"},
+				},
+			}},
+		},
+		"responses": map[string]any{"200": map[string]any{"description": "OK"}},
+	}
+	if !reflect.DeepEqual(operation, expectedOperation) {
+		t.Errorf("expected operation %v, got %v", expectedOperation, operation)
+	}
+}
+
+func TestDescribe_NilSpec(t *testing.T) {
+	t.Parallel()
+
+	router := testutils.NewRouter()
+
+	if described := httputils.Describe(router, nil, httputils.OpenAPIOperation{}); described != router {
+		t.Error("expected Describe to return the router as is when spec is nil")
+	}
+}
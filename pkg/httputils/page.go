@@ -0,0 +1,38 @@
+package httputils
+
+// Page is the response envelope for a page of a paginated list, so that list endpoints share one shape:
+//
+//	{"items": [...], "page": 2, "pageSize": 25, "totalItems": 60, "totalPages": 3, "hasNext": true, "hasPrev": true}
+type Page[T any] struct {
+	Items      []T  `json:"items"`
+	Page       int  `json:"page"`
+	PageSize   int  `json:"pageSize"`
+	TotalItems int  `json:"totalItems"`
+	TotalPages int  `json:"totalPages"`
+	HasNext    bool `json:"hasNext"`
+	HasPrev    bool `json:"hasPrev"`
+}
+
+// NewPage creates the envelope for items, the rows of the given 1-based page, out of totalItems rows in the
+// full result set, e.g. the count(*) over() column of a query paged with dbutils.QueryBuilder.Page. Nil items
+// are encoded as an empty list.
+func NewPage[T any](items []T, page, pageSize, totalItems int) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = (totalItems + pageSize - 1) / pageSize
+	}
+
+	return Page[T]{
+		Items:      items,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+}
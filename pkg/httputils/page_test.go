@@ -0,0 +1,67 @@
+package httputils_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestNewPage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		page       int
+		pageSize   int
+		totalItems int
+		totalPages int
+		hasNext    bool
+		hasPrev    bool
+	}{
+		{"no items", 1, 25, 0, 0, false, false},
+		{"single partial page", 1, 25, 1, 1, false, false},
+		{"exactly one page", 1, 25, 25, 1, false, false},
+		{"one item over a page", 1, 25, 26, 2, true, false},
+		{"last partial page", 2, 25, 26, 2, false, true},
+		{"middle page", 2, 10, 30, 3, true, true},
+		{"exact multiple", 3, 10, 30, 3, false, true},
+		{"page past the end", 5, 10, 30, 3, false, true},
+		{"zero page size", 1, 0, 30, 0, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			page := httputils.NewPage([]int{1}, tt.page, tt.pageSize, tt.totalItems)
+
+			if page.TotalPages != tt.totalPages {
+				t.Errorf("expected %d total pages, got %d", tt.totalPages, page.TotalPages)
+			}
+
+			if page.HasNext != tt.hasNext || page.HasPrev != tt.hasPrev {
+				t.Errorf("expected hasNext %v and hasPrev %v, got %v and %v",
+					tt.hasNext, tt.hasPrev, page.HasNext, page.HasPrev)
+			}
+
+			if page.Page != tt.page || page.PageSize != tt.pageSize || page.TotalItems != tt.totalItems {
+				t.Errorf("unexpected page %+v", page)
+			}
+		})
+	}
+}
+
+func TestNewPageEncodesNilItemsAsEmptyList(t *testing.T) {
+	t.Parallel()
+
+	body, err := json.Marshal(httputils.NewPage[string](nil, 1, 25, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"items":[],"page":1,"pageSize":25,"totalItems":0,"totalPages":0,"hasNext":false,"hasPrev":false}`
+	if string(body) != expected {
+		t.Errorf("expected %s, got %s", expected, body)
+	}
+}
@@ -0,0 +1,95 @@
+package httputils
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+// ErrorFormat selects the JSON structure used by the error response helpers.
+type ErrorFormat string
+
+const (
+	// ErrorFormatLegacy renders errors as {"errors": ...}.
+	ErrorFormatLegacy ErrorFormat = "legacy"
+	// ErrorFormatProblem renders errors as RFC 7807 application/problem+json documents.
+	ErrorFormatProblem ErrorFormat = "problem"
+)
+
+const (
+	mediaTypeProblemJSON = "application/problem+json"
+	problemTypeBlank     = "about:blank"
+	validationDetail     = "one or more fields failed validation"
+)
+
+type errorFormatContextKey struct{}
+
+// ProblemDetails is an RFC 7807 problem details object. Errors is an extension member holding
+// the field-level failures of a validation error.
+type ProblemDetails struct {
+	Type     string             `json:"type"`
+	Title    string             `json:"title"`
+	Status   int                `json:"status"`
+	Detail   string             `json:"detail,omitempty"`
+	Instance string             `json:"instance,omitempty"`
+	Errors   []validation.Error `json:"errors,omitempty"`
+}
+
+// GetErrorFormat returns the error format selected by the ERROR_FORMAT environment variable
+// (legacy or problem). It defaults to legacy.
+func GetErrorFormat() ErrorFormat {
+	format := ErrorFormat(parser.ParseEnvString("ERROR_FORMAT", string(ErrorFormatLegacy)))
+	if format != ErrorFormatProblem {
+		return ErrorFormatLegacy
+	}
+
+	return format
+}
+
+// ErrorFormatMiddleware sets the JSON error format used by the error response helpers for the request.
+// Clients that explicitly accept application/problem+json always receive problem details.
+func ErrorFormatMiddleware(format ErrorFormat) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), errorFormatContextKey{}, format)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// contextGetErrorFormat returns the error format set by ErrorFormatMiddleware, or ErrorFormatLegacy if it is not set.
+func contextGetErrorFormat(ctx context.Context) ErrorFormat {
+	format, ok := ctx.Value(errorFormatContextKey{}).(ErrorFormat)
+	if !ok {
+		return ErrorFormatLegacy
+	}
+
+	return format
+}
+
+// newProblemDetails builds the problem details for an error response message.
+func newProblemDetails(r *http.Request, status int, message interface{}) ProblemDetails {
+	//nolint: exhaustruct
+	problem := ProblemDetails{
+		Type:     problemTypeBlank,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Instance: r.URL.Path,
+	}
+
+	if validationErrors, ok := message.([]validation.Error); ok {
+		problem.Detail = validationDetail
+		problem.Errors = validationErrors
+	} else {
+		problem.Detail = errorMessages(message)[0]
+	}
+
+	return problem
+}
+
+// writeProblemJSON writes message as an application/problem+json response.
+func writeProblemJSON(w http.ResponseWriter, r *http.Request, status int, message interface{}) error {
+	return writeJSON(w, status, mediaTypeProblemJSON, newProblemDetails(r, status, message), nil)
+}
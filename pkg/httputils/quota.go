@@ -0,0 +1,99 @@
+package httputils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const quotaPeriodFormat = "2006-01"
+
+// QuotaStore tracks per-tenant request usage for a billing period.
+// dbutils.QuotaStore provides a database-backed implementation.
+type QuotaStore interface {
+	// Consume records a single request for the tenant in the given period if the tenant's usage is
+	// below limit. It returns the usage after the call and whether the request was allowed.
+	Consume(ctx context.Context, tenantID int64, period string, limit int64) (used int64, allowed bool, err error)
+}
+
+// QuotaTenantFunc resolves the tenant and plan that a request is billed against.
+// Returning ok = false skips quota enforcement for the request (e.g. unauthenticated requests).
+type QuotaTenantFunc func(r *http.Request) (tenantID int64, plan string, ok bool)
+
+// QuotaConfig configures QuotaMiddleware.
+type QuotaConfig struct {
+	// Store persists request usage.
+	Store QuotaStore
+	// MonthlyLimits maps a plan to the number of requests a tenant on that plan may make per calendar month.
+	// Requests from tenants on plans without a limit are not counted.
+	MonthlyLimits map[string]int64
+	// RateLimits maps a plan to the backend that limits the request rate of each tenant on that plan, e.g.
+	// NewMemoryRateLimitBackend(5, 10) for free tenants. Requests from tenants on plans without a backend are
+	// not rate limited.
+	RateLimits map[string]RateLimitBackend
+	// Tenant resolves the tenant and plan for a request.
+	Tenant QuotaTenantFunc
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// QuotaMiddleware enforces per-tenant request rates and monthly request quotas derived from the tenant's plan.
+// The request rate is checked first, with the tenant ID as the rate limit key, so that rate limited requests
+// do not count against the quota. Unlike the request rate, quotas are persisted and reset at the start of each
+// calendar month (UTC). Every counted response includes X-Quota-Limit, X-Quota-Remaining and X-Quota-Reset
+// headers. Once a tenant's quota is exhausted, requests receive a 429 Too Many Requests response with a
+// Retry-After header indicating when the quota resets.
+func QuotaMiddleware(cfg QuotaConfig) func(next http.Handler) http.Handler {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenantID, plan, ok := cfg.Tenant(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			if backend, ok := cfg.RateLimits[plan]; ok {
+				if !allowRateLimited(w, r, backend, strconv.FormatInt(tenantID, 10)) {
+					return
+				}
+			}
+
+			limit, ok := cfg.MonthlyLimits[plan]
+			if !ok {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			now := cfg.Now().UTC()
+			period := now.Format(quotaPeriodFormat)
+			reset := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+
+			used, allowed, err := cfg.Store.Consume(r.Context(), tenantID, period, limit)
+			if err != nil {
+				ServerErrorResponse(w, r, fmt.Errorf("failed to consume quota: %w", err))
+
+				return
+			}
+
+			w.Header().Set("X-Quota-Limit", strconv.FormatInt(limit, 10))
+			w.Header().Set("X-Quota-Remaining", strconv.FormatInt(max(limit-used, 0), 10))
+			w.Header().Set("X-Quota-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+			if !allowed {
+				QuotaExceededResponse(w, r, reset.Sub(now))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package httputils_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+type memoryQuotaStore struct {
+	usage map[string]int64
+}
+
+func (s *memoryQuotaStore) Consume(_ context.Context, tenantID int64, period string, limit int64) (int64, bool, error) {
+	key := strconv.FormatInt(tenantID, 10) + ":" + period
+	if s.usage[key] >= limit {
+		return s.usage[key], false, nil
+	}
+
+	s.usage[key]++
+
+	return s.usage[key], true, nil
+}
+
+func TestQuotaMiddleware(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.October, 31, 23, 59, 0, 0, time.UTC)
+	store := &memoryQuotaStore{usage: map[string]int64{}}
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := httputils.QuotaMiddleware(httputils.QuotaConfig{
+		Store:         store,
+		MonthlyLimits: map[string]int64{"free": 2},
+		Tenant: func(r *http.Request) (int64, string, bool) {
+			plan := r.Header.Get("X-Plan")
+
+			return 1, plan, plan != ""
+		},
+		Now: func() time.Time { return now },
+	})(next)
+
+	doRequest := func(plan string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/tenants", nil)
+		if plan != "" {
+			req.Header.Set("X-Plan", plan)
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	for i := range 2 {
+		rr := doRequest("free")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rr.Code)
+		}
+
+		if remaining := rr.Header().Get("X-Quota-Remaining"); remaining != strconv.Itoa(1-i) {
+			t.Errorf("expected %d remaining, got %s", 1-i, remaining)
+		}
+	}
+
+	rr := doRequest("free")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", rr.Code)
+	}
+
+	expectedReset := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	if reset := rr.Header().Get("X-Quota-Reset"); reset != strconv.FormatInt(expectedReset.Unix(), 10) {
+		t.Errorf("expected reset %d, got %s", expectedReset.Unix(), reset)
+	}
+
+	if retryAfter := rr.Header().Get("Retry-After"); retryAfter != "60" {
+		t.Errorf("expected Retry-After 60, got %s", retryAfter)
+	}
+
+	if rr := doRequest("paid"); rr.Code != http.StatusOK || rr.Header().Get("X-Quota-Limit") != "" {
+		t.Errorf("expected unlimited plan to pass without quota headers, got %d", rr.Code)
+	}
+
+	if rr := doRequest(""); rr.Code != http.StatusOK {
+		t.Errorf("expected unresolved tenant to pass, got %d", rr.Code)
+	}
+}
+
+func TestQuotaMiddlewarePlanRateLimits(t *testing.T) {
+	t.Parallel()
+
+	store := &memoryQuotaStore{usage: map[string]int64{}}
+	// A rate this low does not refill during the test, so each tenant on the free plan gets a single request.
+	freeRateLimit := httputils.NewMemoryRateLimitBackend(0.001, 1)
+	defer freeRateLimit.Close()
+
+	handler := httputils.QuotaMiddleware(httputils.QuotaConfig{
+		Store:         store,
+		MonthlyLimits: map[string]int64{"free": 10},
+		RateLimits:    map[string]httputils.RateLimitBackend{"free": freeRateLimit},
+		Tenant: func(r *http.Request) (int64, string, bool) {
+			tenantID, err := strconv.ParseInt(r.Header.Get("X-Tenant"), 10, 64)
+
+			return tenantID, r.Header.Get("X-Plan"), err == nil
+		},
+		Now: nil,
+	})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		tenant         string
+		plan           string
+		expectedStatus int
+	}{
+		{"1", "free", http.StatusOK},
+		{"1", "free", http.StatusTooManyRequests},
+		{"2", "free", http.StatusOK},
+		{"3", "paid", http.StatusOK},
+		{"3", "paid", http.StatusOK},
+	}
+
+	for i, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/tenants", nil)
+		req.Header.Set("X-Tenant", tt.tenant)
+		req.Header.Set("X-Plan", tt.plan)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != tt.expectedStatus {
+			t.Errorf("request %d: expected status %d, got %d", i, tt.expectedStatus, rr.Code)
+		}
+
+		if tt.plan == "free" && rr.Header().Get("X-RateLimit-Limit") != "1" {
+			t.Errorf("request %d: expected rate limit headers, got %v", i, rr.Header())
+		}
+	}
+
+	for key, used := range store.usage {
+		if used != 1 {
+			t.Errorf("expected rate limited requests not to count against the quota, got %d for %s", used, key)
+		}
+	}
+}
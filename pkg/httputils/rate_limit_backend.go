@@ -0,0 +1,240 @@
+package httputils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrUnexpectedRedisResponse is returned when the Redis rate limit script returns an unexpected value.
+var ErrUnexpectedRedisResponse = errors.New("unexpected redis response")
+
+// RateLimitResult describes a key's limiter state after a request.
+type RateLimitResult struct {
+	// Allowed reports whether the request may proceed.
+	Allowed bool
+	// Limit is the maximum number of requests that can be made in a burst.
+	Limit int
+	// Remaining is the number of requests that can be made immediately.
+	Remaining int
+	// RetryAfter is the time until another request can be made.
+	RetryAfter time.Duration
+}
+
+// RateLimitBackend tracks request rates per key. MemoryRateLimitBackend keeps state in process and is suitable
+// for a single instance; RedisRateLimitBackend shares state across instances.
+type RateLimitBackend interface {
+	// Allow records a request for key and reports whether it is within the limit.
+	Allow(ctx context.Context, key string) (RateLimitResult, error)
+}
+
+func newRateLimitResult(allowed bool, tokens, ratePerSecond float64, burst int) RateLimitResult {
+	var retryAfter time.Duration
+	if tokens < 1 && ratePerSecond > 0 {
+		retryAfter = time.Duration((1 - tokens) / ratePerSecond * float64(time.Second))
+	}
+
+	return RateLimitResult{
+		Allowed:    allowed,
+		Limit:      burst,
+		Remaining:  max(int(tokens), 0),
+		RetryAfter: retryAfter,
+	}
+}
+
+// MemoryRateLimitBackend is an in-memory token bucket RateLimitBackend. Keys that have not been seen for a
+// few minutes are evicted periodically until Close is called.
+type MemoryRateLimitBackend struct {
+	rate      float64
+	burst     int
+	clock     Clock
+	mu        sync.Mutex
+	clients   map[string]*rateLimitClient
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type rateLimitClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewMemoryRateLimitBackend creates a MemoryRateLimitBackend that allows rate requests per second with bursts
+// of up to burst requests per key.
+func NewMemoryRateLimitBackend(ratePerSecond float64, burst int) *MemoryRateLimitBackend {
+	return NewMemoryRateLimitBackendWithClock(ratePerSecond, burst, RealClock{})
+}
+
+// NewMemoryRateLimitBackendWithClock creates a MemoryRateLimitBackend that reads the current time from
+// clock when refilling buckets and evicting stale keys. Eviction still runs every minute of wall-clock
+// time; tests can call EvictStale directly after advancing a fake clock.
+func NewMemoryRateLimitBackendWithClock(ratePerSecond float64, burst int, clock Clock) *MemoryRateLimitBackend {
+	backend := &MemoryRateLimitBackend{
+		rate:      ratePerSecond,
+		burst:     burst,
+		clock:     clock,
+		mu:        sync.Mutex{},
+		clients:   make(map[string]*rateLimitClient),
+		done:      make(chan struct{}),
+		closeOnce: sync.Once{},
+	}
+
+	go func() {
+		ticker := time.NewTicker(rateLimitCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				backend.EvictStale()
+			case <-backend.done:
+				return
+			}
+		}
+	}()
+
+	return backend
+}
+
+// Close stops evicting stale keys. The backend can still be used, but keys are only evicted when EvictStale
+// is called.
+func (b *MemoryRateLimitBackend) Close() {
+	b.closeOnce.Do(func() {
+		close(b.done)
+	})
+}
+
+// Allow records a request for key and reports whether it is within the limit.
+func (b *MemoryRateLimitBackend) Allow(_ context.Context, key string) (RateLimitResult, error) {
+	now := b.clock.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	client, ok := b.clients[key]
+	if !ok {
+		client = &rateLimitClient{limiter: rate.NewLimiter(rate.Limit(b.rate), b.burst), lastSeen: now}
+		b.clients[key] = client
+	} else {
+		client.lastSeen = now
+	}
+
+	allowed := client.limiter.AllowN(now, 1)
+
+	return newRateLimitResult(allowed, client.limiter.TokensAt(now), b.rate, b.burst), nil
+}
+
+// EvictStale removes keys that have not made a request for a few minutes, resetting their limits.
+func (b *MemoryRateLimitBackend) EvictStale() {
+	now := b.clock.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, client := range b.clients {
+		if now.Sub(client.lastSeen) > rateLimitClientTTL {
+			delete(b.clients, key)
+		}
+	}
+}
+
+// RedisScripter is the subset of a Redis client needed by RedisRateLimitBackend. A go-redis client can be
+// adapted with:
+//
+//	func (a adapter) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+//		return a.client.Eval(ctx, script, keys, args...).Result()
+//	}
+type RedisScripter interface {
+	Eval(ctx context.Context, script string, keys []string, args ...any) (any, error)
+}
+
+// redisTokenBucketScript atomically refills and takes a token from the bucket stored at KEYS[1]. It uses the
+// Redis server clock so that instances with skewed clocks share a consistent view of the bucket.
+// It returns {allowed (0 or 1), remaining tokens as a string}.
+const redisTokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], ttl)
+return {allowed, tostring(tokens)}
+`
+
+const redisRateLimitKeyPrefix = "ratelimit:"
+
+// RedisRateLimitBackend is a token bucket RateLimitBackend that stores state in Redis so that limits are
+// shared by every instance of the application.
+type RedisRateLimitBackend struct {
+	client RedisScripter
+	rate   float64
+	burst  int
+	ttl    time.Duration
+}
+
+// NewRedisRateLimitBackend creates a RedisRateLimitBackend that allows rate requests per second with bursts
+// of up to burst requests per key.
+func NewRedisRateLimitBackend(client RedisScripter, ratePerSecond float64, burst int) *RedisRateLimitBackend {
+	// Buckets expire once they would have refilled completely since they are then equivalent to a new bucket.
+	ttl := rateLimitClientTTL
+	if ratePerSecond > 0 {
+		ttl = time.Duration(math.Ceil(float64(burst)/ratePerSecond*float64(time.Second))) + time.Second
+	}
+
+	return &RedisRateLimitBackend{client: client, rate: ratePerSecond, burst: burst, ttl: ttl}
+}
+
+// Allow records a request for key and reports whether it is within the limit.
+func (b *RedisRateLimitBackend) Allow(ctx context.Context, key string) (RateLimitResult, error) {
+	res, err := b.client.Eval(
+		ctx,
+		redisTokenBucketScript,
+		[]string{redisRateLimitKeyPrefix + key},
+		b.rate, b.burst, b.ttl.Milliseconds(),
+	)
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("failed to evaluate rate limit script: %w", err)
+	}
+
+	values, ok := res.([]any)
+	if !ok || len(values) != 2 { //nolint: mnd
+		return RateLimitResult{}, fmt.Errorf("%w: %v", ErrUnexpectedRedisResponse, res)
+	}
+
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return RateLimitResult{}, fmt.Errorf("%w: %v", ErrUnexpectedRedisResponse, res)
+	}
+
+	tokensStr, ok := values[1].(string)
+	if !ok {
+		return RateLimitResult{}, fmt.Errorf("%w: %v", ErrUnexpectedRedisResponse, res)
+	}
+
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("%w: %w", ErrUnexpectedRedisResponse, err)
+	}
+
+	return newRateLimitResult(allowed == 1, tokens, b.rate, b.burst), nil
+}
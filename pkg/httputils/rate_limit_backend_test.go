@@ -0,0 +1,371 @@
+package httputils_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+// fakeRedis emulates the rate limit script with buckets that never refill.
+type fakeRedis struct {
+	mu      sync.Mutex
+	buckets map[string]float64
+	keys    []string
+}
+
+func (f *fakeRedis) Eval(_ context.Context, _ string, keys []string, args ...any) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.keys = append(f.keys, keys[0])
+
+	burst, _ := args[1].(int)
+
+	tokens, ok := f.buckets[keys[0]]
+	if !ok {
+		tokens = float64(burst)
+	}
+
+	allowed := int64(0)
+	if tokens >= 1 {
+		tokens--
+		allowed = 1
+	}
+
+	f.buckets[keys[0]] = tokens
+
+	return []any{allowed, strconv.FormatFloat(tokens, 'f', -1, 64)}, nil
+}
+
+type brokenRedis struct{}
+
+func (brokenRedis) Eval(_ context.Context, _ string, _ []string, _ ...any) (any, error) {
+	return "OK", nil
+}
+
+func TestRedisRateLimitBackend(t *testing.T) {
+	t.Parallel()
+
+	redis := &fakeRedis{buckets: map[string]float64{}}
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// Two application instances sharing the same Redis share the same limit.
+	instances := []http.Handler{
+		httputils.GetRateLimitMiddlewareWithBackend(
+			httputils.NewRedisRateLimitBackend(redis, 1, 2), httputils.RemoteIPRateLimitKey)(okHandler),
+		httputils.GetRateLimitMiddlewareWithBackend(
+			httputils.NewRedisRateLimitBackend(redis, 1, 2), httputils.RemoteIPRateLimitKey)(okHandler),
+	}
+
+	expected := []struct {
+		status    int
+		remaining string
+	}{
+		{http.StatusOK, "1"},
+		{http.StatusOK, "0"},
+		{http.StatusTooManyRequests, "0"},
+	}
+
+	for i, exp := range expected {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+
+		rr := httptest.NewRecorder()
+		instances[i%len(instances)].ServeHTTP(rr, req)
+
+		if rr.Code != exp.status {
+			t.Errorf("request %d: expected status %d, got %d", i, exp.status, rr.Code)
+		}
+
+		if remaining := rr.Header().Get("X-RateLimit-Remaining"); remaining != exp.remaining {
+			t.Errorf("request %d: expected remaining %s, got %s", i, exp.remaining, remaining)
+		}
+	}
+
+	if redis.keys[0] != "ratelimit:192.0.2.1" {
+		t.Errorf("expected key to be prefixed, got %s", redis.keys[0])
+	}
+}
+
+var errRedisReply = errors.New("redis error reply")
+
+// respRedis is a minimal RESP client that runs EVAL against a real Redis server.
+type respRedis struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newRespRedis(t *testing.T, addr string) *respRedis {
+	t.Helper()
+
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		t.Fatalf("failed to connect to redis at %s: %v", addr, err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return &respRedis{mu: sync.Mutex{}, conn: conn, reader: bufio.NewReader(conn)}
+}
+
+func (c *respRedis) Eval(_ context.Context, script string, keys []string, args ...any) (any, error) {
+	command := []string{"EVAL", script, strconv.Itoa(len(keys))}
+	command = append(command, keys...)
+
+	for _, arg := range args {
+		command = append(command, fmt.Sprint(arg))
+	}
+
+	return c.do(command...)
+}
+
+func (c *respRedis) do(command ...string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	request := fmt.Sprintf("*%d\r\n", len(command))
+	for _, arg := range command {
+		request += fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	if _, err := io.WriteString(c.conn, request); err != nil {
+		return nil, fmt.Errorf("failed to write command: %w", err)
+	}
+
+	return c.readReply()
+}
+
+func (c *respRedis) readReply() (any, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reply: %w", err)
+	}
+
+	line = line[:len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("%w: %s", errRedisReply, line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+
+		bulk := make([]byte, size+2)
+		if _, err := io.ReadFull(c.reader, bulk); err != nil {
+			return nil, fmt.Errorf("failed to read bulk string: %w", err)
+		}
+
+		return string(bulk[:size]), nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]any, size)
+		for i := range values {
+			if values[i], err = c.readReply(); err != nil {
+				return nil, err
+			}
+		}
+
+		return values, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown reply %q", errRedisReply, line)
+	}
+}
+
+// TestRedisRateLimitBackend_Script runs the token bucket script against the Redis server at REDIS_ADDR, e.g.
+// REDIS_ADDR=localhost:6379 go test ./pkg/httputils -run Script.
+func TestRedisRateLimitBackend_Script(t *testing.T) {
+	t.Parallel()
+
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR is not set")
+	}
+
+	redis := newRespRedis(t, addr)
+	prefix := "test-" + strconv.FormatInt(time.Now().UnixNano(), 10) + "-"
+
+	allow := func(backend *httputils.RedisRateLimitBackend, key string) httputils.RateLimitResult {
+		t.Helper()
+
+		result, err := backend.Allow(context.Background(), prefix+key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return result
+	}
+
+	// A bucket that refills too slowly to matter within the test is shared by every backend using the key.
+	first := httputils.NewRedisRateLimitBackend(redis, 0.001, 2)
+	second := httputils.NewRedisRateLimitBackend(redis, 0.001, 2)
+
+	expected := []struct {
+		backend   *httputils.RedisRateLimitBackend
+		allowed   bool
+		remaining int
+	}{
+		{first, true, 1},
+		{second, true, 0},
+		{first, false, 0},
+	}
+
+	for i, exp := range expected {
+		result := allow(exp.backend, "shared")
+		if result.Allowed != exp.allowed || result.Remaining != exp.remaining || result.Limit != 2 {
+			t.Errorf("request %d: expected allowed %v with %d remaining, got %+v",
+				i, exp.allowed, exp.remaining, result)
+		}
+
+		if !result.Allowed && result.RetryAfter <= 0 {
+			t.Errorf("request %d: expected a retry delay, got %+v", i, result)
+		}
+	}
+
+	ttl, err := redis.do("PTTL", "ratelimit:"+prefix+"shared")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ms, ok := ttl.(int64); !ok || ms <= 0 {
+		t.Errorf("expected bucket to expire, got ttl %v", ttl)
+	}
+
+	// A bucket refills at the configured rate using the Redis server clock.
+	refilling := httputils.NewRedisRateLimitBackend(redis, 10, 1)
+
+	if !allow(refilling, "refill").Allowed || allow(refilling, "refill").Allowed {
+		t.Fatal("expected only the first request to be allowed before the bucket refills")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	if !allow(refilling, "refill").Allowed {
+		t.Error("expected request to be allowed after the bucket refills")
+	}
+}
+
+func TestRedisRateLimitBackend_UnexpectedResponse(t *testing.T) {
+	t.Parallel()
+
+	backend := httputils.NewRedisRateLimitBackend(brokenRedis{}, 1, 1)
+
+	_, err := backend.Allow(context.Background(), "key")
+	if err == nil {
+		t.Error("expected error for unexpected redis response")
+	}
+}
+
+func TestMemoryRateLimitBackend(t *testing.T) {
+	t.Parallel()
+
+	backend := httputils.NewMemoryRateLimitBackend(1, 1)
+	defer backend.Close()
+
+	first, err := backend.Allow(context.Background(), "a")
+	if err != nil || !first.Allowed {
+		t.Fatalf("expected first request to be allowed, got %+v, %v", first, err)
+	}
+
+	second, err := backend.Allow(context.Background(), "a")
+	if err != nil || second.Allowed || second.RetryAfter <= 0 {
+		t.Errorf("expected second request to be limited with a retry delay, got %+v, %v", second, err)
+	}
+
+	other, err := backend.Allow(context.Background(), "b")
+	if err != nil || !other.Allowed {
+		t.Errorf("expected other key to be allowed, got %+v, %v", other, err)
+	}
+}
+
+func TestMemoryRateLimitBackend_Refill(t *testing.T) {
+	t.Parallel()
+
+	clock := testutils.NewFakeClock(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	backend := httputils.NewMemoryRateLimitBackendWithClock(1, 1, clock)
+	defer backend.Close()
+
+	expected := []struct {
+		advance time.Duration
+		allowed bool
+	}{
+		{0, true},
+		{500 * time.Millisecond, false},
+		{500 * time.Millisecond, true},
+	}
+
+	for i, exp := range expected {
+		clock.Advance(exp.advance)
+
+		result, err := backend.Allow(context.Background(), "a")
+		if err != nil || result.Allowed != exp.allowed {
+			t.Errorf("request %d: expected allowed %v, got %+v, %v", i, exp.allowed, result, err)
+		}
+	}
+}
+
+func TestMemoryRateLimitBackend_EvictStale(t *testing.T) {
+	t.Parallel()
+
+	clock := testutils.NewFakeClock(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	// A rate of zero never refills, so a key is only allowed again once it has been evicted.
+	backend := httputils.NewMemoryRateLimitBackendWithClock(0, 1, clock)
+	defer backend.Close()
+
+	allow := func(key string) bool {
+		result, err := backend.Allow(context.Background(), key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		return result.Allowed
+	}
+
+	if !allow("stale") || allow("stale") {
+		t.Fatal("expected only the first request for the stale key to be allowed")
+	}
+
+	clock.Advance(2 * time.Minute)
+
+	if !allow("active") || allow("active") {
+		t.Fatal("expected only the first request for the active key to be allowed")
+	}
+
+	clock.Advance(2 * time.Minute)
+	backend.EvictStale()
+
+	if !allow("stale") {
+		t.Error("expected stale key to be evicted and allowed again")
+	}
+
+	if allow("active") {
+		t.Error("expected recently seen key to keep its limit")
+	}
+}
@@ -0,0 +1,137 @@
+package httputils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+)
+
+// ErrInvalidTrustedProxy is returned when a trusted proxy is not a valid IP address or CIDR.
+var ErrInvalidTrustedProxy = errors.New("invalid trusted proxy")
+
+// ParseTrustedProxies parses a list of CIDRs or individual IP addresses into networks for use with RealIP.
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(proxies))
+
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidTrustedProxy, proxy)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTrustedProxy, err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// GetTrustedProxies returns the trusted proxies configured by the comma-separated TRUSTED_PROXIES
+// environment variable.
+func GetTrustedProxies() []*net.IPNet {
+	trustedProxies, err := ParseTrustedProxies(parser.ParseEnvStringSlice("TRUSTED_PROXIES", nil))
+	if err != nil {
+		panic(err)
+	}
+
+	return trustedProxies
+}
+
+func isTrustedProxy(ip net.IP, trustedProxies []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RealIP returns the IP address of the client that made the request. The X-Forwarded-For and X-Real-IP
+// headers are only used when the immediate peer is one of trustedProxies; otherwise they could be spoofed
+// by the client and the peer address from RemoteAddr is returned.
+//
+// X-Forwarded-For is read right to left, skipping trusted proxies, so that a client cannot prepend
+// fake addresses to the header.
+func RealIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
+	}
+
+	if !isTrustedProxy(net.ParseIP(peer), trustedProxies) {
+		return peer
+	}
+
+	if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+		hops := strings.Split(forwardedFor, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+
+			ip := net.ParseIP(hop)
+			if ip == nil {
+				break
+			}
+
+			if i == 0 || !isTrustedProxy(ip, trustedProxies) {
+				return hop
+			}
+		}
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return peer
+}
+
+// RealIPMiddleware sets the request's RemoteAddr to the client IP resolved by RealIP so that logging and
+// rate limiting see the client rather than the load balancer. The peer's port is preserved.
+func RealIPMiddleware(trustedProxies []*net.IPNet) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := RealIP(r, trustedProxies); ip != "" {
+				_, port, err := net.SplitHostPort(r.RemoteAddr)
+				if err != nil {
+					port = "0"
+				}
+
+				r.RemoteAddr = net.JoinHostPort(ip, port)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RealIPRateLimitKey returns a RateLimitKeyFunc that rate limits requests by the client IP resolved by RealIP.
+func RealIPRateLimitKey(trustedProxies []*net.IPNet) RateLimitKeyFunc {
+	return func(r *http.Request) string {
+		return RealIP(r, trustedProxies)
+	}
+}
@@ -0,0 +1,127 @@
+package httputils_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestRealIP(t *testing.T) {
+	t.Parallel()
+
+	trustedProxies, err := httputils.ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.10"})
+	if err != nil {
+		t.Fatalf("failed to parse trusted proxies: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		expected   string
+	}{
+		{
+			name:       "no proxy headers",
+			remoteAddr: "203.0.113.5:1234",
+			expected:   "203.0.113.5",
+		},
+		{
+			name:       "untrusted peer cannot spoof X-Forwarded-For",
+			remoteAddr: "203.0.113.5:1234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			expected:   "203.0.113.5",
+		},
+		{
+			name:       "untrusted peer cannot spoof X-Real-IP",
+			remoteAddr: "203.0.113.5:1234",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.1"},
+			expected:   "203.0.113.5",
+		},
+		{
+			name:       "trusted proxy X-Forwarded-For",
+			remoteAddr: "10.1.2.3:1234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "trusted single ip proxy",
+			remoteAddr: "192.0.2.10:1234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "client-prepended X-Forwarded-For entries are ignored",
+			remoteAddr: "10.1.2.3:1234",
+			headers:    map[string]string{"X-Forwarded-For": "1.1.1.1, 198.51.100.1, 10.4.5.6"},
+			expected:   "198.51.100.1",
+		},
+		{
+			name:       "trusted proxy X-Real-IP",
+			remoteAddr: "10.1.2.3:1234",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.2"},
+			expected:   "198.51.100.2",
+		},
+		{
+			name:       "trusted proxy with invalid headers",
+			remoteAddr: "10.1.2.3:1234",
+			headers:    map[string]string{"X-Forwarded-For": "not-an-ip", "X-Real-IP": "also-not-an-ip"},
+			expected:   "10.1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			if actual := httputils.RealIP(req, trustedProxies); actual != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRealIPMiddleware(t *testing.T) {
+	t.Parallel()
+
+	trustedProxies, err := httputils.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("failed to parse trusted proxies: %v", err)
+	}
+
+	var remoteAddr string
+
+	handler := httputils.RealIPMiddleware(trustedProxies)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		remoteAddr = r.RemoteAddr
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.1.2.3:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if remoteAddr != "198.51.100.1:1234" {
+		t.Errorf("expected RemoteAddr 198.51.100.1:1234, got %s", remoteAddr)
+	}
+}
+
+func TestParseTrustedProxies_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, proxy := range []string{"not-an-ip", "10.0.0.0/33"} {
+		_, err := httputils.ParseTrustedProxies([]string{proxy})
+		if !errors.Is(err, httputils.ErrInvalidTrustedProxy) {
+			t.Errorf("expected ErrInvalidTrustedProxy for %s, got %v", proxy, err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package httputils
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// RecoveryMiddleware recovers from panics in downstream handlers, logs the panic value and stack trace to
+// logger along with the request details, and responds with a generic 500 Internal Server Error so that no
+// internals are leaked to the client. Loggers created by InitializeSlog or wrapped with NewContextHandler
+// also include the request ID. http.ErrAbortHandler is re-raised so that net/http can abort the response.
+func RecoveryMiddleware(logger *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+
+				if p == http.ErrAbortHandler { //nolint: errorlint
+					panic(p)
+				}
+
+				stack := debug.Stack()
+				abort := false
+
+				if hp, ok := p.(*handlerPanic); ok {
+					p, stack, abort = hp.value, hp.stack, hp.abort
+				}
+
+				logger.ErrorContext(r.Context(), "request panicked",
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.String("client_ip", clientHost(r)),
+					slog.Any("panic", p),
+					slog.String("stack", string(stack)),
+				)
+
+				if abort {
+					panic(http.ErrAbortHandler)
+				}
+
+				// The connection has been hijacked for a websocket, so there is no response to write.
+				if r.Header.Get("Connection") == "Upgrade" {
+					return
+				}
+
+				errorResponse(w, r, http.StatusInternalServerError, serverErrorMessage)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
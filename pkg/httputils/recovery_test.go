@@ -0,0 +1,112 @@
+package httputils_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(httputils.NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+	handler := httputils.RequestIDMiddleware(httputils.RecoveryMiddleware(logger)(
+		http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			panic("database password is hunter2")
+		}),
+	))
+
+	req := httptest.NewRequest(http.MethodGet, "/tenants", nil)
+	req.Header.Set(httputils.RequestIDHeader, "req-123")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+
+	if strings.Contains(rr.Body.String(), "hunter2") {
+		t.Errorf("expected a generic response body, got %q", rr.Body.String())
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to unmarshal log record %q: %v", buf.String(), err)
+	}
+
+	if record["msg"] != "request panicked" || record["panic"] != "database password is hunter2" {
+		t.Errorf("expected the panic to be logged, got %v", record)
+	}
+
+	if record["request_id"] != "req-123" {
+		t.Errorf("expected request_id req-123, got %v", record["request_id"])
+	}
+
+	stack, _ := record["stack"].(string)
+	if !strings.Contains(stack, "goroutine") || !strings.Contains(stack, "TestRecoveryMiddleware") {
+		t.Errorf("expected the log record to contain the stack trace, got %q", stack)
+	}
+}
+
+func TestRecoveryMiddlewareReraisesAbortHandler(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil))
+	handler := httputils.RecoveryMiddleware(logger)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler { //nolint: errorlint
+			t.Errorf("expected http.ErrAbortHandler to be re-raised, got %v", p)
+		}
+	}()
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func panicInHandler(http.ResponseWriter, *http.Request) {
+	panic("handler failed")
+}
+
+func TestRecoveryMiddlewareLogsTimeoutHandlerStack(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	handler := httputils.RecoveryMiddleware(logger)(
+		httputils.TimeoutMiddleware(time.Second)(http.HandlerFunc(panicInHandler)),
+	)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to unmarshal log record %q: %v", buf.String(), err)
+	}
+
+	if record["panic"] != "handler failed" {
+		t.Errorf("expected the original panic value to be logged, got %v", record["panic"])
+	}
+
+	stack, _ := record["stack"].(string)
+	if !strings.Contains(stack, "panicInHandler") {
+		t.Errorf("expected the stack trace of the panicking handler, got %q", stack)
+	}
+}
@@ -0,0 +1,114 @@
+package httputils
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// RedactedValue replaces the values of redacted fields in log records.
+const RedactedValue = "***"
+
+// DefaultRedactedFields are the header and JSON field names redacted by NewLogger.
+var DefaultRedactedFields = []string{"Authorization", "Cookie"}
+
+// Redactor masks the values of sensitive header and JSON field names in log records. Names are matched
+// case-insensitively against attribute keys, the keys of http.Header values, and the keys of JSON objects
+// in map[string]any and json.RawMessage values, so that logging a request's headers or body does not leak
+// credentials.
+type Redactor struct {
+	fields map[string]struct{}
+}
+
+// NewRedactor creates a Redactor that masks the given header and JSON field names.
+func NewRedactor(fields ...string) *Redactor {
+	redactor := &Redactor{fields: make(map[string]struct{}, len(fields))}
+	for _, field := range fields {
+		redactor.fields[strings.ToLower(field)] = struct{}{}
+	}
+
+	return redactor
+}
+
+// ReplaceAttr redacts attr if its key is sensitive, or the sensitive fields within its value. It can be used
+// as, or called from, slog.HandlerOptions.ReplaceAttr.
+func (r *Redactor) ReplaceAttr(_ []string, attr slog.Attr) slog.Attr {
+	if r.redacts(attr.Key) {
+		return slog.String(attr.Key, RedactedValue)
+	}
+
+	if attr.Value.Kind() != slog.KindAny {
+		return attr
+	}
+
+	switch value := attr.Value.Any().(type) {
+	case http.Header:
+		attr.Value = slog.AnyValue(r.redactHeader(value))
+	case map[string]any:
+		attr.Value = slog.AnyValue(r.redactJSON(value))
+	case json.RawMessage:
+		attr.Value = slog.AnyValue(r.redactRawJSON(value))
+	}
+
+	return attr
+}
+
+func (r *Redactor) redacts(name string) bool {
+	_, ok := r.fields[strings.ToLower(name)]
+
+	return ok
+}
+
+func (r *Redactor) redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for name := range redacted {
+		if r.redacts(name) {
+			redacted[name] = []string{RedactedValue}
+		}
+	}
+
+	return redacted
+}
+
+// redactJSON returns a copy of value, a decoded JSON value, with sensitive object fields masked at any depth.
+func (r *Redactor) redactJSON(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(value))
+		for key, field := range value {
+			if r.redacts(key) {
+				redacted[key] = RedactedValue
+			} else {
+				redacted[key] = r.redactJSON(field)
+			}
+		}
+
+		return redacted
+	case []any:
+		redacted := make([]any, len(value))
+		for i, item := range value {
+			redacted[i] = r.redactJSON(item)
+		}
+
+		return redacted
+	default:
+		return value
+	}
+}
+
+// redactRawJSON masks sensitive fields in an encoded JSON document. Documents that are not valid JSON are
+// logged as-is since they cannot contain JSON fields.
+func (r *Redactor) redactRawJSON(raw json.RawMessage) json.RawMessage {
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return raw
+	}
+
+	redacted, err := json.Marshal(r.redactJSON(value))
+	if err != nil {
+		return raw
+	}
+
+	return redacted
+}
@@ -0,0 +1,91 @@
+package httputils_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestNewLoggerRedactsDefaultFields(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := httputils.NewLogger(&buf, "info", httputils.LogFormatJSON)
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret-token")
+	header.Set("Cookie", "session=secret")
+	header.Set("Accept", "application/json")
+
+	logger.Info("request received", slog.Any("headers", header), slog.String("authorization", "Bearer secret"))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to unmarshal log record %q: %v", buf.String(), err)
+	}
+
+	expectedHeaders := map[string]any{
+		"Authorization": []any{"***"},
+		"Cookie":        []any{"***"},
+		"Accept":        []any{"application/json"},
+	}
+	if !reflect.DeepEqual(record["headers"], expectedHeaders) {
+		t.Errorf("expected headers %v, got %v", expectedHeaders, record["headers"])
+	}
+
+	if record["authorization"] != httputils.RedactedValue {
+		t.Errorf("expected authorization attribute to be redacted, got %v", record["authorization"])
+	}
+
+	if header.Get("Authorization") != "Bearer secret-token" {
+		t.Error("expected the logged header not to be modified")
+	}
+}
+
+func TestRedactorJSONFields(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	redactor := httputils.NewRedactor("password", "X-Api-Key")
+	logger := httputils.NewLoggerWithRedactor(&buf, "info", httputils.LogFormatJSON, redactor)
+
+	body := json.RawMessage(`{"user":"jane","password":"hunter2","tokens":[{"password":"a"}]}`)
+	logger.Info("request body",
+		slog.Any("body", body),
+		slog.Any("decoded", map[string]any{"user": "jane", "Password": "hunter2"}),
+		slog.Group("request", slog.String("x-api-key", "secret"), slog.String("path", "/login")),
+		slog.Any("invalid", json.RawMessage(`not json`)),
+	)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to unmarshal log record %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]any{
+		"body": map[string]any{
+			"user":     "jane",
+			"password": "***",
+			"tokens":   []any{map[string]any{"password": "***"}},
+		},
+		"decoded": map[string]any{"user": "jane", "Password": "***"},
+		"request": map[string]any{"x-api-key": "***", "path": "/login"},
+	}
+
+	for key, value := range expected {
+		if !reflect.DeepEqual(record[key], value) {
+			t.Errorf("expected %s to be %v, got %v", key, value, record[key])
+		}
+	}
+
+	if _, ok := record["invalid"]; !ok {
+		t.Error("expected invalid JSON to be logged unchanged")
+	}
+}
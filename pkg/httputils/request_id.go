@@ -0,0 +1,69 @@
+package httputils
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestIDHeader is the header used to propagate request IDs between clients and services.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware assigns each request an ID, reusing the client's X-Request-ID header when present,
+// and echoes it in the X-Request-ID response header so that clients and downstream services can correlate
+// requests with logs.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestID := middleware.GetReqID(r.Context()); requestID != "" {
+			w.Header().Set(RequestIDHeader, requestID)
+		}
+
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// RequestIDFromContext returns the request ID assigned by RequestIDMiddleware, falling back to the ID stored
+// under LogRequestIDKey for work that does not pass through the middleware.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	if requestID := middleware.GetReqID(ctx); requestID != "" {
+		return requestID, true
+	}
+
+	requestID, ok := ctx.Value(LogRequestIDKey).(string)
+
+	return requestID, ok && requestID != ""
+}
+
+// RequestIDTransport is an http.RoundTripper that forwards the request ID from the outbound request's context
+// in the X-Request-ID header so that downstream services log the same ID. Requests must be created with the
+// incoming request's context, e.g. with http.NewRequestWithContext(r.Context(), ...). An X-Request-ID header
+// that is already set is left unchanged.
+type RequestIDTransport struct {
+	// Base performs the request. Defaults to http.DefaultTransport.
+	Base http.RoundTripper
+}
+
+// NewRequestIDClient returns an http.Client whose requests forward the request ID via RequestIDTransport.
+func NewRequestIDClient() *http.Client {
+	return &http.Client{Transport: &RequestIDTransport{Base: nil}} //nolint: exhaustruct
+}
+
+// RoundTrip sets the X-Request-ID header and sends the request with the base transport.
+func (t *RequestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	requestID, ok := RequestIDFromContext(req.Context())
+	if !ok || req.Header.Get(RequestIDHeader) != "" {
+		return base.RoundTrip(req) //nolint: wrapcheck
+	}
+
+	// A RoundTripper must not modify the caller's request.
+	req = req.Clone(req.Context())
+	req.Header.Set(RequestIDHeader, requestID)
+
+	return base.RoundTrip(req) //nolint: wrapcheck
+}
@@ -0,0 +1,168 @@
+package httputils_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		incomingID string
+	}{
+		{name: "client supplied id", incomingID: "client-request-id"},
+		{name: "generated id", incomingID: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var contextID string
+
+			handler := httputils.RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contextID = middleware.GetReqID(r.Context())
+
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incomingID != "" {
+				req.Header.Set(httputils.RequestIDHeader, tt.incomingID)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			responseID := rr.Header().Get(httputils.RequestIDHeader)
+			if responseID == "" {
+				t.Fatal("expected X-Request-ID response header")
+			}
+
+			if responseID != contextID {
+				t.Errorf("expected response id %q to match context id %q", responseID, contextID)
+			}
+
+			if tt.incomingID != "" && responseID != tt.incomingID {
+				t.Errorf("expected response id %q to match incoming id %q", responseID, tt.incomingID)
+			}
+		})
+	}
+}
+
+func TestRequestIDClientForwardsRequestID(t *testing.T) {
+	t.Parallel()
+
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get(httputils.RequestIDHeader)))
+	}))
+	defer downstream.Close()
+
+	client := httputils.NewRequestIDClient()
+	handler := httputils.RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, downstream.URL, nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("failed to call downstream service: %v", err)
+		}
+		defer resp.Body.Close()
+
+		_, _ = io.Copy(w, resp.Body)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(httputils.RequestIDHeader, "client-request-id")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Body.String() != "client-request-id" {
+		t.Errorf("expected downstream to receive client-request-id, got %q", rr.Body.String())
+	}
+}
+
+func TestRequestIDTransport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context //nolint: containedctx
+		header     string
+		expectedID string
+	}{
+		{
+			name:       "middleware id",
+			ctx:        context.WithValue(context.Background(), middleware.RequestIDKey, "middleware-id"),
+			expectedID: "middleware-id",
+		},
+		{
+			name:       "log request id",
+			ctx:        context.WithValue(context.Background(), httputils.LogRequestIDKey, "job-id"),
+			expectedID: "job-id",
+		},
+		{
+			name:       "existing header",
+			ctx:        context.WithValue(context.Background(), httputils.LogRequestIDKey, "job-id"),
+			header:     "caller-id",
+			expectedID: "caller-id",
+		},
+		{name: "no id", ctx: context.Background(), expectedID: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var received string
+
+			transport := &httputils.RequestIDTransport{
+				Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					received = req.Header.Get(httputils.RequestIDHeader)
+
+					return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil //nolint: exhaustruct
+				}),
+			}
+
+			req, err := http.NewRequestWithContext(tt.ctx, http.MethodGet, "http://downstream.test", nil)
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+
+			if tt.header != "" {
+				req.Header.Set(httputils.RequestIDHeader, tt.header)
+			}
+
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if received != tt.expectedID {
+				t.Errorf("expected request ID %q, got %q", tt.expectedID, received)
+			}
+
+			if tt.header == "" && req.Header.Get(httputils.RequestIDHeader) != "" {
+				t.Error("expected the caller's request not to be modified")
+			}
+		})
+	}
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
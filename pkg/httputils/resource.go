@@ -0,0 +1,294 @@
+package httputils
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/parser"
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+// Resource describes how a ResourceController maps a model of type T to a database table. The model is used
+// both as the request body of create and update requests and as the response body, so its JSON tags define
+// the API.
+type Resource[T any] struct {
+	// Name is the name of the resource, e.g. tenants. It is the key of the list in list responses.
+	Name string
+	// Table is the name of the database table.
+	Table string
+	// Path is the route prefix of the resource, e.g. /tenants.
+	Path string
+	// Columns returns pointers to every field of model that is read from the database, keyed by column name.
+	// It must include the id and version columns.
+	Columns func(model *T) map[string]any
+	// Values returns the column values written when model is created or updated, keyed by column name. It
+	// must not include the id or version columns.
+	Values func(model *T) map[string]any
+	// Keys returns pointers to the id and version fields of model.
+	Keys func(model *T) (*int64, *int32)
+	// New returns a model with default values that the create request body is decoded into. Defaults to the
+	// zero value of T.
+	New func() T
+	// Validate adds an error to v for every invalid field of model. It is called before create and update.
+	Validate func(v *validation.Validator, model *T)
+	// MapError converts database errors, such as dbutils.ErrUniqueConstraint, into errors that
+	// HandleErrorResponse reports to the client, such as a validation.Error. Errors are passed through if nil.
+	MapError func(err error) error
+	// Filter adds conditions to the list query from the query string, e.g.
+	//
+	//	query.AndWhere("plan = ?", parser.ParseQSString(queryString, "plan", nil))
+	Filter func(queryString url.Values, query *dbutils.QueryBuilder)
+	// SortSafeList is the list of columns the list endpoint may be sorted by, in descending order if prefixed
+	// with - in the sort query parameter. Defaults to id.
+	SortSafeList []string
+}
+
+// ResourceController serves create, read, update, delete and list endpoints for a Resource:
+//
+//	POST   {Path}       creates a model and responds with its id and a Location header.
+//	GET    {Path}       lists models in a Page, paginated and sorted by parser.Filters and filtered by Resource.Filter.
+//	GET    {Path}/{id}  responds with a model.
+//	PATCH  {Path}/{id}  updates the fields present in the request body and responds with the model.
+//	DELETE {Path}/{id}  deletes a model.
+type ResourceController[T any] struct {
+	db       *sql.DB
+	resource Resource[T]
+}
+
+// NewResourceController creates a ResourceController for resource backed by db.
+func NewResourceController[T any](db *sql.DB, resource Resource[T]) *ResourceController[T] {
+	if resource.SortSafeList == nil {
+		resource.SortSafeList = []string{"id"}
+	}
+
+	return &ResourceController[T]{db: db, resource: resource}
+}
+
+// Routes registers the resource's endpoints on router.
+func (c *ResourceController[T]) Routes(router Router) {
+	router.Post(c.resource.Path, c.Create)
+	router.Get(c.resource.Path, c.List)
+	router.Get(c.resource.Path+"/{id}", c.Get)
+	router.Patch(c.resource.Path+"/{id}", c.Update)
+	router.Delete(c.resource.Path+"/{id}", c.Delete)
+}
+
+// Create handles POST {Path}.
+func (c *ResourceController[T]) Create(w http.ResponseWriter, r *http.Request) {
+	model := c.newModel()
+
+	if err := DecodeJSON(w, r, &model); err != nil {
+		HandleErrorResponse(w, r, err)
+
+		return
+	}
+
+	if !c.validate(w, r, &model) {
+		return
+	}
+
+	id, err := dbutils.Insert(r.Context(), c.db, c.resource.Table, c.resource.Values(&model))
+	if err != nil {
+		HandleErrorResponse(w, r, c.mapError(err))
+
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("%s/%d", c.resource.Path, *id))
+
+	RespondJSON(w, r, http.StatusCreated, map[string]any{"id": *id}, headers)
+}
+
+// Get handles GET {Path}/{id}.
+func (c *ResourceController[T]) Get(w http.ResponseWriter, r *http.Request) {
+	model, ok := c.load(w, r)
+	if !ok {
+		return
+	}
+
+	RespondJSON(w, r, http.StatusOK, model, nil)
+}
+
+// Update handles PATCH {Path}/{id}. Fields omitted from the request body keep their current values.
+func (c *ResourceController[T]) Update(w http.ResponseWriter, r *http.Request) {
+	model, ok := c.load(w, r)
+	if !ok {
+		return
+	}
+
+	idField, versionField := c.resource.Keys(model)
+	id, version := *idField, *versionField
+
+	if err := DecodeJSON(w, r, model); err != nil {
+		HandleErrorResponse(w, r, err)
+
+		return
+	}
+
+	// The id and version come from the stored record, never from the request body.
+	*idField, *versionField = id, version
+
+	if !c.validate(w, r, model) {
+		return
+	}
+
+	err := dbutils.UpdateByID(r.Context(), c.db, c.resource.Table, id, version, c.resource.Values(model))
+	if err != nil {
+		HandleErrorResponse(w, r, c.mapError(err))
+
+		return
+	}
+
+	*versionField = version + 1
+
+	RespondJSON(w, r, http.StatusOK, model, nil)
+}
+
+// Delete handles DELETE {Path}/{id}.
+func (c *ResourceController[T]) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := ReadIDParam(r)
+	if err != nil {
+		HandleErrorResponse(w, r, err)
+
+		return
+	}
+
+	err = dbutils.DeleteByID(r.Context(), c.db, c.resource.Table, id)
+	if err != nil {
+		HandleErrorResponse(w, r, c.mapError(err))
+
+		return
+	}
+
+	RespondJSON(w, r, http.StatusOK, map[string]any{"message": "resource successfully deleted"}, nil)
+}
+
+// List handles GET {Path}.
+func (c *ResourceController[T]) List(w http.ResponseWriter, r *http.Request) {
+	queryString := r.URL.Query()
+	v := validation.NewValidator()
+
+	var filters parser.Filters
+	filters.ParseQSFilters(queryString, v, c.resource.SortSafeList)
+
+	if v.HasErrors() {
+		FailedValidationResponse(w, r, v.Errors)
+
+		return
+	}
+
+	var template T
+
+	columns := sortedKeys(c.resource.Columns(&template))
+	query := dbutils.NewQueryBuilder(c.db).
+		Select(append([]string{"count(*) over()"}, columns...)...).
+		From(c.resource.Table)
+
+	if c.resource.Filter != nil {
+		c.resource.Filter(queryString, query)
+	}
+
+	var totalRecords int
+
+	models := make([]T, 0)
+
+	err := query.OrderBySpec(filters.SortSpec).Page(filters.Page, filters.PageSize).Execute(func(rows *sql.Rows) error {
+		var model T
+
+		fields := c.resource.Columns(&model)
+		dest := []any{&totalRecords}
+
+		for _, column := range columns {
+			dest = append(dest, fields[column])
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("failed to scan %s: %w", c.resource.Name, err)
+		}
+
+		models = append(models, model)
+
+		return nil
+	})
+	if err != nil {
+		HandleErrorResponse(w, r, c.mapError(dbutils.WrapDBError(err)))
+
+		return
+	}
+
+	RespondJSON(w, r, http.StatusOK, NewPage(models, filters.Page, filters.PageSize, totalRecords), nil)
+}
+
+// load reads the model identified by the id path parameter, writing an error response if it cannot.
+func (c *ResourceController[T]) load(w http.ResponseWriter, r *http.Request) (*T, bool) {
+	id, err := ReadIDParam(r)
+	if err != nil {
+		HandleErrorResponse(w, r, err)
+
+		return nil, false
+	}
+
+	var model T
+
+	err = dbutils.GetByID(r.Context(), c.db, c.resource.Table, id, c.resource.Columns(&model))
+	if err != nil {
+		HandleErrorResponse(w, r, c.mapError(err))
+
+		return nil, false
+	}
+
+	return &model, true
+}
+
+// validate runs the resource's validation, writing a validation error response if model is invalid.
+func (c *ResourceController[T]) validate(w http.ResponseWriter, r *http.Request, model *T) bool {
+	if c.resource.Validate == nil {
+		return true
+	}
+
+	v := validation.NewValidator()
+	c.resource.Validate(v, model)
+
+	if v.HasErrors() {
+		FailedValidationResponse(w, r, v.Errors)
+
+		return false
+	}
+
+	return true
+}
+
+func (c *ResourceController[T]) newModel() T {
+	if c.resource.New == nil {
+		var model T
+
+		return model
+	}
+
+	return c.resource.New()
+}
+
+func (c *ResourceController[T]) mapError(err error) error {
+	if c.resource.MapError == nil {
+		return err
+	}
+
+	return c.resource.MapError(err)
+}
+
+// sortedKeys returns the keys of m in sorted order so that generated queries are deterministic.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -1,7 +1,15 @@
 package httputils
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gurch101/gowebutils/pkg/parser"
 )
 
 type Router interface {
@@ -15,3 +23,229 @@ type Router interface {
 	Put(pattern string, h http.HandlerFunc)
 	Trace(pattern string, h http.HandlerFunc)
 }
+
+// NewRouter creates a chi router that responds to requests for unknown paths with NotFoundResponse and to
+// requests with unsupported methods with MethodNotAllowedResponse, so that they receive the same error format
+// as other errors rather than chi's plain text defaults.
+func NewRouter() *chi.Mux {
+	router := chi.NewRouter()
+	router.NotFound(NotFoundResponse)
+	router.MethodNotAllowed(MethodNotAllowedResponse)
+
+	return router
+}
+
+// Chain composes middlewares into a single middleware. Middlewares are applied outer-to-inner in the order
+// they are declared, so Chain(a, b)(h) passes a request through a, then b, then h.
+func Chain(middlewares ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			next = middlewares[i](next)
+		}
+
+		return next
+	}
+}
+
+// SkipPaths returns a middleware that applies middleware to every request except those for the given paths,
+// so that operational endpoints such as /healthz can bypass it. A path ending in /* also matches every path
+// below it, e.g. /static/* matches /static/app.css.
+func SkipPaths(paths []string, middleware func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	if len(paths) == 0 {
+		return middleware
+	}
+
+	return func(next http.Handler) http.Handler {
+		wrapped := middleware(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if matchesAnyPath(paths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
+
+func matchesAnyPath(paths []string, path string) bool {
+	for _, candidate := range paths {
+		if prefix, ok := strings.CutSuffix(candidate, "*"); ok && strings.HasPrefix(path, prefix) {
+			return true
+		}
+
+		if candidate == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// With returns a Router that registers handlers on router wrapped in middlewares, applied as by Chain. It
+// lets controllers apply route-level middleware through the Router interface, e.g.
+//
+//	httputils.With(router, idempotency).Post("/tenants", c.CreateTenantHandler)
+func With(router Router, middlewares ...func(http.Handler) http.Handler) Router { //nolint: ireturn
+	return &middlewareRouter{router: router, middleware: Chain(middlewares...)}
+}
+
+type middlewareRouter struct {
+	router     Router
+	middleware func(http.Handler) http.Handler
+}
+
+func (m *middlewareRouter) wrap(h http.HandlerFunc) http.HandlerFunc {
+	return m.middleware(h).ServeHTTP
+}
+
+func (m *middlewareRouter) Connect(pattern string, h http.HandlerFunc) {
+	m.router.Connect(pattern, m.wrap(h))
+}
+
+func (m *middlewareRouter) Delete(pattern string, h http.HandlerFunc) {
+	m.router.Delete(pattern, m.wrap(h))
+}
+
+func (m *middlewareRouter) Get(pattern string, h http.HandlerFunc) {
+	m.router.Get(pattern, m.wrap(h))
+}
+
+func (m *middlewareRouter) Head(pattern string, h http.HandlerFunc) {
+	m.router.Head(pattern, m.wrap(h))
+}
+
+func (m *middlewareRouter) Options(pattern string, h http.HandlerFunc) {
+	m.router.Options(pattern, m.wrap(h))
+}
+
+func (m *middlewareRouter) Patch(pattern string, h http.HandlerFunc) {
+	m.router.Patch(pattern, m.wrap(h))
+}
+
+func (m *middlewareRouter) Post(pattern string, h http.HandlerFunc) {
+	m.router.Post(pattern, m.wrap(h))
+}
+
+func (m *middlewareRouter) Put(pattern string, h http.HandlerFunc) {
+	m.router.Put(pattern, m.wrap(h))
+}
+
+func (m *middlewareRouter) Trace(pattern string, h http.HandlerFunc) {
+	m.router.Trace(pattern, m.wrap(h))
+}
+
+// RoutePattern returns the pattern of the route that matched r, e.g. /tenants/{id}, or an empty string if no
+// route matched. Routes matched by chi are checked first, then those matched by an http.ServeMux, whose pattern
+// has its method and host stripped. Unlike the request path, the pattern has a bounded number of values, so
+// it is suitable for grouping logs and metrics.
+//
+// An http.ServeMux only sets the pattern on the request it is passed, which middleware that calls
+// r.WithContext never sees. Middleware that needs the pattern after the request is served should wrap its
+// next handler with RecordRoutePattern.
+func RoutePattern(r *http.Request) string {
+	if routeContext := chi.RouteContext(r.Context()); routeContext != nil {
+		if pattern := routeContext.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+
+	if pattern := serveMuxPattern(r); pattern != "" {
+		return pattern
+	}
+
+	if holder, ok := r.Context().Value(routePatternContextKey{}).(*routePatternHolder); ok {
+		holder.mu.Lock()
+		defer holder.mu.Unlock()
+
+		return holder.pattern
+	}
+
+	return ""
+}
+
+type routePatternContextKey struct{}
+
+// routePatternHolder is shared by every copy of a request made with r.WithContext, so that the pattern an
+// http.ServeMux sets on its copy can be read from the others.
+type routePatternHolder struct {
+	mu      sync.Mutex
+	pattern string
+}
+
+// serveMuxPattern returns the pattern set on r by an http.ServeMux with its method and host stripped.
+func serveMuxPattern(r *http.Request) string {
+	if i := strings.Index(r.Pattern, "/"); i >= 0 {
+		return r.Pattern[i:]
+	}
+
+	return ""
+}
+
+// RecordRoutePattern returns a handler that serves requests with next and then records the pattern an
+// http.ServeMux set on the request, so that RoutePattern returns it for every copy of the request, including
+// those held by outer middleware. It only needs to be used directly around an http.ServeMux that is behind
+// middleware that copies the request; the access log, metrics and tracing middleware apply it to their next
+// handler already.
+func RecordRoutePattern(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, holder := withRoutePatternHolder(r)
+
+		defer func() {
+			if pattern := serveMuxPattern(r); pattern != "" {
+				holder.mu.Lock()
+				holder.pattern = pattern
+				holder.mu.Unlock()
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withRoutePatternHolder returns r with a routePatternHolder in its context, reusing an existing one.
+func withRoutePatternHolder(r *http.Request) (*http.Request, *routePatternHolder) {
+	if holder, ok := r.Context().Value(routePatternContextKey{}).(*routePatternHolder); ok {
+		return r, holder
+	}
+
+	holder := &routePatternHolder{mu: sync.Mutex{}, pattern: ""}
+
+	return r.WithContext(context.WithValue(r.Context(), routePatternContextKey{}, holder)), holder
+}
+
+// ReadIDParam returns the "id" path parameter as a positive integer. It returns an error wrapping
+// parser.ErrInvalidPathParam if the id is missing, not a number or not positive, which HandleErrorResponse
+// reports as a 400 Bad Request. A well-formed id that matches no record should be reported as a 404 by the
+// caller, e.g. by passing dbutils.ErrRecordNotFound to HandleErrorResponse.
+func ReadIDParam(r *http.Request) (int64, error) {
+	id, err := ReadInt64PathParam(r, "id")
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%w: id must be a positive integer", parser.ErrInvalidPathParam)
+	}
+
+	return id, nil
+}
+
+// ReadInt64PathParam returns the path parameter called name, e.g. "tenantID" in /tenants/{tenantID}/users, as a
+// non-negative integer. It returns an error wrapping parser.ErrInvalidPathParam that names the parameter and is
+// safe to return to the client if the parameter is missing, not a number or negative.
+func ReadInt64PathParam(r *http.Request, name string) (int64, error) {
+	value := r.PathValue(name)
+	if value == "" {
+		return 0, fmt.Errorf("%w: %s is required", parser.ErrInvalidPathParam, name)
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s must be an integer", parser.ErrInvalidPathParam, name)
+	}
+
+	if n < 0 {
+		return 0, fmt.Errorf("%w: %s must not be negative", parser.ErrInvalidPathParam, name)
+	}
+
+	return n, nil
+}
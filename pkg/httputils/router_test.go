@@ -0,0 +1,241 @@
+package httputils_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/parser"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func TestReadIDParam(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		id            string
+		expectedID    int64
+		expectedError error
+	}{
+		{name: "valid", id: "42", expectedID: 42, expectedError: nil},
+		{name: "not a number", id: "abc", expectedID: 0, expectedError: parser.ErrInvalidPathParam},
+		{name: "zero", id: "0", expectedID: 0, expectedError: parser.ErrInvalidPathParam},
+		{name: "negative", id: "-1", expectedID: 0, expectedError: parser.ErrInvalidPathParam},
+		{name: "overflow", id: "9223372036854775808", expectedID: 0, expectedError: parser.ErrInvalidPathParam},
+		{name: "missing", id: "", expectedID: 0, expectedError: parser.ErrInvalidPathParam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/tenants/"+tt.id, nil)
+			req.SetPathValue("id", tt.id)
+
+			id, err := httputils.ReadIDParam(req)
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+
+			if id != tt.expectedID {
+				t.Errorf("expected id %d, got %d", tt.expectedID, id)
+			}
+		})
+	}
+}
+
+func TestReadInt64PathParam(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		value         string
+		expectedValue int64
+		expectedError string
+	}{
+		{name: "valid", value: "42", expectedValue: 42, expectedError: ""},
+		{name: "zero", value: "0", expectedValue: 0, expectedError: ""},
+		{
+			name: "not a number", value: "abc", expectedValue: 0,
+			expectedError: "invalid path param: tenantID must be an integer",
+		},
+		{name: "negative", value: "-1", expectedValue: 0, expectedError: "invalid path param: tenantID must not be negative"},
+		{name: "missing", value: "", expectedValue: 0, expectedError: "invalid path param: tenantID is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/tenants/"+tt.value+"/users", nil)
+			req.SetPathValue("tenantID", tt.value)
+
+			value, err := httputils.ReadInt64PathParam(req, "tenantID")
+			if tt.expectedError == "" && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			invalid := errors.Is(err, parser.ErrInvalidPathParam) && err.Error() == tt.expectedError
+			if tt.expectedError != "" && !invalid {
+				t.Errorf("expected error %q, got %v", tt.expectedError, err)
+			}
+
+			if value != tt.expectedValue {
+				t.Errorf("expected %d, got %d", tt.expectedValue, value)
+			}
+		})
+	}
+}
+
+func recordingMiddleware(calls *[]string, name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*calls = append(*calls, name+" before")
+			next.ServeHTTP(w, r)
+			*calls = append(*calls, name+" after")
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	handler := httputils.Chain(
+		recordingMiddleware(&calls, "outer"),
+		recordingMiddleware(&calls, "middle"),
+		recordingMiddleware(&calls, "inner"),
+	)(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		calls = append(calls, "handler")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	expected := []string{
+		"outer before", "middle before", "inner before", "handler", "inner after", "middle after", "outer after",
+	}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestChainWithoutMiddleware(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+	httputils.Chain()(http.NotFoundHandler()).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected the handler to be called, got %d", rr.Code)
+	}
+}
+
+func TestWith(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	router := testutils.NewRouter()
+	handler := func(_ http.ResponseWriter, _ *http.Request) {
+		calls = append(calls, "handler")
+	}
+
+	httputils.With(router, recordingMiddleware(&calls, "outer"), recordingMiddleware(&calls, "inner")).
+		Post("/tenants", handler)
+	router.Get("/tenants", handler)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/tenants", nil))
+
+	expected := []string{"outer before", "inner before", "handler", "inner after", "outer after"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected %v, got %v", expected, calls)
+	}
+
+	calls = nil
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tenants", nil))
+
+	if !reflect.DeepEqual(calls, []string{"handler"}) {
+		t.Errorf("expected routes registered on the router directly not to use the middleware, got %v", calls)
+	}
+}
+
+func TestNewRouterErrorResponses(t *testing.T) {
+	t.Parallel()
+
+	router := httputils.NewRouter()
+	router.Get("/tenants", func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	testutils.AssertStatus(t, rr, http.StatusNotFound)
+
+	if contentType := rr.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("expected JSON content type, got %q", contentType)
+	}
+
+	var resp map[string]any
+
+	testutils.AssertJSONBody(t, rr, &resp)
+
+	if resp["errors"] != "the requested resource could not be found" {
+		t.Errorf("expected not found error, got %v", resp)
+	}
+}
+
+func TestMethodNotAllowedResponse(t *testing.T) {
+	t.Parallel()
+
+	rr := httptest.NewRecorder()
+	httputils.MethodNotAllowedResponse(rr, httptest.NewRequest(http.MethodDelete, "/tenants", nil))
+
+	testutils.AssertStatus(t, rr, http.StatusMethodNotAllowed)
+
+	var resp map[string]any
+
+	testutils.AssertJSONBody(t, rr, &resp)
+
+	if resp["errors"] != "the DELETE method is not supported for this resource" {
+		t.Errorf("expected method not allowed error, got %v", resp)
+	}
+}
+
+func TestSkipPaths(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	handler := httputils.SkipPaths([]string{"/healthz", "/static/*"}, recordingMiddleware(&calls, "middleware"))(
+		http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			calls = append(calls, "handler")
+		}),
+	)
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{path: "/healthz", expected: []string{"handler"}},
+		{path: "/static/app.css", expected: []string{"handler"}},
+		{path: "/static/", expected: []string{"handler"}},
+		{path: "/healthz/details", expected: []string{"middleware before", "handler", "middleware after"}},
+		{path: "/static", expected: []string{"middleware before", "handler", "middleware after"}},
+		{path: "/tenants", expected: []string{"middleware before", "handler", "middleware after"}},
+	}
+
+	for _, tt := range tests {
+		calls = nil
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if !reflect.DeepEqual(calls, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.path, tt.expected, calls)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package httputils_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func TestSecureHeadersMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		config          httputils.SecureHeadersConfig
+		tls             bool
+		expectedHeaders map[string]string
+	}{
+		{
+			name:   "defaults over plaintext",
+			config: httputils.SecureHeadersConfig{},
+			expectedHeaders: map[string]string{
+				"X-Content-Type-Options":    "nosniff",
+				"X-Frame-Options":           "DENY",
+				"Referrer-Policy":           "strict-origin-when-cross-origin",
+				"Content-Security-Policy":   "default-src 'self'; frame-ancestors 'none'",
+				"Strict-Transport-Security": "",
+			},
+		},
+		{
+			name:   "defaults over tls",
+			config: httputils.SecureHeadersConfig{},
+			tls:    true,
+			expectedHeaders: map[string]string{
+				"Strict-Transport-Security": "max-age=31536000",
+			},
+		},
+		{
+			name: "custom config",
+			config: httputils.SecureHeadersConfig{
+				ContentSecurityPolicy: "default-src 'self' https://cdn.example.com",
+				FrameOptions:          "SAMEORIGIN",
+				ReferrerPolicy:        "no-referrer",
+				HSTSMaxAge:            time.Hour,
+				HSTSIncludeSubdomains: true,
+			},
+			tls: true,
+			expectedHeaders: map[string]string{
+				"X-Frame-Options":           "SAMEORIGIN",
+				"Referrer-Policy":           "no-referrer",
+				"Content-Security-Policy":   "default-src 'self' https://cdn.example.com",
+				"Strict-Transport-Security": "max-age=3600; includeSubDomains",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := httputils.SecureHeadersMiddleware(tt.config)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			for key, expected := range tt.expectedHeaders {
+				if actual := rr.Header().Get(key); actual != expected {
+					t.Errorf("expected header %s to be %q, got %q", key, expected, actual)
+				}
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // sets environment variables
+func TestGetSecureHeadersConfig(t *testing.T) {
+	if csp := httputils.GetSecureHeadersConfig().ContentSecurityPolicy; csp != "default-src 'self'; frame-ancestors 'none'" {
+		t.Errorf("expected default policy, got %q", csp)
+	}
+
+	t.Setenv("CONTENT_SECURITY_POLICY", "default-src 'self' 'unsafe-inline'")
+
+	if csp := httputils.GetSecureHeadersConfig().ContentSecurityPolicy; csp != "default-src 'self' 'unsafe-inline'" {
+		t.Errorf("expected policy from env, got %q", csp)
+	}
+}
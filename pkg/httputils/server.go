@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"github.com/gurch101/gowebutils/pkg/parser"
+	"github.com/gurch101/gowebutils/pkg/threads"
 )
 
 const defaultPort = 8080
@@ -23,12 +25,74 @@ const writeTimeout = 10 * time.Second
 
 const shutdownTimeout = 5 * time.Second
 
-func ServeHTTP(handler http.Handler, logger *slog.Logger) error {
+// ServerConfig configures the server started by Serve.
+type ServerConfig struct {
+	// Addr is the TCP address to listen on, e.g. ":8080". It is ignored if Listener is set.
+	Addr string
+	// Listener is an optional listener to accept connections on instead of listening on Addr.
+	Listener net.Listener
+	// TLSCertFile and TLSKeyFile are the certificate and key files used to serve TLS. The server
+	// serves plain HTTP if either is empty.
+	TLSCertFile string
+	TLSKeyFile  string
+	// DrainTimeout is how long in-flight requests are given to complete once shutdown begins.
+	// Defaults to 5 seconds.
+	DrainTimeout time.Duration
+	// Logger is used for server lifecycle and error logs. Defaults to slog.Default().
+	Logger *slog.Logger
+	// Context optionally triggers a graceful shutdown when it is done, in addition to SIGINT and SIGTERM.
+	Context context.Context //nolint: containedctx
+	// Workers is an optional pool of background jobs that is drained once in-flight requests complete, so that
+	// jobs they submitted, such as sending emails, are not dropped. It shares the DrainTimeout deadline.
+	Workers *threads.Pool
+}
+
+// ServeHTTP starts a TLS server on SERVER_PORT using the certificate in ./tls and shuts it down
+// gracefully on SIGINT or SIGTERM, giving in-flight requests and then workers' jobs SHUTDOWN_TIMEOUT (e.g. 10s)
+// to complete. workers may be nil if there are no background jobs to drain.
+func ServeHTTP(handler http.Handler, logger *slog.Logger, workers *threads.Pool) error {
 	port, err := parser.ParseEnvInt("SERVER_PORT", defaultPort)
 	if err != nil {
 		return fmt.Errorf("invalid server port: %w", err)
 	}
 
+	drainTimeout, err := parser.ParseEnvDuration("SHUTDOWN_TIMEOUT", shutdownTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid shutdown timeout: %w", err)
+	}
+
+	//nolint: exhaustruct
+	return Serve(handler, ServerConfig{
+		Addr:         fmt.Sprintf(":%d", port),
+		TLSCertFile:  "./tls/cert.pem",
+		TLSKeyFile:   "./tls/key.pem",
+		DrainTimeout: drainTimeout,
+		Logger:       logger,
+		Workers:      workers,
+	})
+}
+
+// Serve starts a server for handler and blocks until it stops. When the process receives SIGINT
+// or SIGTERM, or cfg.Context is done, the server stops accepting new connections and waits up to
+// cfg.DrainTimeout for in-flight requests and then cfg.Workers' jobs to complete. If the drain deadline
+// elapses, the remaining connections are closed, running jobs' contexts are cancelled and an error wrapping
+// context.DeadlineExceeded is returned.
+func Serve(handler http.Handler, cfg ServerConfig) error {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	drainTimeout := cfg.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = shutdownTimeout
+	}
+
+	ctx := cfg.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	//nolint: exhaustruct
 	tlsConfig := &tls.Config{
 		MinVersion:       tls.VersionTLS13,
@@ -37,7 +101,7 @@ func ServeHTTP(handler http.Handler, logger *slog.Logger) error {
 
 	//nolint: exhaustruct
 	server := &http.Server{
-		Addr:              fmt.Sprintf(":%d", port),
+		Addr:              cfg.Addr,
 		Handler:           handler,
 		TLSConfig:         tlsConfig,
 		IdleTimeout:       time.Minute,
@@ -46,39 +110,75 @@ func ServeHTTP(handler http.Handler, logger *slog.Logger) error {
 		ErrorLog:          NewSlogErrorWriter(logger),
 	}
 
-	shutdownError := make(chan error)
+	listener := cfg.Listener
+	if listener == nil {
+		var err error
 
-	go func() {
-		// Create a quit channel which carries os.Signal values.
-		quit := make(chan os.Signal, 1)
-		// Use signal.Notify() to listen for incoming SIGINT and SIGTERM signals and relay them to the quit channel.
-		// Any other signals will not be caught by signal.Notify() and will retain their default behavior.
-		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-		// Read the signal from the quit channel. This code will block until a signal is received.
-		s := <-quit
+		listener, err = net.Listen("tcp", cfg.Addr)
+		if err != nil {
+			return fmt.Errorf("server error %w", err)
+		}
+	}
 
-		slog.Info("shutting down server", "signal", s.String())
+	// Use signal.Notify() to listen for incoming SIGINT and SIGTERM signals and relay them to the quit channel.
+	// Any other signals will not be caught by signal.Notify() and will retain their default behavior.
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
-		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
-		defer cancel()
+	defer signal.Stop(quit)
 
-		shutdownError <- server.Shutdown(ctx)
-	}()
+	serveError := make(chan error, 1)
 
-	slog.Info("server started", "port", port)
+	go func() {
+		if cfg.TLSCertFile != "" && cfg.TLSKeyFile != "" {
+			serveError <- server.ServeTLS(listener, cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			serveError <- server.Serve(listener)
+		}
+	}()
 
-	err = server.ListenAndServeTLS("./tls/cert.pem", "./tls/key.pem")
+	logger.Info("server started", "addr", listener.Addr().String())
 
-	if !errors.Is(err, http.ErrServerClosed) {
+	select {
+	case err := <-serveError:
 		return fmt.Errorf("server error %w", err)
+	case s := <-quit:
+		logger.Info("shutting down server", "signal", s.String())
+	case <-ctx.Done():
+		logger.Info("shutting down server", "reason", context.Cause(ctx).Error())
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	err := server.Shutdown(shutdownCtx)
+	if err != nil {
+		// The drain deadline elapsed, so forcibly close any connections that are still active.
+		closeErr := server.Close()
+
+		return fmt.Errorf("server shutdown error %w", errors.Join(err, closeErr, shutdownWorkers(shutdownCtx, cfg)))
 	}
 
-	err = <-shutdownError
+	err = shutdownWorkers(shutdownCtx, cfg)
 	if err != nil {
 		return fmt.Errorf("server shutdown error %w", err)
 	}
 
-	slog.Info("server stopped", "port", port)
+	err = <-serveError
+	if !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server error %w", err)
+	}
+
+	logger.Info("server stopped", "addr", listener.Addr().String())
 
 	return nil
 }
+
+// shutdownWorkers drains cfg.Workers, if set, until ctx is done.
+func shutdownWorkers(ctx context.Context, cfg ServerConfig) error {
+	if cfg.Workers == nil {
+		return nil
+	}
+
+	return cfg.Workers.Shutdown(ctx) //nolint: wrapcheck
+}
@@ -0,0 +1,195 @@
+package httputils_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/threads"
+)
+
+func startTestServer(
+	t *testing.T, handler http.Handler, drainTimeout time.Duration, workers *threads.Pool,
+) (string, context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		//nolint: exhaustruct
+		done <- httputils.Serve(handler, httputils.ServerConfig{
+			Listener:     listener,
+			DrainTimeout: drainTimeout,
+			Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+			Context:      ctx,
+			Workers:      workers,
+		})
+	}()
+
+	return "http://" + listener.Addr().String(), cancel, done
+}
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	url, shutdown, done := startTestServer(t, handler, 2*time.Second, nil)
+
+	statusCh := make(chan int, 1)
+
+	go func() {
+		resp, err := http.Get(url) //nolint: noctx
+		if err != nil {
+			statusCh <- 0
+
+			return
+		}
+		defer resp.Body.Close()
+
+		statusCh <- resp.StatusCode
+	}()
+
+	<-started
+	shutdown()
+
+	if status := <-statusCh; status != http.StatusOK {
+		t.Errorf("expected in-flight request to complete with status %d, got %d", http.StatusOK, status)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected graceful shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+}
+
+func TestServeReturnsWhenDrainTimeoutElapses(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	defer close(release)
+
+	url, shutdown, done := startTestServer(t, handler, 50*time.Millisecond, nil)
+
+	go func() {
+		resp, err := http.Get(url) //nolint: noctx
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	<-started
+
+	start := time.Now()
+
+	shutdown()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected drain deadline error, got %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected server to stop shortly after the drain timeout, took %v", elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop after the drain timeout")
+	}
+}
+
+func TestServeDrainsWorkers(t *testing.T) {
+	t.Parallel()
+
+	workers := threads.NewPool(1, 1)
+	started := make(chan struct{})
+	finished := make(chan struct{})
+
+	_ = workers.Submit(func(context.Context) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		close(finished)
+	})
+
+	_, shutdown, done := startTestServer(t, http.NotFoundHandler(), 2*time.Second, workers)
+
+	<-started
+	shutdown()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected graceful shutdown, got %v", err)
+		}
+
+		select {
+		case <-finished:
+		default:
+			t.Error("expected server to wait for the background job to finish")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+}
+
+func TestServeCancelsWorkersWhenDrainTimeoutElapses(t *testing.T) {
+	t.Parallel()
+
+	workers := threads.NewPool(1, 1)
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+
+	_ = workers.Submit(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+	})
+
+	_, shutdown, done := startTestServer(t, http.NotFoundHandler(), 50*time.Millisecond, workers)
+
+	<-started
+	shutdown()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected drain deadline error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop after the drain timeout")
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("expected the background job's context to be cancelled")
+	}
+}
@@ -0,0 +1,94 @@
+package httputils
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MiddlewareConfig configures the optional parts of StandardMiddleware.
+type MiddlewareConfig struct {
+	// Logger receives access logs and recovered panics. slog.Default() is used if it is nil.
+	Logger *slog.Logger
+	// AccessLogWriter receives access logs in the common and combined formats. os.Stdout is used if it is nil.
+	AccessLogWriter io.Writer
+	// AllowedOrigins enables CORS for the listed origins. CORS is disabled if it is empty.
+	AllowedOrigins []string
+	// MetricsRegisterer registers the Prometheus request metrics. Metrics are disabled if it is nil.
+	MetricsRegisterer prometheus.Registerer
+	// TracerProvider creates the request spans started by TracingMiddleware. Tracing is disabled if it is nil.
+	TracerProvider trace.TracerProvider
+	// SecureHeaders configures the security headers set on every response. GetSecureHeadersConfig is used if
+	// it is nil.
+	SecureHeaders *SecureHeadersConfig
+}
+
+// StandardMiddleware returns the middleware stack applied to every request by the starter server, in the
+// order it should be applied: client IP and request ID resolution, tracing, CORS, error formatting, JSON key
+// casing and data envelopes, secure headers, metrics, access logging, rate limiting, panic recovery,
+// compression, the request timeout and, if DEBUG_HTTP is set, request and response body logging. Rate limits,
+// timeouts, log formats and the JSON response shape are configured via env; an error is returned if the rate
+// limit config is invalid. The stack can be applied to a single handler with stack.Handler(handler).
+func StandardMiddleware(cfg MiddlewareConfig) (chi.Middlewares, error) {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	accessLogWriter := cfg.AccessLogWriter
+	if accessLogWriter == nil {
+		accessLogWriter = os.Stdout
+	}
+
+	rateLimit, err := GetRateLimitMiddleware(RemoteIPRateLimitKey)
+	if err != nil {
+		return nil, err
+	}
+
+	secureHeaders := GetSecureHeadersConfig()
+	if cfg.SecureHeaders != nil {
+		secureHeaders = *cfg.SecureHeaders
+	}
+
+	stack := chi.Middlewares{
+		RealIPMiddleware(GetTrustedProxies()),
+		RequestIDMiddleware,
+	}
+
+	if cfg.TracerProvider != nil {
+		stack = append(stack, TracingMiddleware(cfg.TracerProvider))
+	}
+
+	if len(cfg.AllowedOrigins) > 0 {
+		stack = append(stack, GetCORSMiddleware(cfg.AllowedOrigins))
+	}
+
+	stack = append(stack,
+		ErrorFormatMiddleware(GetErrorFormat()),
+		KeyCaseMiddleware(GetKeyCase()),
+		DataEnvelopeMiddleware(GetDataEnvelope()),
+		SecureHeadersMiddleware(secureHeaders),
+	)
+
+	if cfg.MetricsRegisterer != nil {
+		stack = append(stack, MetricsMiddleware(cfg.MetricsRegisterer))
+	}
+
+	stack = append(stack,
+		AccessLogMiddleware(GetAccessLogFormatter(logger, accessLogWriter)),
+		rateLimit,
+		RecoveryMiddleware(logger),
+		CompressionMiddleware,
+		TimeoutMiddleware(GetRequestTimeout()),
+	)
+
+	if debugLog := GetDebugLogMiddleware(logger); debugLog != nil {
+		stack = append(stack, debugLog)
+	}
+
+	return stack, nil
+}
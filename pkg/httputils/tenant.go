@@ -0,0 +1,110 @@
+package httputils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+)
+
+// TenantIDHeader is the header HeaderTenantResolver reads the tenant from by default.
+const TenantIDHeader = "X-Tenant-ID"
+
+// TenantResolver returns the identifier of the tenant a request is for, such as a subdomain or tenant ID, or
+// an empty string if the request does not identify one.
+type TenantResolver func(r *http.Request) string
+
+// TenantLookupFunc returns the ID of the tenant with the given identifier. It returns dbutils.ErrRecordNotFound
+// if there is no such tenant.
+type TenantLookupFunc func(ctx context.Context, identifier string) (int64, error)
+
+// SubdomainTenantResolver resolves the tenant from the first label of the host when the host is a subdomain of
+// baseDomain, e.g. acme for acme.example.com.
+func SubdomainTenantResolver(baseDomain string) TenantResolver {
+	suffix := "." + strings.ToLower(strings.TrimPrefix(baseDomain, "."))
+
+	return func(r *http.Request) string {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+
+		subdomain, ok := strings.CutSuffix(strings.ToLower(host), suffix)
+		if !ok || strings.Contains(subdomain, ".") {
+			return ""
+		}
+
+		return subdomain
+	}
+}
+
+// HeaderTenantResolver resolves the tenant from the given request header, e.g. TenantIDHeader.
+func HeaderTenantResolver(header string) TenantResolver {
+	return func(r *http.Request) string {
+		return strings.TrimSpace(r.Header.Get(header))
+	}
+}
+
+// PathTenantResolver resolves the tenant from the named path parameter, e.g. tenant for routes mounted under
+// /t/{tenant}. The middleware must be applied within the route for the parameter to be set.
+func PathTenantResolver(name string) TenantResolver {
+	return func(r *http.Request) string {
+		return r.PathValue(name)
+	}
+}
+
+// TenantConfig configures TenantMiddleware.
+type TenantConfig struct {
+	// Resolvers are tried in order until one returns a tenant identifier.
+	Resolvers []TenantResolver
+	// Lookup returns the ID of the tenant with the resolved identifier.
+	Lookup TenantLookupFunc
+}
+
+// TenantMiddleware resolves the tenant a request is for and stores its ID in the request context, where
+// handlers can read it with TenantIDFromContext and pass it to tenant-scoped helpers such as
+// dbutils.GetByIDForTenant. Requests that do not identify a tenant, or identify an unknown one, receive a
+// 404 Not Found response.
+func TenantMiddleware(cfg TenantConfig) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identifier := ""
+
+			for _, resolve := range cfg.Resolvers {
+				if identifier = resolve(r); identifier != "" {
+					break
+				}
+			}
+
+			if identifier == "" {
+				NotFoundResponse(w, r)
+
+				return
+			}
+
+			tenantID, err := cfg.Lookup(r.Context(), identifier)
+			if err != nil {
+				if errors.Is(err, dbutils.ErrRecordNotFound) {
+					NotFoundResponse(w, r)
+				} else {
+					ServerErrorResponse(w, r, fmt.Errorf("failed to look up tenant %q: %w", identifier, err))
+				}
+
+				return
+			}
+
+			next.ServeHTTP(w, ContextSetTenant(r, tenantID))
+		})
+	}
+}
+
+// TenantIDFromContext returns the ID of the tenant resolved by TenantMiddleware.
+func TenantIDFromContext(ctx context.Context) (int64, bool) {
+	tenantID, ok := ctx.Value(tenantContextKey{}).(int64)
+
+	return tenantID, ok
+}
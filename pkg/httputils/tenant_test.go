@@ -0,0 +1,99 @@
+package httputils_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/dbutils"
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+func lookupTestTenant(_ context.Context, identifier string) (int64, error) {
+	switch identifier {
+	case "acme", "1":
+		return 1, nil
+	case "flancrest", "2":
+		return 2, nil
+	case "broken":
+		return 0, errors.New("database unavailable") //nolint: err113
+	default:
+		return 0, dbutils.ErrRecordNotFound
+	}
+}
+
+func TestTenantMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := httputils.TenantMiddleware(httputils.TenantConfig{
+		Resolvers: []httputils.TenantResolver{
+			httputils.SubdomainTenantResolver("example.com"),
+			httputils.HeaderTenantResolver(httputils.TenantIDHeader),
+		},
+		Lookup: lookupTestTenant,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID, ok := httputils.TenantIDFromContext(r.Context())
+		if !ok {
+			t.Error("expected tenant ID in context")
+		}
+
+		_, _ = w.Write([]byte(strconv.FormatInt(tenantID, 10)))
+	}))
+
+	tests := []struct {
+		name           string
+		host           string
+		header         string
+		expectedStatus int
+		expectedTenant string
+	}{
+		{"subdomain", "acme.example.com", "", http.StatusOK, "1"},
+		{"subdomain with port", "Flancrest.example.com:8080", "", http.StatusOK, "2"},
+		{"subdomain takes precedence over header", "acme.example.com", "2", http.StatusOK, "1"},
+		{"header", "example.com", "2", http.StatusOK, "2"},
+		{"nested subdomain falls back to header", "api.acme.example.com", "1", http.StatusOK, "1"},
+		{"unknown subdomain", "globex.example.com", "", http.StatusNotFound, ""},
+		{"unknown header", "example.com", "99", http.StatusNotFound, ""},
+		{"no tenant", "example.com", "", http.StatusNotFound, ""},
+		{"other domain", "acme.example.org", "", http.StatusNotFound, ""},
+		{"lookup error", "broken.example.com", "", http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			req.Host = tt.host
+
+			if tt.header != "" {
+				req.Header.Set(httputils.TenantIDHeader, tt.header)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if tt.expectedTenant != "" && rr.Body.String() != tt.expectedTenant {
+				t.Errorf("expected tenant %s, got %s", tt.expectedTenant, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestPathTenantResolver(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/t/acme/users", nil)
+	req.SetPathValue("tenant", "acme")
+
+	if tenant := httputils.PathTenantResolver("tenant")(req); tenant != "acme" {
+		t.Errorf("expected tenant acme, got %q", tenant)
+	}
+}
@@ -0,0 +1,216 @@
+package httputils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+)
+
+// defaultRequestTimeout leaves time to write the 503 response before the server's write timeout closes the
+// connection.
+const defaultRequestTimeout = writeTimeout - time.Second
+
+// GetRequestTimeout returns the request timeout configured by the REQUEST_TIMEOUT environment
+// variable, e.g. 5s. It defaults to 9 seconds and must be shorter than the server's 10 second write
+// timeout, otherwise clients would see the connection close rather than the timeout response.
+func GetRequestTimeout() time.Duration {
+	timeout, err := parser.ParseEnvDuration("REQUEST_TIMEOUT", defaultRequestTimeout)
+	if err != nil {
+		panic(err)
+	}
+
+	if timeout >= writeTimeout {
+		panic(fmt.Sprintf("REQUEST_TIMEOUT must be shorter than the %s write timeout, got %s", writeTimeout, timeout))
+	}
+
+	return timeout
+}
+
+// TimeoutMiddleware cancels the request context once d has elapsed and responds with a 503 Service
+// Unavailable JSON error if the handler has not finished by then. Handlers should pass the request
+// context to the dbutils functions so that in-flight queries are cancelled along with the request.
+//
+// The handler's response is buffered until it completes so that a timeout response can still be sent.
+// Handlers that stream can call Flush to send what they have written so far, after which a timeout cancels
+// the request context but can no longer replace the response.
+//
+// The handler runs in its own goroutine and keeps running after a timeout until it returns. Once the timeout
+// response has been sent the router may reuse the request's routing context, so chi.URLParam and RoutePattern
+// must be read before any work that can outlive the timeout, and handlers should stop once the request context
+// is cancelled. Panics in the handler are re-raised on the request goroutine with the handler's stack trace.
+func TimeoutMiddleware(d time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			r = r.WithContext(ctx)
+
+			done := make(chan struct{})
+			panicChan := make(chan *handlerPanic, 1)
+			tw := &timeoutWriter{w: w, ctx: ctx, header: make(http.Header), status: http.StatusOK} //nolint: exhaustruct
+
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicChan <- &handlerPanic{value: p, stack: debug.Stack()}
+					}
+				}()
+
+				next.ServeHTTP(tw, r)
+				tw.complete()
+				close(done)
+			}()
+
+			select {
+			case p := <-panicChan:
+				if p.value == http.ErrAbortHandler { //nolint: errorlint
+					panic(p.value)
+				}
+
+				panic(p)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+
+				if err := tw.writeBuffered(); err != nil {
+					logError(r, err)
+				}
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+
+				// The handler may have returned just before the deadline, with both channels ready by the time the
+				// select ran, in which case its response wins.
+				if tw.completed {
+					if err := tw.writeBuffered(); err != nil {
+						logError(r, err)
+					}
+
+					return
+				}
+
+				tw.timedOut = true
+
+				if !tw.flushed && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					TimeoutResponse(w, r)
+				}
+			}
+		})
+	}
+}
+
+// handlerPanic carries a panic raised in another goroutine along with the stack trace captured where it was
+// recovered, so that RecoveryMiddleware can log where the handler actually failed. If abort is set, part of the
+// response has already been sent, so RecoveryMiddleware aborts the response instead of writing a 500.
+type handlerPanic struct {
+	value any
+	stack []byte
+	abort bool
+}
+
+func (p *handlerPanic) String() string {
+	return fmt.Sprint(p.value)
+}
+
+// timeoutWriter buffers a handler's response so it can be discarded if the handler times out.
+type timeoutWriter struct {
+	w           http.ResponseWriter
+	ctx         context.Context //nolint: containedctx
+	mu          sync.Mutex
+	header      http.Header
+	buf         bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+	flushed     bool
+	// completed is set if the handler returned before the request context was done.
+	completed bool
+}
+
+// complete records that the handler has returned, if it did so before the request context was done.
+func (tw *timeoutWriter) complete() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	tw.completed = tw.ctx.Err() == nil
+}
+
+// expired reports whether the handler can no longer write because the request context is done, even if
+// TimeoutMiddleware has not yet recorded the timeout. tw.mu must be held.
+func (tw *timeoutWriter) expired() bool {
+	return tw.timedOut || tw.ctx.Err() != nil
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.expired() || tw.wroteHeader {
+		return
+	}
+
+	tw.wroteHeader = true
+	tw.status = status
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.expired() {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	tw.wroteHeader = true
+
+	return tw.buf.Write(b) //nolint: wrapcheck
+}
+
+// Flush sends the buffered response to the client so that streamed responses are not held back.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.expired() {
+		return
+	}
+
+	if err := tw.writeBuffered(); err != nil {
+		return
+	}
+
+	if flusher, ok := tw.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// writeBuffered writes the status and headers, if they have not been sent yet, and the buffered body to the
+// underlying writer. tw.mu must be held.
+func (tw *timeoutWriter) writeBuffered() error {
+	if !tw.flushed {
+		dst := tw.w.Header()
+		for key, values := range tw.header {
+			dst[key] = values
+		}
+
+		tw.w.WriteHeader(tw.status)
+		tw.flushed = true
+	}
+
+	_, err := tw.w.Write(tw.buf.Bytes())
+	tw.buf.Reset()
+
+	return err //nolint: wrapcheck
+}
@@ -0,0 +1,152 @@
+package httputils_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	t.Parallel()
+
+	t.Run("handler completes in time", func(t *testing.T) {
+		t.Parallel()
+
+		handler := httputils.TimeoutMiddleware(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("X-Test", "value")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("created"))
+		}))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+
+		if rr.Code != http.StatusCreated {
+			t.Errorf("expected status 201, got %d", rr.Code)
+		}
+
+		if rr.Header().Get("X-Test") != "value" {
+			t.Errorf("expected handler headers to be copied, got %v", rr.Header())
+		}
+
+		if rr.Body.String() != "created" {
+			t.Errorf("expected body 'created', got %q", rr.Body.String())
+		}
+	})
+
+	t.Run("handler exceeds timeout", func(t *testing.T) {
+		t.Parallel()
+
+		handlerErr := make(chan error, 1)
+		handler := httputils.TimeoutMiddleware(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+
+			_, err := w.Write([]byte("too late"))
+			handlerErr <- err
+		}))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rr.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", rr.Code)
+		}
+
+		if !strings.Contains(rr.Body.String(), "the request timed out") {
+			t.Errorf("expected timeout error body, got %q", rr.Body.String())
+		}
+
+		if err := <-handlerErr; !errors.Is(err, http.ErrHandlerTimeout) {
+			t.Errorf("expected late write to fail with ErrHandlerTimeout, got %v", err)
+		}
+	})
+
+	t.Run("flushed response is streamed", func(t *testing.T) {
+		t.Parallel()
+
+		flushed := make(chan struct{})
+		handler := httputils.TimeoutMiddleware(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte("partial"))
+
+			flusher, ok := w.(http.Flusher)
+			if !ok {
+				t.Error("expected the timeout writer to implement http.Flusher")
+			} else {
+				flusher.Flush()
+			}
+
+			close(flushed)
+			<-r.Context().Done()
+		}))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		<-flushed
+
+		if rr.Code != http.StatusAccepted || rr.Body.String() != "partial" || !rr.Flushed {
+			t.Errorf("expected flushed 202 response, got %d %q flushed=%v", rr.Code, rr.Body.String(), rr.Flushed)
+		}
+	})
+
+	t.Run("in-flight query is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		db := testutils.SetupTestDB(t)
+		defer func() {
+			closeErr := db.Close()
+			if closeErr != nil {
+				t.Fatalf("Failed to close database connection: %v", closeErr)
+			}
+		}()
+
+		queryErr := make(chan error, 1)
+		handler := httputils.TimeoutMiddleware(10 * time.Millisecond)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			var count int64
+
+			queryErr <- db.QueryRowContext(r.Context(), `
+				WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 1000000000)
+				SELECT COUNT(*) FROM c`).Scan(&count)
+		}))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rr.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status 503, got %d", rr.Code)
+		}
+
+		select {
+		case err := <-queryErr:
+			if !errors.Is(err, context.DeadlineExceeded) && (err == nil || !strings.Contains(err.Error(), "interrupt")) {
+				t.Errorf("expected query to be cancelled, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("expected query to be cancelled when the request timed out")
+		}
+	})
+}
+
+//nolint:paralleltest // sets environment variables
+func TestGetRequestTimeout(t *testing.T) {
+	if timeout := httputils.GetRequestTimeout(); timeout != 9*time.Second {
+		t.Errorf("expected default timeout below the write timeout, got %s", timeout)
+	}
+
+	t.Setenv("REQUEST_TIMEOUT", "30s")
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a timeout longer than the write timeout to panic")
+		}
+	}()
+
+	httputils.GetRequestTimeout()
+}
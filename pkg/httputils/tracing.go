@@ -0,0 +1,59 @@
+package httputils
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceparentHeader is the W3C Trace Context header used to propagate traces between services.
+const TraceparentHeader = "traceparent"
+
+// InjectTraceContext sets the W3C trace context headers on an outgoing request's headers so that downstream
+// services join the current trace.
+func InjectTraceContext(ctx context.Context, header http.Header) {
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// TracingMiddleware starts an OpenTelemetry server span for each request with otelhttp, using tracerProvider
+// so that tests can record spans with an in-memory exporter. If the request has a valid W3C traceparent
+// header, the span joins that trace; otherwise a new trace is started. The span is named after the method and
+// matched route pattern, e.g. GET /tenants/{id}, records the method, route, response status and request id,
+// and its traceparent is returned in the response headers.
+func TracingMiddleware(tracerProvider trace.TracerProvider) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		next = RecordRoutePattern(next)
+
+		traced := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			InjectTraceContext(r.Context(), w.Header())
+
+			r, _ = withRoutePatternHolder(r)
+
+			// The span is named once the route is known, so it is named even if the handler panics.
+			defer func() {
+				span := trace.SpanFromContext(r.Context())
+				span.SetAttributes(attribute.String("request.id", middleware.GetReqID(r.Context())))
+
+				if route := RoutePattern(r); route != "" {
+					span.SetName(r.Method + " " + route)
+					span.SetAttributes(attribute.String("http.route", route))
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+
+		return otelhttp.NewHandler(traced, "",
+			otelhttp.WithTracerProvider(tracerProvider),
+			otelhttp.WithPropagators(propagation.TraceContext{}),
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method
+			}),
+		)
+	}
+}
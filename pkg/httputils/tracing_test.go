@@ -0,0 +1,147 @@
+package httputils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		traceparent   string
+		expectParent  bool
+		expectTraceID string
+		expectExport  bool
+	}{
+		{
+			name:          "joins incoming trace",
+			traceparent:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectParent:  true,
+			expectTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectExport:  true,
+		},
+		{
+			name:          "joins incoming trace with a higher version",
+			traceparent:   "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future",
+			expectParent:  true,
+			expectTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectExport:  true,
+		},
+		{
+			name:         "starts new trace",
+			expectExport: true,
+		},
+		{
+			name:         "ignores malformed traceparent",
+			traceparent:  "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			expectExport: true,
+		},
+		{
+			name:          "does not export unsampled trace",
+			traceparent:   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+			expectParent:  true,
+			expectTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			exporter := tracetest.NewInMemoryExporter()
+			tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+			router := testutils.NewRouter()
+			router.Use(httputils.RequestIDMiddleware)
+			router.Use(httputils.TracingMiddleware(tracerProvider))
+
+			var outgoing http.Header
+
+			router.Get("/tenants/{id}", func(w http.ResponseWriter, r *http.Request) {
+				outgoing = make(http.Header)
+				httputils.InjectTraceContext(r.Context(), outgoing)
+
+				w.WriteHeader(http.StatusNotFound)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/tenants/1", nil)
+			req.Header.Set(httputils.RequestIDHeader, "request-1")
+
+			if tt.traceparent != "" {
+				req.Header.Set(httputils.TraceparentHeader, tt.traceparent)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			traceparent := rr.Header().Get(httputils.TraceparentHeader)
+			if traceparent == "" {
+				t.Fatal("expected traceparent response header")
+			}
+
+			if outgoing.Get(httputils.TraceparentHeader) != traceparent {
+				t.Errorf("expected injected traceparent %q, got %q", traceparent, outgoing.Get(httputils.TraceparentHeader))
+			}
+
+			if tt.expectTraceID != "" && traceparent[3:35] != tt.expectTraceID {
+				t.Errorf("expected trace id %s, got traceparent %s", tt.expectTraceID, traceparent)
+			}
+
+			spans := exporter.GetSpans()
+			if !tt.expectExport {
+				if len(spans) != 0 {
+					t.Errorf("expected no spans to be exported, got %d", len(spans))
+				}
+
+				return
+			}
+
+			if len(spans) != 1 {
+				t.Fatalf("expected 1 span, got %d", len(spans))
+			}
+
+			span := spans[0]
+			if span.SpanContext.TraceID().String() != traceparent[3:35] ||
+				span.SpanContext.SpanID().String() != traceparent[36:52] {
+				t.Errorf("expected span context to match %s, got %s-%s",
+					traceparent, span.SpanContext.TraceID(), span.SpanContext.SpanID())
+			}
+
+			if tt.expectParent && span.Parent.SpanID().String() != "00f067aa0ba902b7" {
+				t.Errorf("expected parent span id 00f067aa0ba902b7, got %q", span.Parent.SpanID())
+			}
+
+			if !tt.expectParent && span.Parent.IsValid() {
+				t.Errorf("expected no parent span, got %q", span.Parent.SpanID())
+			}
+
+			attributes := map[attribute.Key]string{}
+			for _, kv := range span.Attributes {
+				attributes[kv.Key] = kv.Value.Emit()
+			}
+
+			expectedAttributes := map[attribute.Key]string{
+				"http.route": "/tenants/{id}",
+				"request.id": "request-1",
+			}
+			for key, value := range expectedAttributes {
+				if attributes[key] != value {
+					t.Errorf("expected attribute %s to be %v, got %v", key, value, attributes[key])
+				}
+			}
+
+			if span.Name != "GET /tenants/{id}" {
+				t.Errorf("expected span name 'GET /tenants/{id}', got %q", span.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package httputils
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+type bodyContextKey struct{}
+
+// ValidateBody returns middleware that decodes the JSON request body into a T with DecodeJSON and checks it
+// against the validate tags on T's fields, as described by validation.ValidateStruct. Bodies that can't be
+// decoded are rejected by HandleErrorResponse and bodies that break a rule are rejected with a 400
+// validation response, so the next handler only runs for valid bodies and reads the decoded value with
+// ContextGetBody:
+//
+//	func CreateTenantHandler(w http.ResponseWriter, r *http.Request) {
+//		req, _ := httputils.ContextGetBody[CreateTenantRequest](r)
+//		...
+//	}
+//
+//	router.With(httputils.ValidateBody[CreateTenantRequest](catalog)).Post("/tenants", CreateTenantHandler)
+//
+// If catalog is non-nil, error messages are localized with NewRequestValidator.
+func ValidateBody[T any](catalog *validation.Catalog) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body T
+
+			if err := DecodeJSON(w, r, &body); err != nil {
+				HandleErrorResponse(w, r, err)
+
+				return
+			}
+
+			v := validation.NewValidator()
+			if catalog != nil {
+				v = NewRequestValidator(r, catalog)
+			}
+
+			validation.ValidateStruct(v, body)
+
+			if v.HasErrors() {
+				FailedValidationResponse(w, r, v.Errors)
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), bodyContextKey{}, body)))
+		})
+	}
+}
+
+// ContextGetBody returns the request body validated by ValidateBody. ok is false if no body was stored or it
+// is not a T.
+func ContextGetBody[T any](r *http.Request) (T, bool) { //nolint: ireturn
+	body, ok := r.Context().Value(bodyContextKey{}).(T)
+
+	return body, ok
+}
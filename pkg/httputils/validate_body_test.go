@@ -0,0 +1,118 @@
+package httputils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+type validateBodyTestRequest struct {
+	TenantName   string `json:"tenantName" validate:"required"`
+	ContactEmail string `json:"contactEmail" validate:"required,email"`
+	Plan         string `json:"plan" validate:"required,oneof=free paid"`
+}
+
+func TestValidateBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		body            string
+		catalog         *validation.Catalog
+		expectedStatus  int
+		expectedBody    map[string]any
+		expectedHandled bool
+	}{
+		{
+			name:            "valid body",
+			body:            `{"tenantName":"Acme","contactEmail":"admin@acme.com","plan":"paid"}`,
+			expectedStatus:  http.StatusOK,
+			expectedHandled: true,
+		},
+		{
+			name:           "invalid plan",
+			body:           `{"tenantName":"Acme","contactEmail":"admin@acme.com","plan":"enterprise"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]any{"errors": []any{
+				map[string]any{"field": "plan", "message": "must be one of free, paid"},
+			}},
+		},
+		{
+			name:           "localized errors",
+			body:           `{"tenantName":"","contactEmail":"admin@acme.com","plan":"free"}`,
+			catalog:        validation.NewCatalog().Add("fr", map[string]string{"is required": "est obligatoire"}),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]any{"errors": []any{
+				map[string]any{"field": "tenantName", "message": "est obligatoire"},
+			}},
+		},
+		{
+			name:           "malformed body",
+			body:           `{"tenantName":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handled := false
+			handler := httputils.ValidateBody[validateBodyTestRequest](tt.catalog)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					handled = true
+
+					body, ok := httputils.ContextGetBody[validateBodyTestRequest](r)
+					if !ok || body.Plan != "paid" || body.TenantName != "Acme" {
+						t.Errorf("expected the decoded body in the context, got %+v (ok=%v)", body, ok)
+					}
+
+					w.WriteHeader(http.StatusOK)
+				}),
+			)
+
+			req := httptest.NewRequest(http.MethodPost, "/tenants", strings.NewReader(tt.body))
+			req.Header.Set("Accept-Language", "fr")
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if handled != tt.expectedHandled {
+				t.Errorf("expected handler to run: %v, got %v", tt.expectedHandled, handled)
+			}
+
+			if tt.expectedBody == nil {
+				return
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response body: %v", err)
+			}
+
+			if !reflect.DeepEqual(body, tt.expectedBody) {
+				t.Errorf("expected body %v, got %v", tt.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestContextGetBody_NotSet(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if body, ok := httputils.ContextGetBody[validateBodyTestRequest](req); ok || body != (validateBodyTestRequest{}) {
+		t.Errorf("expected no body, got %+v", body)
+	}
+}
@@ -4,6 +4,7 @@ package mailutils
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"html/template"
@@ -23,6 +24,13 @@ var ErrTemplateExecution = errors.New("error executing mail template")
 
 const retryInterval = 500 * time.Millisecond
 
+const (
+	// sendWorkers is the number of emails that are sent concurrently.
+	sendWorkers = 4
+	// sendQueueSize is the number of emails that may wait for a free worker before Send drops them.
+	sendQueueSize = 100
+)
+
 // MailSender is an interface for sending emails.
 type MailSender interface {
 	Send(recipient, templateName string, data map[string]string) error
@@ -37,6 +45,7 @@ type Emailer struct {
 	dialer    *gomail.Dialer
 	sender    string
 	templates map[string]*template.Template
+	workers   *threads.Pool
 }
 
 // New initializes a new Mailer instance.
@@ -48,66 +57,107 @@ func NewMailer(
 ) *Emailer {
 	dialer := gomail.NewDialer(host, port, username, password)
 
-	return &Emailer{dialer: dialer, sender: sender, templates: templates}
+	return &Emailer{
+		dialer:    dialer,
+		sender:    sender,
+		templates: templates,
+		workers:   threads.NewPool(sendWorkers, sendQueueSize),
+	}
+}
+
+// Workers returns the pool that emails are sent from. Pass it to httputils.ServerConfig.Workers so that
+// queued emails are sent before the server shuts down.
+func (m *Emailer) Workers() *threads.Pool {
+	return m.workers
 }
 
 // InitMailer initializes a new Mailer instance from SMTP_HOST, SMTP_PORT,
 // SMTP_USERNAME, SMTP_PASSWORD, and SMTP_FROM environment variables.
 func InitMailer(templates map[string]*template.Template) *Emailer {
 	return NewMailer(
-		parser.ParseEnvStringPanic("SMTP_HOST"),
-		parser.ParseEnvIntPanic("SMTP_PORT"),
-		parser.ParseEnvStringPanic("SMTP_USERNAME"),
-		parser.ParseEnvStringPanic("SMTP_PASSWORD"),
-		parser.ParseEnvStringPanic("SMTP_FROM"),
+		parser.MustParseEnvString("SMTP_HOST"),
+		parser.MustParseEnvInt("SMTP_PORT"),
+		parser.MustParseEnvString("SMTP_USERNAME"),
+		parser.MustParseEnvStringOrFile("SMTP_PASSWORD"),
+		parser.MustParseEnvString("SMTP_FROM"),
 		templates,
 	)
 }
 
-// Send sends an email from a template using the provided data.
+// Send sends an email from a template using the provided data. The email is sent in the background by the
+// mailer's worker pool.
 func (m *Emailer) Send(recipient, templateName string, data map[string]string) {
-	threads.Background(func() {
+	err := m.workers.Submit(func(_ context.Context) {
 		err := m.sendInternal(recipient, templateName, data)
-		if err == nil {
-			slog.Error("failed to send invite email", "error", err)
+		if err != nil {
+			slog.Error("failed to send email", "template", templateName, "error", err)
 		}
 	})
+	if err != nil {
+		slog.Error("failed to queue email", "template", templateName, "error", err)
+	}
 }
 
-func (m *Emailer) sendInternal(recipient, templateName string, data map[string]string) error {
-	var err error
+// Message is an email rendered from a template.
+type Message struct {
+	Recipient string
+	Subject   string
+	PlainBody string
+	HTMLBody  string
+}
 
-	tmpl, ok := m.templates[templateName]
+// RenderMessage renders the subject, plainBody and htmlBody blocks of the named template with data. The
+// templates are typically loaded from an embedded FS with templateutils.LoadTemplates.
+func RenderMessage(
+	templates map[string]*template.Template,
+	recipient, templateName string,
+	data map[string]string,
+) (*Message, error) {
+	tmpl, ok := templates[templateName]
 	if !ok {
-		return ErrTemplateNotFound
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, templateName)
 	}
 
 	subject := new(bytes.Buffer)
 	if err := tmpl.ExecuteTemplate(subject, "subject", data); err != nil {
-		return fmt.Errorf("%w: %w", ErrTemplateExecution, err)
+		return nil, fmt.Errorf("%w: %w", ErrTemplateExecution, err)
 	}
 
 	plainBody := new(bytes.Buffer)
 	if err := tmpl.ExecuteTemplate(plainBody, "plainBody", data); err != nil {
-		return fmt.Errorf("%w: %w", ErrTemplateExecution, err)
+		return nil, fmt.Errorf("%w: %w", ErrTemplateExecution, err)
 	}
 
 	htmlBody := new(bytes.Buffer)
 	if err := tmpl.ExecuteTemplate(htmlBody, "htmlBody", data); err != nil {
-		return fmt.Errorf("%w: %w", ErrTemplateExecution, err)
+		return nil, fmt.Errorf("%w: %w", ErrTemplateExecution, err)
+	}
+
+	return &Message{
+		Recipient: recipient,
+		Subject:   subject.String(),
+		PlainBody: plainBody.String(),
+		HTMLBody:  htmlBody.String(),
+	}, nil
+}
+
+func (m *Emailer) sendInternal(recipient, templateName string, data map[string]string) error {
+	message, err := RenderMessage(m.templates, recipient, templateName, data)
+	if err != nil {
+		return err
 	}
 
 	msg := gomail.NewMessage()
 	msg.SetHeader("From", m.sender)
-	msg.SetHeader("To", recipient)
-	msg.SetHeader("Subject", subject.String())
-	msg.SetBody("text/plain", plainBody.String())
-	msg.AddAlternative("text/html", htmlBody.String())
+	msg.SetHeader("To", message.Recipient)
+	msg.SetHeader("Subject", message.Subject)
+	msg.SetBody("text/plain", message.PlainBody)
+	msg.AddAlternative("text/html", message.HTMLBody)
 
 	// Try sending the email up to three times before aborting and returning the final
 	// error. We sleep for 500 milliseconds between each attempt.
 	for i := 1; i <= 3; i++ {
-		err := m.dialer.DialAndSend(msg)
+		err = m.dialer.DialAndSend(msg)
 		if nil == err {
 			return nil
 		}
@@ -115,5 +165,5 @@ func (m *Emailer) sendInternal(recipient, templateName string, data map[string]s
 		time.Sleep(retryInterval)
 	}
 
-	return err
+	return fmt.Errorf("failed to send email: %w", err)
 }
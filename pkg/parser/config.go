@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrInvalidConfigTarget is returned when LoadConfig is not passed a non-nil pointer to a struct.
+	ErrInvalidConfigTarget = errors.New("config must be a non-nil pointer to a struct")
+	// ErrInvalidConfigField is returned when an env var cannot be parsed into its config field.
+	ErrInvalidConfigField = errors.New("invalid config field")
+	// ErrUnsupportedConfigType is returned when a config field has a type LoadConfig cannot populate.
+	ErrUnsupportedConfigType = errors.New("unsupported config field type")
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// LoadConfig populates the fields of the struct pointed to by cfg from the environment. Each field tagged
+// with env:"NAME" is set from the NAME env var, or from its default:"..." tag if NAME is not set. Fields
+// tagged with required:"true" must be set in the environment. Untagged struct fields are loaded recursively.
+//
+// Supported field types are string, bool, signed and unsigned integers, floats, time.Duration (e.g. 30s)
+// and []string (comma-separated). Errors for every invalid field are joined and returned together.
+//
+//	type ServerConfig struct {
+//		Port         int           `env:"SERVER_PORT" default:"8080"`
+//		DrainTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"5s"`
+//		Origins      []string      `env:"CORS_ALLOWED_ORIGINS"`
+//	}
+func LoadConfig(cfg any) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidConfigTarget
+	}
+
+	return errors.Join(loadStruct(v.Elem())...)
+}
+
+func loadStruct(v reflect.Value) []error {
+	var errs []error
+
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key, ok := field.Tag.Lookup("env")
+		if !ok {
+			if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+				errs = append(errs, loadStruct(v.Field(i))...)
+			}
+
+			continue
+		}
+
+		val := os.Getenv(key)
+		if val == "" {
+			if field.Tag.Get("required") == "true" {
+				errs = append(errs, fmt.Errorf("%w: %s", ErrMissingEnvVar, key))
+
+				continue
+			}
+
+			val, ok = field.Tag.Lookup("default")
+			if !ok {
+				continue
+			}
+		}
+
+		if err := setField(v.Field(i), val); err != nil {
+			errs = append(errs, fmt.Errorf("%w %s (env var %s): %w", ErrInvalidConfigField, field.Name, key, err))
+		}
+	}
+
+	return errs
+}
+
+func setField(field reflect.Value, val string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err //nolint: wrapcheck
+		}
+
+		field.SetInt(int64(d))
+
+		return nil
+	}
+
+	//nolint: exhaustive
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err //nolint: wrapcheck
+		}
+
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(val, 10, field.Type().Bits())
+		if err != nil {
+			return err //nolint: wrapcheck
+		}
+
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(val, 10, field.Type().Bits())
+		if err != nil {
+			return err //nolint: wrapcheck
+		}
+
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(val, field.Type().Bits())
+		if err != nil {
+			return err //nolint: wrapcheck
+		}
+
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%w: %s", ErrUnsupportedConfigType, field.Type())
+		}
+
+		field.Set(reflect.ValueOf(splitList(val)).Convert(field.Type()))
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedConfigType, field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package parser_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+)
+
+type testDatabaseConfig struct {
+	Path string `env:"TEST_CONFIG_DB_PATH" required:"true"`
+}
+
+type testConfig struct {
+	Name     string        `env:"TEST_CONFIG_NAME" default:"app"`
+	Port     int           `env:"TEST_CONFIG_PORT" default:"8080"`
+	Burst    uint16        `env:"TEST_CONFIG_BURST"`
+	Enabled  bool          `env:"TEST_CONFIG_ENABLED"`
+	Rate     float64       `env:"TEST_CONFIG_RATE" default:"2.5"`
+	Timeout  time.Duration `env:"TEST_CONFIG_TIMEOUT" default:"30s"`
+	Origins  []string      `env:"TEST_CONFIG_ORIGINS"`
+	Database testDatabaseConfig
+	Ignored  string
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected testConfig
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{"TEST_CONFIG_DB_PATH": "./app.db"},
+			expected: testConfig{
+				Name:     "app",
+				Port:     8080,
+				Rate:     2.5,
+				Timeout:  30 * time.Second,
+				Database: testDatabaseConfig{Path: "./app.db"},
+			},
+		},
+		{
+			name: "from env",
+			env: map[string]string{
+				"TEST_CONFIG_NAME":    "api",
+				"TEST_CONFIG_PORT":    "9090",
+				"TEST_CONFIG_BURST":   "20",
+				"TEST_CONFIG_ENABLED": "true",
+				"TEST_CONFIG_RATE":    "0.5",
+				"TEST_CONFIG_TIMEOUT": "1m30s",
+				"TEST_CONFIG_ORIGINS": "https://a.example.com, https://b.example.com",
+				"TEST_CONFIG_DB_PATH": "/data/app.db",
+			},
+			expected: testConfig{
+				Name:     "api",
+				Port:     9090,
+				Burst:    20,
+				Enabled:  true,
+				Rate:     0.5,
+				Timeout:  90 * time.Second,
+				Origins:  []string{"https://a.example.com", "https://b.example.com"},
+				Database: testDatabaseConfig{Path: "/data/app.db"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{
+				"TEST_CONFIG_NAME", "TEST_CONFIG_PORT", "TEST_CONFIG_BURST", "TEST_CONFIG_ENABLED",
+				"TEST_CONFIG_RATE", "TEST_CONFIG_TIMEOUT", "TEST_CONFIG_ORIGINS", "TEST_CONFIG_DB_PATH",
+			} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			var cfg testConfig
+			if err := parser.LoadConfig(&cfg); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(cfg, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, cfg)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestLoadConfigAggregatesErrors(t *testing.T) {
+	t.Setenv("TEST_CONFIG_NAME", "")
+	t.Setenv("TEST_CONFIG_PORT", "eighty")
+	t.Setenv("TEST_CONFIG_BURST", "-1")
+	t.Setenv("TEST_CONFIG_ENABLED", "maybe")
+	t.Setenv("TEST_CONFIG_RATE", "fast")
+	t.Setenv("TEST_CONFIG_TIMEOUT", "30")
+	t.Setenv("TEST_CONFIG_ORIGINS", "")
+	t.Setenv("TEST_CONFIG_DB_PATH", "")
+
+	var cfg testConfig
+
+	err := parser.LoadConfig(&cfg)
+	if !errors.Is(err, parser.ErrInvalidConfigField) || !errors.Is(err, parser.ErrMissingEnvVar) {
+		t.Fatalf("expected invalid field and missing env var errors, got %v", err)
+	}
+
+	for _, key := range []string{
+		"TEST_CONFIG_PORT", "TEST_CONFIG_BURST", "TEST_CONFIG_ENABLED",
+		"TEST_CONFIG_RATE", "TEST_CONFIG_TIMEOUT", "TEST_CONFIG_DB_PATH",
+	} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("expected error to mention %s, got %v", key, err)
+		}
+	}
+}
+
+func TestLoadConfigInvalidTarget(t *testing.T) {
+	t.Parallel()
+
+	var cfg testConfig
+
+	unsupported := struct {
+		Ports []int `env:"TEST_CONFIG_UNSUPPORTED" default:"80,443"`
+	}{}
+
+	tests := []struct {
+		name     string
+		target   any
+		expected error
+	}{
+		{name: "non-pointer", target: cfg, expected: parser.ErrInvalidConfigTarget},
+		{name: "nil pointer", target: (*testConfig)(nil), expected: parser.ErrInvalidConfigTarget},
+		{name: "pointer to non-struct", target: new(string), expected: parser.ErrInvalidConfigTarget},
+		{name: "unsupported type", target: &unsupported, expected: parser.ErrUnsupportedConfigType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := parser.LoadConfig(tt.target); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrInvalidDotEnvLine is returned when a line of a .env file is not a KEY=VALUE assignment.
+var ErrInvalidDotEnvLine = errors.New("invalid .env line")
+
+// LoadDotEnv reads KEY=VALUE assignments from the file at path and sets each one that is not already
+// present in the environment, so real environment variables always take precedence. It should be called
+// before any ParseEnv calls. If the file does not exist, the returned error wraps os.ErrNotExist.
+//
+// Blank lines and lines starting with # are ignored, and an optional export prefix is allowed so that
+// direnv .envrc files can be loaded. Values may be:
+//   - unquoted, with trailing comments starting with " #" removed
+//   - single quoted, taken literally
+//   - double quoted, with \n, \", and \\ escapes
+//
+// Variable references such as ${VAR} are not expanded, so values containing $ are set literally.
+func LoadDotEnv(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, err := parseDotEnvLine(line)
+		if err != nil {
+			return fmt.Errorf("%w: %s:%d: %w", ErrInvalidDotEnvLine, path, lineNumber, err)
+		}
+
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set env var %s: %w", key, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return nil
+}
+
+var (
+	errMissingAssignment = errors.New("expected KEY=VALUE")
+	errUnterminatedQuote = errors.New("unterminated quoted value")
+)
+
+func parseDotEnvLine(line string) (string, string, error) {
+	line = strings.TrimPrefix(line, "export ")
+
+	key, value, ok := strings.Cut(line, "=")
+	key = strings.TrimSpace(key)
+
+	if !ok || key == "" || strings.ContainsAny(key, " \t") {
+		return "", "", errMissingAssignment
+	}
+
+	value = strings.TrimSpace(value)
+
+	switch {
+	case strings.HasPrefix(value, "'"):
+		end := strings.Index(value[1:], "'")
+		if end < 0 {
+			return "", "", errUnterminatedQuote
+		}
+
+		return key, value[1 : end+1], nil
+	case strings.HasPrefix(value, `"`):
+		unquoted, ok := unquoteDoubleQuoted(value[1:])
+		if !ok {
+			return "", "", errUnterminatedQuote
+		}
+
+		return key, unquoted, nil
+	default:
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+
+		return key, value, nil
+	}
+}
+
+// unquoteDoubleQuoted returns the contents of a double quoted value up to its closing quote, which has
+// already had its opening quote removed, and reports whether the closing quote was found.
+func unquoteDoubleQuoted(value string) (string, bool) {
+	var sb strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '"':
+			return sb.String(), true
+		case c == '\\' && i+1 < len(value):
+			i++
+
+			switch value[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case '"', '\\':
+				sb.WriteByte(value[i])
+			default:
+				sb.WriteByte('\\')
+				sb.WriteByte(value[i])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return "", false
+}
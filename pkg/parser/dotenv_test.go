@@ -0,0 +1,101 @@
+package parser_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+)
+
+func writeDotEnv(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed to write .env file: %v", err)
+	}
+
+	return path
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestLoadDotEnv(t *testing.T) {
+	keys := []string{
+		"DOTENV_PLAIN", "DOTENV_EXPORTED", "DOTENV_COMMENT", "DOTENV_HASH", "DOTENV_SINGLE",
+		"DOTENV_DOUBLE", "DOTENV_EMPTY", "DOTENV_DOLLAR", "DOTENV_DOUBLE_DOLLAR", "DOTENV_REFERENCE",
+		"DOTENV_EXISTING",
+	}
+	for _, key := range keys {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+
+	t.Setenv("DOTENV_EXISTING", "from-environment")
+
+	path := writeDotEnv(t, `
+# a comment
+DOTENV_PLAIN=plain
+export DOTENV_EXPORTED=8080
+DOTENV_COMMENT=value # trailing comment
+DOTENV_HASH=pass#word
+DOTENV_SINGLE='literal $DOTENV_PLAIN # not a comment'
+DOTENV_DOUBLE="line one\nsays \"hi\" # not a comment"
+DOTENV_EMPTY=
+DOTENV_DOLLAR=pa$word
+DOTENV_DOUBLE_DOLLAR="pa$$word"
+DOTENV_REFERENCE="http://localhost:${DOTENV_EXPORTED}"
+DOTENV_EXISTING=from-file
+`)
+
+	if err := parser.LoadDotEnv(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"DOTENV_PLAIN":         "plain",
+		"DOTENV_EXPORTED":      "8080",
+		"DOTENV_COMMENT":       "value",
+		"DOTENV_HASH":          "pass#word",
+		"DOTENV_SINGLE":        "literal $DOTENV_PLAIN # not a comment",
+		"DOTENV_DOUBLE":        "line one\nsays \"hi\" # not a comment",
+		"DOTENV_EMPTY":         "",
+		"DOTENV_DOLLAR":        "pa$word",
+		"DOTENV_DOUBLE_DOLLAR": "pa$$word",
+		"DOTENV_REFERENCE":     "http://localhost:${DOTENV_EXPORTED}",
+		"DOTENV_EXISTING":      "from-environment",
+	}
+
+	for key, value := range expected {
+		if got := os.Getenv(key); got != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, got)
+		}
+	}
+}
+
+//nolint:paralleltest // LoadDotEnv sets env vars
+func TestLoadDotEnvErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		expected error
+	}{
+		{name: "missing assignment", contents: "DOTENV_INVALID\n", expected: parser.ErrInvalidDotEnvLine},
+		{name: "space in key", contents: "DOTENV INVALID=value\n", expected: parser.ErrInvalidDotEnvLine},
+		{name: "unterminated quote", contents: "DOTENV_INVALID=\"value\n", expected: parser.ErrInvalidDotEnvLine},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := parser.LoadDotEnv(writeDotEnv(t, tt.contents)); !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	err := parser.LoadDotEnv(filepath.Join(t.TempDir(), "missing.env"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist for a missing file, got %v", err)
+	}
+}
@@ -1,11 +1,21 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// ErrMissingEnvVar is returned when a required env var is not set.
+var ErrMissingEnvVar = errors.New("missing required env var")
+
+// ErrInvalidURL is returned when an env var is not an absolute URL.
+var ErrInvalidURL = errors.New("invalid URL")
+
 func ParseEnvString(key string, defaultValue string) string {
 	val := os.Getenv(key)
 	if val == "" {
@@ -38,6 +48,37 @@ func ParseEnvInt(key string, defaultValue int) (int, error) {
 	return intVal, nil
 }
 
+// ParseEnvInt64 parses the env var as an int64. It returns defaultValue if the env var is not set.
+func ParseEnvInt64(key string, defaultValue int64) (int64, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultValue, nil
+	}
+
+	intVal, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse env var %s as int64: %w", key, err)
+	}
+
+	return intVal, nil
+}
+
+// ParseEnvUint parses the env var as a uint, rejecting negative values. It returns defaultValue if the env var
+// is not set.
+func ParseEnvUint(key string, defaultValue uint) (uint, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultValue, nil
+	}
+
+	uintVal, err := strconv.ParseUint(val, 10, strconv.IntSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse env var %s as uint: %w", key, err)
+	}
+
+	return uint(uintVal), nil
+}
+
 func ParseEnvIntPanic(key string) int {
 	val := os.Getenv(key)
 	if val == "" {
@@ -74,3 +115,129 @@ func ParseEnvFloat64(key string, defaultValue float64) (float64, error) {
 
 	return floatVal, nil
 }
+
+// ParseEnvDuration parses the env var as a time.Duration, e.g. 30s or 5m. It returns defaultValue if the
+// env var is not set.
+func ParseEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultValue, nil
+	}
+
+	durationVal, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse env var %s as duration: %w", key, err)
+	}
+
+	return durationVal, nil
+}
+
+// ParseEnvURL parses the env var, or defaultValue if it is not set, as an absolute URL such as
+// https://example.com or redis://localhost:6379/0. It returns nil if neither is set, and an error
+// wrapping ErrInvalidURL if the value cannot be parsed or has no scheme.
+func ParseEnvURL(key string, defaultValue string) (*url.URL, error) {
+	val := ParseEnvString(key, defaultValue)
+	if val == "" {
+		return nil, nil //nolint: nilnil
+	}
+
+	parsed, err := url.Parse(val)
+	if err != nil {
+		return nil, fmt.Errorf("%w: env var %s: %w", ErrInvalidURL, key, err)
+	}
+
+	// A value like localhost:8080 parses with localhost as the scheme and 8080 as opaque data.
+	if parsed.Scheme == "" || parsed.Opaque != "" {
+		return nil, fmt.Errorf("%w: env var %s must include a scheme, e.g. https://, got %q", ErrInvalidURL, key, val)
+	}
+
+	return parsed, nil
+}
+
+// ParseEnvStringSlice parses the env var as a comma-separated list, trimming whitespace around each
+// entry and dropping empty entries. It returns defaultValue if the env var is not set.
+func ParseEnvStringSlice(key string, defaultValue []string) []string {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultValue
+	}
+
+	return splitList(val)
+}
+
+// splitList splits a comma-separated list, trimming whitespace around each entry and dropping empty entries.
+func splitList(val string) []string {
+	values := []string{}
+
+	for _, entry := range strings.Split(val, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" {
+			values = append(values, entry)
+		}
+	}
+
+	return values
+}
+
+// RequireEnv returns an error naming every key that is not set, so that an application can fail fast
+// at startup with the full list of missing configuration.
+func RequireEnv(keys ...string) error {
+	var missing []string
+
+	for _, key := range keys {
+		if os.Getenv(key) == "" {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingEnvVar, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// MustParseEnvString returns the env var. It panics with an error wrapping ErrMissingEnvVar if it is not set.
+func MustParseEnvString(key string) string {
+	if err := RequireEnv(key); err != nil {
+		panic(err)
+	}
+
+	return os.Getenv(key)
+}
+
+// MustParseEnvInt returns the env var parsed as an int. It panics if the env var is not set or is invalid.
+func MustParseEnvInt(key string) int {
+	intVal, err := strconv.Atoi(MustParseEnvString(key))
+	if err != nil {
+		panic(fmt.Errorf("failed to parse env var %s as int: %w", key, err))
+	}
+
+	return intVal
+}
+
+// MustParseEnvFloat64 returns the env var parsed as a float64. It panics if the env var is not set or is invalid.
+func MustParseEnvFloat64(key string) float64 {
+	floatVal, err := strconv.ParseFloat(MustParseEnvString(key), 64)
+	if err != nil {
+		panic(fmt.Errorf("failed to parse env var %s as float64: %w", key, err))
+	}
+
+	return floatVal
+}
+
+// MustParseEnvDuration returns the env var parsed as a time.Duration. It panics if the env var is not set
+// or is invalid.
+func MustParseEnvDuration(key string) time.Duration {
+	durationVal, err := time.ParseDuration(MustParseEnvString(key))
+	if err != nil {
+		panic(fmt.Errorf("failed to parse env var %s as duration: %w", key, err))
+	}
+
+	return durationVal
+}
+
+// MustParseEnvBool returns true if the env var is "true". It panics if the env var is not set.
+func MustParseEnvBool(key string) bool {
+	return MustParseEnvString(key) == "true"
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"slices"
+)
+
+const (
+	// multipartMaxMemory is how much of a multipart form is held in memory before files are written to disk.
+	multipartMaxMemory = 10 << 20
+	// multipartOverheadBytes allows for the boundaries, part headers and other form fields around the file.
+	multipartOverheadBytes = 64 << 10
+	// sniffLen is the number of bytes http.DetectContentType considers.
+	sniffLen = 512
+)
+
+var (
+	// ErrInvalidMultipartForm is returned when the request body is not a valid multipart form.
+	ErrInvalidMultipartForm = errors.New("invalid multipart form")
+	// ErrMissingFile is returned when the multipart form does not contain the requested file field.
+	ErrMissingFile = errors.New("missing file")
+	// ErrFileTooLarge is returned when the uploaded file exceeds the size limit.
+	ErrFileTooLarge = errors.New("file too large")
+	// ErrUnsupportedFileType is returned when the uploaded file's content type is not allowed.
+	ErrUnsupportedFileType = errors.New("unsupported file type")
+)
+
+// MultipartFile is a file uploaded in a multipart form. Callers must Close it to release the file and remove
+// any temporary files created while parsing the form.
+type MultipartFile struct {
+	io.Reader
+	// Header holds the filename, part headers and size of the file.
+	Header *multipart.FileHeader
+	// ContentType is the content type detected from the file's contents. The client-supplied Content-Type
+	// is not trusted.
+	ContentType string
+
+	file multipart.File
+	form *multipart.Form
+}
+
+// Close closes the file and removes any temporary files created for the form.
+func (f *MultipartFile) Close() error {
+	return errors.Join(f.file.Close(), f.form.RemoveAll())
+}
+
+// ReadMultipartFile parses a multipart/form-data request and returns the file uploaded in field. Files
+// larger than maxBytes are rejected with ErrFileTooLarge. If allowedContentTypes is not empty, files whose
+// detected content type is not in the list are rejected with ErrUnsupportedFileType.
+func ReadMultipartFile(
+	r *http.Request, field string, maxBytes int64, allowedContentTypes ...string,
+) (*MultipartFile, error) {
+	r.Body = http.MaxBytesReader(nil, r.Body, maxBytes+multipartOverheadBytes)
+
+	if err := r.ParseMultipartForm(multipartMaxMemory); err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return nil, fmt.Errorf("%w: must not be larger than %d bytes", ErrFileTooLarge, maxBytes)
+		}
+
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMultipartForm, err)
+	}
+
+	form := r.MultipartForm
+
+	upload, err := openMultipartFile(form, field, maxBytes, allowedContentTypes)
+	if err != nil {
+		return nil, errors.Join(err, form.RemoveAll())
+	}
+
+	return upload, nil
+}
+
+func openMultipartFile(
+	form *multipart.Form, field string, maxBytes int64, allowedContentTypes []string,
+) (*MultipartFile, error) {
+	headers := form.File[field]
+	if len(headers) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMissingFile, field)
+	}
+
+	header := headers[0]
+	if header.Size > maxBytes {
+		return nil, fmt.Errorf("%w: must not be larger than %d bytes", ErrFileTooLarge, maxBytes)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+
+	sniff := make([]byte, sniffLen)
+
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, errors.Join(fmt.Errorf("failed to read uploaded file: %w", err), file.Close())
+	}
+
+	contentType := http.DetectContentType(sniff[:n])
+	if len(allowedContentTypes) > 0 && !slices.Contains(allowedContentTypes, contentType) {
+		return nil, errors.Join(fmt.Errorf("%w: %s", ErrUnsupportedFileType, contentType), file.Close())
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to rewind uploaded file: %w", err), file.Close())
+	}
+
+	return &MultipartFile{
+		Reader:      file,
+		Header:      header,
+		ContentType: contentType,
+		file:        file,
+		form:        form,
+	}, nil
+}
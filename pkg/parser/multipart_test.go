@@ -0,0 +1,145 @@
+package parser_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+)
+
+var pngBytes = append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 100)...)
+
+func newMultipartRequest(t *testing.T, field, filename string, contents []byte) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+
+	if err := writer.WriteField("name", "Acme"); err != nil {
+		t.Fatalf("failed to write field: %v", err)
+	}
+
+	part, err := writer.CreateFormFile(field, filename)
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+
+	if _, err := part.Write(contents); err != nil {
+		t.Fatalf("failed to write form file: %v", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close multipart writer: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/tenants/1/logo", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req
+}
+
+func TestReadMultipartFile(t *testing.T) {
+	t.Parallel()
+
+	req := newMultipartRequest(t, "logo", "logo.png", pngBytes)
+
+	file, err := parser.ReadMultipartFile(req, "logo", 1024, "image/png", "image/jpeg")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	defer func() {
+		if err := file.Close(); err != nil {
+			t.Errorf("failed to close file: %v", err)
+		}
+	}()
+
+	if file.Header.Filename != "logo.png" || file.ContentType != "image/png" {
+		t.Errorf("unexpected file %q with content type %q", file.Header.Filename, file.ContentType)
+	}
+
+	contents, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	if !bytes.Equal(contents, pngBytes) {
+		t.Errorf("expected the full file contents, got %d bytes", len(contents))
+	}
+
+	if req.FormValue("name") != "Acme" {
+		t.Errorf("expected other form fields to be parsed, got %q", req.FormValue("name"))
+	}
+}
+
+func TestReadMultipartFileErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		req           func(t *testing.T) *http.Request
+		maxBytes      int64
+		expectedError error
+	}{
+		{
+			name:          "file larger than limit",
+			req:           func(t *testing.T) *http.Request { return newMultipartRequest(t, "logo", "logo.png", pngBytes) },
+			maxBytes:      64,
+			expectedError: parser.ErrFileTooLarge,
+		},
+		{
+			name: "body larger than limit",
+			req: func(t *testing.T) *http.Request {
+				return newMultipartRequest(t, "logo", "logo.png", append(pngBytes, make([]byte, 200<<10)...))
+			},
+			maxBytes:      1024,
+			expectedError: parser.ErrFileTooLarge,
+		},
+		{
+			name: "wrong content type",
+			req: func(t *testing.T) *http.Request {
+				return newMultipartRequest(t, "logo", "logo.png", []byte("<html><script>alert(1)</script></html>"))
+			},
+			maxBytes:      1024,
+			expectedError: parser.ErrUnsupportedFileType,
+		},
+		{
+			name:          "missing field",
+			req:           func(t *testing.T) *http.Request { return newMultipartRequest(t, "avatar", "logo.png", pngBytes) },
+			maxBytes:      1024,
+			expectedError: parser.ErrMissingFile,
+		},
+		{
+			name: "not multipart",
+			req: func(*testing.T) *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/tenants/1/logo", bytes.NewBufferString(`{}`))
+				req.Header.Set("Content-Type", "application/json")
+
+				return req
+			},
+			maxBytes:      1024,
+			expectedError: parser.ErrInvalidMultipartForm,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := parser.ReadMultipartFile(tt.req(t), "logo", tt.maxBytes, "image/png")
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected %v, got %v", tt.expectedError, err)
+			}
+
+			if file != nil {
+				t.Error("expected no file to be returned")
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+// BindQuery populates the struct pointed to by dst from the request's query string and returns a Validator
+// holding an error for every parameter that could not be converted to its field's type. Each field tagged with
+// query:"name" is set from the name parameter, or from its default:"..." tag if the parameter is missing or
+// empty. Untagged struct fields, such as an embedded Filters, are bound recursively.
+//
+// Supported field types are those supported by LoadConfig. Pointer fields are left nil if the parameter is
+// missing, so optional filters can be told apart from zero values, and slice fields are set from repeated
+// parameters, e.g. ?plan=free&plan=paid, or from a comma-separated default. The returned Validator can be used
+// to validate the bound values further before responding with its errors:
+//
+//	type SearchTenantsRequest struct {
+//		TenantName *string  `query:"tenantName"`
+//		Plans      []string `query:"plan"`
+//		IsActive   *bool    `query:"isActive"`
+//		Page       int      `query:"page" default:"1"`
+//	}
+//
+//	var req SearchTenantsRequest
+//	v := parser.BindQuery(r, &req)
+//	v.Check(req.Page > 0, "page", "must be greater than zero")
+//
+// BindQuery panics if dst is not a non-nil pointer to a struct, since that is a programming error.
+func BindQuery(r *http.Request, dst any) *validation.Validator {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("parser: BindQuery expects a non-nil pointer to a struct, got %T", dst))
+	}
+
+	v := validation.NewValidator()
+	bindQueryStruct(v, r.URL.Query(), rv.Elem())
+
+	return v
+}
+
+func bindQueryStruct(v *validation.Validator, queryValues url.Values, rv reflect.Value) {
+	rt := rv.Type()
+
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key, ok := field.Tag.Lookup("query")
+		if !ok {
+			if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+				bindQueryStruct(v, queryValues, rv.Field(i))
+			}
+
+			continue
+		}
+
+		values := queryParamValues(queryValues, key)
+		if len(values) == 0 {
+			defaultValue, ok := field.Tag.Lookup("default")
+			if !ok {
+				continue
+			}
+
+			values = []string{defaultValue}
+			if field.Type.Kind() == reflect.Slice {
+				values = splitList(defaultValue)
+			}
+		}
+
+		if err := setQueryField(rv.Field(i), values); err != nil {
+			v.AddError(key, queryErrorMessage(rv.Field(i).Type()))
+		}
+	}
+}
+
+// queryParamValues returns the trimmed, non-empty values of the key parameter.
+func queryParamValues(queryValues url.Values, key string) []string {
+	values := make([]string, 0, len(queryValues[key]))
+
+	for _, val := range queryValues[key] {
+		if val = strings.TrimSpace(val); val != "" {
+			values = append(values, val)
+		}
+	}
+
+	return values
+}
+
+func setQueryField(field reflect.Value, values []string) error {
+	switch field.Kind() { //nolint: exhaustive
+	case reflect.Pointer:
+		elem := reflect.New(field.Type().Elem())
+		if err := setQueryField(elem.Elem(), values); err != nil {
+			return err
+		}
+
+		field.Set(elem)
+	case reflect.Slice:
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, val := range values {
+			if err := setField(slice.Index(i), val); err != nil {
+				return err
+			}
+		}
+
+		field.Set(slice)
+	default:
+		return setField(field, values[0])
+	}
+
+	return nil
+}
+
+// queryErrorMessage describes the values a field of type t accepts.
+func queryErrorMessage(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		return "must be a duration"
+	}
+
+	switch t.Kind() { //nolint: exhaustive
+	case reflect.Bool:
+		return "must be true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "must be an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "must be a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "must be a number"
+	default:
+		return "is invalid"
+	}
+}
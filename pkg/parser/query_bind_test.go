@@ -0,0 +1,129 @@
+package parser_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+type BindQueryPagination struct {
+	Page     int `query:"page" default:"1"`
+	PageSize int `query:"pageSize" default:"25"`
+}
+
+type bindQueryRequest struct {
+	TenantName *string  `query:"tenantName"`
+	Plans      []string `query:"plan" default:"free,paid"`
+	IDs        []int64  `query:"id"`
+	IsActive   *bool    `query:"isActive"`
+	Verbose    bool     `query:"verbose"`
+	MinScore   float64  `query:"minScore"`
+	BindQueryPagination
+}
+
+func TestBindQuery(t *testing.T) {
+	t.Parallel()
+
+	tenantName := "Acme"
+	isActive := true
+
+	tests := []struct {
+		name     string
+		query    string
+		expected bindQueryRequest
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			expected: bindQueryRequest{
+				Plans:               []string{"free", "paid"},
+				BindQueryPagination: BindQueryPagination{Page: 1, PageSize: 25},
+			},
+		},
+		{
+			name:  "ints and bools",
+			query: "page=3&pageSize=50&isActive=true&verbose=1&minScore=2.5",
+			expected: bindQueryRequest{
+				Plans:               []string{"free", "paid"},
+				IsActive:            &isActive,
+				Verbose:             true,
+				MinScore:            2.5,
+				BindQueryPagination: BindQueryPagination{Page: 3, PageSize: 50},
+			},
+		},
+		{
+			name:  "slices from repeated params",
+			query: "plan=paid&plan=enterprise&id=3&id=1&id=2&tenantName=+Acme+",
+			expected: bindQueryRequest{
+				TenantName:          &tenantName,
+				Plans:               []string{"paid", "enterprise"},
+				IDs:                 []int64{3, 1, 2},
+				BindQueryPagination: BindQueryPagination{Page: 1, PageSize: 25},
+			},
+		},
+		{
+			name:  "empty values use defaults",
+			query: "page=&plan=&tenantName=",
+			expected: bindQueryRequest{
+				Plans:               []string{"free", "paid"},
+				BindQueryPagination: BindQueryPagination{Page: 1, PageSize: 25},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var req bindQueryRequest
+
+			v := parser.BindQuery(httptest.NewRequest(http.MethodGet, "/tenants?"+tt.query, nil), &req)
+			if v.HasErrors() {
+				t.Fatalf("expected no errors, got %+v", v.Errors)
+			}
+
+			if !reflect.DeepEqual(req, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, req)
+			}
+		})
+	}
+}
+
+func TestBindQuery_InvalidValues(t *testing.T) {
+	t.Parallel()
+
+	var req bindQueryRequest
+
+	r := httptest.NewRequest(http.MethodGet, "/tenants?page=abc&isActive=maybe&id=1&id=x&minScore=high", nil)
+	v := parser.BindQuery(r, &req)
+
+	expected := []validation.Error{
+		{Field: "id", Message: "must be an integer"},
+		{Field: "isActive", Message: "must be true or false"},
+		{Field: "minScore", Message: "must be a number"},
+		{Field: "page", Message: "must be an integer"},
+	}
+	if !reflect.DeepEqual(v.Errors, expected) {
+		t.Errorf("expected errors %+v, got %+v", expected, v.Errors)
+	}
+
+	if req.IDs != nil || req.IsActive != nil {
+		t.Errorf("expected invalid values to leave fields unset, got %+v", req)
+	}
+}
+
+func TestBindQuery_InvalidTarget(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected BindQuery to panic when dst is not a pointer to a struct")
+		}
+	}()
+
+	parser.BindQuery(httptest.NewRequest(http.MethodGet, "/", nil), bindQueryRequest{})
+}
@@ -15,6 +15,8 @@ type Filters struct {
 	Page     int
 	PageSize int
 	Sort     string
+	// SortSpec is Sort validated against the sort safelist passed to ParseQSFilters.
+	SortSpec SortSpec
 }
 
 // PaginationMetadata contains metadata about the current page of paginated data.
@@ -54,7 +56,8 @@ const (
 	pageSizeKey = "pageSize"
 )
 
-// ParseFilters parses the query string parameters and populates the Filters struct.
+// ParseQSFilters parses the query string parameters and populates the Filters struct. sortSafeList is the
+// list of columns that may be sorted by; each may be prefixed with - in the query string to sort descending.
 func (f *Filters) ParseQSFilters(queryValues url.Values, v *validation.Validator, sortSafeList []string) {
 	defaultPage := 1
 
@@ -89,7 +92,7 @@ func (f *Filters) ParseQSFilters(queryValues url.Values, v *validation.Validator
 }
 
 // Validate checks that the page and page_size parameters contain sensible values and
-// that the sort parameter matches a value in the safelist.
+// that the sort parameter names a column in the safelist.
 func (f *Filters) validate(v *validation.Validator, sortSafeList []string) {
 	const (
 		maxPageNumber = 10_000_000
@@ -100,8 +103,15 @@ func (f *Filters) validate(v *validation.Validator, sortSafeList []string) {
 	v.Check(f.Page <= maxPageNumber, pageKey, "must be a maximum of 10 million")
 	v.Check(f.PageSize > 0, pageSizeKey, "must be greater than zero")
 	v.Check(f.PageSize <= maxPageSize, pageSizeKey, "must be a maximum of 100")
-	// Check that the sort parameter matches a value in the safelist.
-	v.In(f.Sort, sortSafeList, sortKey, "invalid sort value")
+	// Check that the sort parameter names a column in the safelist.
+	sortSpec, err := ParseSort(f.Sort, sortSafeList)
+	if err != nil {
+		v.AddError(sortKey, "invalid sort value")
+
+		return
+	}
+
+	f.SortSpec = sortSpec
 }
 
 // ParseQSString returns a string value from the query string or the provided
@@ -76,11 +76,15 @@ func TestFilters_ParseFilters(t *testing.T) {
 		qs       url.Values
 		expected parser.Filters
 	}{
-		{"default values", url.Values{}, parser.Filters{Page: 1, PageSize: 25, Sort: "id"}},
+		{
+			"default values",
+			url.Values{},
+			parser.Filters{Page: 1, PageSize: 25, Sort: "id", SortSpec: parser.SortSpec{Column: "id", Descending: false}},
+		},
 		{
 			"custom values",
-			url.Values{"page": {"2"}, "pageSize": {"20"}, "sort": {"name"}},
-			parser.Filters{Page: 2, PageSize: 20, Sort: "name"},
+			url.Values{"page": {"2"}, "pageSize": {"20"}, "sort": {"-name"}},
+			parser.Filters{Page: 2, PageSize: 20, Sort: "-name", SortSpec: parser.SortSpec{Column: "name", Descending: true}},
 		},
 	}
 
@@ -98,7 +102,7 @@ func TestFilters_ParseFilters(t *testing.T) {
 				t.Errorf("unexpected error: %v", v.Errors)
 			}
 
-			if filters.Page != tt.expected.Page || filters.PageSize != tt.expected.PageSize || filters.Sort != tt.expected.Sort {
+			if filters != tt.expected {
 				t.Errorf("expected %+v, got %+v", tt.expected, filters)
 			}
 		})
@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// fileEnvSuffix is appended to a key to name the env var holding the path of a file containing its value.
+const fileEnvSuffix = "_FILE"
+
+// ParseEnvStringOrFile returns the value of a secret following the _FILE convention used for Docker and
+// Kubernetes secrets. If KEY_FILE is set, the secret is read from the file at that path with trailing newlines
+// removed. Otherwise, KEY is returned, or defaultValue if it is not set either. KEY_FILE takes precedence.
+func ParseEnvStringOrFile(key string, defaultValue string) (string, error) {
+	path := os.Getenv(key + fileEnvSuffix)
+	if path == "" {
+		return ParseEnvString(key, defaultValue), nil
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read env var %s from file: %w", key, err)
+	}
+
+	return strings.TrimRight(string(contents), "\r\n"), nil
+}
+
+// MustParseEnvStringOrFile returns the value of a secret from the KEY_FILE file or the KEY env var like
+// ParseEnvStringOrFile. It panics if neither is set, the file cannot be read, or the secret is empty.
+func MustParseEnvStringOrFile(key string) string {
+	val, err := ParseEnvStringOrFile(key, "")
+	if err != nil {
+		panic(err)
+	}
+
+	if val == "" {
+		panic(fmt.Errorf("%w: %s or %s%s", ErrMissingEnvVar, key, key, fileEnvSuffix))
+	}
+
+	return val
+}
@@ -0,0 +1,72 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+)
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestParseEnvStringOrFile(t *testing.T) {
+	secretPath := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretPath, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		value       string
+		file        string
+		expected    string
+		expectError bool
+	}{
+		{name: "default", expected: "default"},
+		{name: "env var", value: "from-env", expected: "from-env"},
+		{name: "file", file: secretPath, expected: "from-file"},
+		{name: "file takes precedence", value: "from-env", file: secretPath, expected: "from-file"},
+		{name: "missing file", value: "from-env", file: secretPath + ".missing", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_SECRET", tt.value)
+			t.Setenv("TEST_SECRET_FILE", tt.file)
+
+			got, err := parser.ParseEnvStringOrFile("TEST_SECRET", "default")
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected an error, got %q", got)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestMustParseEnvStringOrFileMissing(t *testing.T) {
+	t.Setenv("TEST_SECRET", "")
+	t.Setenv("TEST_SECRET_FILE", "")
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !strings.Contains(err.Error(), "missing required env var: TEST_SECRET or TEST_SECRET_FILE") {
+			t.Errorf("expected a missing env var panic, got %v", err)
+		}
+	}()
+
+	parser.MustParseEnvStringOrFile("TEST_SECRET")
+}
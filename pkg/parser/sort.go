@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidSort is returned when a sort value names a column that is not in the allowlist.
+var ErrInvalidSort = errors.New("invalid sort value")
+
+// SortSpec is a validated sort column and direction. Since the column is checked against an allowlist it is
+// safe to use in an ORDER BY clause, e.g. with dbutils.QueryBuilder.OrderBySpec.
+type SortSpec struct {
+	Column     string
+	Descending bool
+}
+
+// String returns the spec in sort query parameter form, e.g. -name for name descending.
+func (s SortSpec) String() string {
+	if s.Descending {
+		return "-" + s.Column
+	}
+
+	return s.Column
+}
+
+// ParseSort parses a sort value such as name or -name, where a leading - sorts in descending order. The
+// column must be one of allowedColumns; a leading - on an allowed column is ignored so that safelists
+// listing both directions keep working.
+func ParseSort(value string, allowedColumns []string) (SortSpec, error) {
+	column, descending := strings.CutPrefix(strings.TrimSpace(value), "-")
+
+	allowed := slices.ContainsFunc(allowedColumns, func(allowedColumn string) bool {
+		return strings.TrimPrefix(allowedColumn, "-") == column
+	})
+	if column == "" || !allowed {
+		return SortSpec{}, fmt.Errorf("%w: %q", ErrInvalidSort, value) //nolint: exhaustruct
+	}
+
+	return SortSpec{Column: column, Descending: descending}, nil
+}
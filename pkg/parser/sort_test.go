@@ -0,0 +1,66 @@
+package parser_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/parser"
+)
+
+func TestParseSort(t *testing.T) {
+	t.Parallel()
+
+	allowedColumns := []string{"id", "name", "-createdAt"}
+
+	tests := []struct {
+		name     string
+		value    string
+		expected parser.SortSpec
+		wantErr  bool
+	}{
+		{"ascending", "name", parser.SortSpec{Column: "name", Descending: false}, false},
+		{"descending", "-name", parser.SortSpec{Column: "name", Descending: true}, false},
+		{"safelist entry with direction", "createdAt", parser.SortSpec{Column: "createdAt", Descending: false}, false},
+		{"surrounding whitespace", " -id ", parser.SortSpec{Column: "id", Descending: true}, false},
+		{"disallowed column", "email", parser.SortSpec{}, true},
+		{"injection", "id; DROP TABLE users", parser.SortSpec{}, true},
+		{"double minus", "--id", parser.SortSpec{}, true},
+		{"only minus", "-", parser.SortSpec{}, true},
+		{"empty", "", parser.SortSpec{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			spec, err := parser.ParseSort(tt.value, allowedColumns)
+			if tt.wantErr {
+				if !errors.Is(err, parser.ErrInvalidSort) {
+					t.Errorf("expected ErrInvalidSort, got %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if spec != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, spec)
+			}
+		})
+	}
+}
+
+func TestSortSpecString(t *testing.T) {
+	t.Parallel()
+
+	if s := (parser.SortSpec{Column: "name", Descending: false}).String(); s != "name" {
+		t.Errorf("expected name, got %q", s)
+	}
+
+	if s := (parser.SortSpec{Column: "name", Descending: true}).String(); s != "-name" {
+		t.Errorf("expected -name, got %q", s)
+	}
+}
@@ -5,17 +5,23 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log/slog"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/gurch101/gowebutils/pkg/authutils"
+	"github.com/gurch101/gowebutils/pkg/dbutils"
 	"github.com/gurch101/gowebutils/pkg/httputils"
 	"github.com/gurch101/gowebutils/pkg/parser"
+	"github.com/gurch101/gowebutils/pkg/threads"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
-const compressionLevel = 5
+// defaultMetricsAddr only accepts connections from the same host, so metrics are not exposed by default.
+const defaultMetricsAddr = "localhost:9090"
 
 type Routable interface {
 	PublicRoutes(r httputils.Router)
@@ -27,20 +33,61 @@ type AuthService[T any] interface {
 	GetUserExists(ctx context.Context, user T) bool
 }
 
-func CreateAppServer[T any](authService AuthService[T], db *sql.DB, routables ...Routable) error {
-	logger := httputils.InitializeSlog(parser.ParseEnvString("LOG_LEVEL", "info"))
+// CreateAppServer serves routables until the process is signalled to stop. workers, e.g. the mailer's pool from
+// mailutils.Emailer.Workers, is drained before it returns so that background jobs are not dropped; it may be nil.
+func CreateAppServer[T any](
+	authService AuthService[T], db *sql.DB, workers *threads.Pool, routables ...Routable,
+) error {
+	logger := httputils.SetupLogger()
+	// Resolve the encryption key up front so that a missing or invalid key stops the server from starting.
+	encryptionKey := authutils.MustGetEncryptionKey()
+	dbutils.SetQueryLogger(dbutils.GetQueryLogger(logger))
 
 	sessionManager := authutils.CreateSessionManager(db)
 	sessionMiddleware := authutils.GetSessionMiddleware(sessionManager, authService.GetUserExists)
-	router := chi.NewRouter()
-	router.Use(middleware.RealIP)
-	router.Use(middleware.RequestID)
-	router.Use(httputils.RateLimitMiddleware)
-	router.Use(middleware.RequestLogger(httputils.NewSlogLogFormatter(slog.Default())))
-	router.Use(middleware.Recoverer)
-	router.Use(middleware.Compress(compressionLevel))
+	router := httputils.NewRouter()
+
+	// metricsRegisterer is only set when metrics are enabled, since a nil *prometheus.Registry would be a
+	// non-nil Registerer.
+	var (
+		metricsRegistry   *prometheus.Registry
+		metricsRegisterer prometheus.Registerer
+	)
+
+	if parser.ParseEnvBool("METRICS_ENABLED", false) {
+		metricsRegistry = prometheus.NewRegistry()
+		metricsRegisterer = metricsRegistry
+		dbutils.SetQueryMetrics(dbutils.NewQueryMetrics(metricsRegistry))
+	}
+
+	// Spans are created with the global tracer provider, which the application sets up with its exporter
+	// before starting the server, e.g. with otel.SetTracerProvider.
+	var tracerProvider trace.TracerProvider
+	if parser.ParseEnvBool("TRACING_ENABLED", false) {
+		tracerProvider = otel.GetTracerProvider()
+	}
+
+	standardMiddleware, err := httputils.StandardMiddleware(httputils.MiddlewareConfig{
+		Logger:            logger,
+		AccessLogWriter:   nil,
+		AllowedOrigins:    parser.ParseEnvStringSlice("CORS_ALLOWED_ORIGINS", nil),
+		MetricsRegisterer: metricsRegisterer,
+		TracerProvider:    tracerProvider,
+		SecureHeaders:     nil,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid middleware config: %w", err)
+	}
+
+	router.Use(standardMiddleware...)
 	router.Use(sessionManager.LoadAndSave)
 
+	router.Get("/healthz", httputils.LivenessHandler())
+	router.Get("/readyz", httputils.HealthHandler(httputils.HealthCheck{
+		Name:  "database",
+		Check: func(ctx context.Context) error { return dbutils.HealthCheck(ctx, db) },
+	}))
+
 	for _, routable := range routables {
 		routable.PublicRoutes(router)
 	}
@@ -49,19 +96,47 @@ func CreateAppServer[T any](authService AuthService[T], db *sql.DB, routables ..
 		r.Use(middleware.NoCache)
 		r.Use(sessionMiddleware)
 
+		if parser.ParseEnvBool("CSRF_ENABLED", false) {
+			r.Use(httputils.CSRFMiddleware(httputils.CSRFConfig{})) //nolint: exhaustruct
+		}
+
 		for _, routable := range routables {
 			routable.ProtectedRoutes(r)
 		}
 	})
 
-	oidcController := authutils.CreateOidcController(sessionManager, authService.GetOrCreateUser)
+	oidcController := authutils.CreateOidcController(sessionManager, authService.GetOrCreateUser, encryptionKey)
 	oidcController.PublicRoutes(router)
 	oidcController.ProtectedRoutes(router)
 
 	fileServer := http.FileServer(http.Dir("./web/static/"))
 	router.Handle("/static/*", http.StripPrefix("/static", fileServer))
 
-	err := httputils.ServeHTTP(router, logger)
+	if metricsRegistry != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Metrics are served on their own listener, which should not be exposed publicly, rather than on the
+		// application's listener where anyone could read them.
+		metricsAddr := parser.ParseEnvString("METRICS_ADDR", defaultMetricsAddr)
+
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("GET /metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})) //nolint: exhaustruct
+
+		go func() {
+			//nolint: exhaustruct
+			err := httputils.Serve(metricsMux, httputils.ServerConfig{
+				Addr:    metricsAddr,
+				Logger:  logger,
+				Context: ctx,
+			})
+			if err != nil {
+				logger.Error("metrics server error", "error", err)
+			}
+		}()
+	}
+
+	err = httputils.ServeHTTP(router, logger, workers)
 	if err != nil {
 		return fmt.Errorf("failed to start server: %w", err)
 	}
@@ -0,0 +1,14 @@
+package testutils
+
+import (
+	"net/http"
+
+	"github.com/gurch101/gowebutils/pkg/authutils"
+)
+
+// WithAuthUser returns a copy of req with user stored in the request context under the same key the
+// session middleware uses, so handlers that call authutils.ContextGetUser can be tested without a login flow.
+// The type of user must match the type parameter the handler passes to authutils.ContextGetUser.
+func WithAuthUser[T any](req *http.Request, user T) *http.Request {
+	return authutils.ContextSetUser(req, user)
+}
@@ -0,0 +1,34 @@
+package testutils_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/authutils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+type testUser struct {
+	ID       int64
+	TenantID int64
+}
+
+func TestWithAuthUser(t *testing.T) {
+	t.Parallel()
+
+	user := testUser{ID: 1, TenantID: 2}
+
+	var got testUser
+
+	handler := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = authutils.ContextGetUser[testUser](r)
+	})
+
+	req := testutils.WithAuthUser(testutils.CreateGetRequest("/api/me"), user)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != user {
+		t.Errorf("expected user %v; got %v", user, got)
+	}
+}
@@ -64,11 +64,14 @@ func getProjectRoot() string {
 func SetupTestDB(t *testing.T) *sql.DB {
 	t.Helper()
 
-	db, err := sql.Open(dbutils.SqliteDriverName, ":memory:")
+	db, err := dbutils.OpenWithPragmas(dbutils.SqliteDriverName, ":memory:", dbutils.DefaultPragmas...)
 	if err != nil {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
 
+	// Every connection to :memory: opens a separate empty database, so concurrent requests must share one.
+	db.SetMaxOpenConns(1)
+
 	// Apply all migrations
 	projectRoot := getProjectRoot()
 	migrationDir := filepath.Join(projectRoot, "db", "migrations")
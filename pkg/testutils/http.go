@@ -3,6 +3,7 @@ package testutils
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,14 +15,36 @@ import (
 func createRequestWithBody(t *testing.T, method, url string, payload interface{}) *http.Request {
 	t.Helper()
 
-	requestBody, err := json.Marshal(payload)
-	if err != nil {
-		t.Fatalf("Failed to marshal request body: %v", err)
+	return CreateRequest(t, method, url, payload, nil)
+}
+
+// CreateRequest creates a request with a JSON content type. If payload is not nil, it is marshalled as the
+// JSON request body. Headers are added after the content type, so they can override it.
+func CreateRequest(t *testing.T, method, url string, payload interface{}, headers http.Header) *http.Request {
+	t.Helper()
+
+	var body io.Reader
+
+	if payload != nil {
+		requestBody, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("Failed to marshal request body: %v", err)
+		}
+
+		body = bytes.NewReader(requestBody)
 	}
 
-	req := httptest.NewRequest(method, url, bytes.NewReader(requestBody))
+	req := httptest.NewRequest(method, url, body)
 	httputils.SetJSONContentTypeRequestHeader(req)
 
+	for key, values := range headers {
+		req.Header.Del(key)
+
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
 	return req
 }
 
@@ -37,6 +60,12 @@ func CreatePatchRequest(t *testing.T, url string, payload interface{}) *http.Req
 	return createRequestWithBody(t, http.MethodPatch, url, payload)
 }
 
+func CreatePutRequest(t *testing.T, url string, payload interface{}) *http.Request {
+	t.Helper()
+
+	return createRequestWithBody(t, http.MethodPut, url, payload)
+}
+
 func CreateGetRequest(url string) *http.Request {
 	req := httptest.NewRequest(http.MethodGet, url, nil)
 	httputils.SetJSONContentTypeRequestHeader(req)
@@ -51,6 +80,27 @@ func CreateDeleteRequest(url string) *http.Request {
 	return req
 }
 
+// AssertStatus fails the test if the recorded response status is not want. The response body is included
+// in the failure message to make unexpected errors easier to diagnose.
+func AssertStatus(t *testing.T, rr *httptest.ResponseRecorder, want int) {
+	t.Helper()
+
+	if rr.Code != want {
+		t.Errorf("expected status %d; got %d with body %s", want, rr.Code, rr.Body.String())
+	}
+}
+
+// AssertJSONBody unmarshals the recorded response body into dst, stopping the test if the body is not
+// valid JSON for dst.
+func AssertJSONBody(t *testing.T, rr *httptest.ResponseRecorder, dst interface{}) {
+	t.Helper()
+
+	err := json.Unmarshal(rr.Body.Bytes(), dst)
+	if err != nil {
+		t.Fatalf("failed to unmarshal response body %q into %T: %v", rr.Body.String(), dst, err)
+	}
+}
+
 func AssertError(t *testing.T, resp map[string]interface{}, expectedErrorField string, expectedErrorMessage string) {
 	t.Helper()
 
@@ -70,6 +120,53 @@ func AssertError(t *testing.T, resp map[string]interface{}, expectedErrorField s
 	}
 }
 
+// AssertErrorCount fails the test if the response does not contain exactly n errors.
+func AssertErrorCount(t *testing.T, resp map[string]interface{}, n int) {
+	t.Helper()
+
+	if errs := responseErrors(t, resp); len(errs) != n {
+		t.Errorf("expected %d errors; got %d in %v", n, len(errs), resp)
+	}
+}
+
+// AssertErrorContains fails the test if none of the errors in the response has the expected field and message.
+func AssertErrorContains(t *testing.T, resp map[string]interface{}, expectedErrorField, expectedErrorMessage string) {
+	t.Helper()
+
+	for _, err := range responseErrors(t, resp) {
+		if err["field"] == expectedErrorField && err["message"] == expectedErrorMessage {
+			return
+		}
+	}
+
+	t.Errorf("expected error %s: %s; got %v", expectedErrorField, expectedErrorMessage, resp)
+}
+
+// responseErrors returns the field errors of a validation error response, stopping the test if the
+// response does not have the {"errors": [{"field": ..., "message": ...}]} shape.
+func responseErrors(t *testing.T, resp map[string]interface{}) []map[string]interface{} {
+	t.Helper()
+
+	list, ok := resp["errors"].([]interface{})
+	if !ok {
+		t.Fatalf("expected errors list; got %v", resp)
+	}
+
+	errs := make([]map[string]interface{}, 0, len(list))
+
+	for _, item := range list {
+		err, ok := item.(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected field error; got %v in %v", item, resp)
+		}
+
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// NewRouter creates a router configured like the starter server's, with JSON 404 and 405 responses.
 func NewRouter() *chi.Mux {
-	return chi.NewRouter()
+	return httputils.NewRouter()
 }
@@ -0,0 +1,73 @@
+package testutils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func TestCreateRequest(t *testing.T) {
+	t.Parallel()
+
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer token")
+	headers.Set("X-Request-Id", "abc-123")
+
+	req := testutils.CreateRequest(t, http.MethodPut, "/tenants/1", map[string]string{"plan": "paid"}, headers)
+
+	if req.Method != http.MethodPut {
+		t.Errorf("expected method %s; got %s", http.MethodPut, req.Method)
+	}
+
+	for _, header := range []string{"Authorization", "X-Request-Id"} {
+		if req.Header.Get(header) != headers.Get(header) {
+			t.Errorf("expected %s header %q; got %q", header, headers.Get(header), req.Header.Get(header))
+		}
+	}
+
+	if req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("expected JSON content type; got %q", req.Header.Get("Content-Type"))
+	}
+
+	var body map[string]string
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+
+	if body["plan"] != "paid" {
+		t.Errorf("expected plan paid; got %v", body)
+	}
+}
+
+func TestCreateRequestWithoutPayload(t *testing.T) {
+	t.Parallel()
+
+	headers := http.Header{}
+	headers.Set("Content-Type", "text/plain")
+
+	req := testutils.CreateRequest(t, http.MethodGet, "/tenants", nil, headers)
+
+	if req.ContentLength != 0 {
+		t.Errorf("expected empty body; got content length %d", req.ContentLength)
+	}
+
+	if req.Header.Get("Content-Type") != "text/plain" {
+		t.Errorf("expected overridden content type; got %q", req.Header.Get("Content-Type"))
+	}
+}
+
+func TestCreatePutRequest(t *testing.T) {
+	t.Parallel()
+
+	req := testutils.CreatePutRequest(t, "/tenants/1", map[string]string{"plan": "paid"})
+
+	if req.Method != http.MethodPut {
+		t.Errorf("expected method %s; got %s", http.MethodPut, req.Method)
+	}
+
+	if req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("expected JSON content type; got %q", req.Header.Get("Content-Type"))
+	}
+}
@@ -1,20 +1,63 @@
 package testutils
 
+import (
+	"html/template"
+	"sync"
+
+	"github.com/gurch101/gowebutils/pkg/mailutils"
+)
+
+// MockMailer is a mailutils.Mailer that records emails instead of sending them. It is safe to use from
+// background jobs.
 type MockMailer struct {
 	SentEmails []map[string]any
+	templates  map[string]*template.Template
+	messages   []mailutils.Message
+	mu         sync.Mutex
 }
 
 func NewMockMailer() *MockMailer {
+	return NewMockMailerWithTemplates(nil)
+}
+
+// NewMockMailerWithTemplates creates a MockMailer that renders each email with templates, as the SMTP mailer
+// does, so that tests can assert on the rendered subject and bodies with Messages.
+func NewMockMailerWithTemplates(templates map[string]*template.Template) *MockMailer {
 	return &MockMailer{
 		SentEmails: []map[string]any{},
+		templates:  templates,
+		messages:   []mailutils.Message{},
+		mu:         sync.Mutex{},
 	}
 }
 
 func (m *MockMailer) Send(recipient, templateName string, data map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	email := map[string]any{
 		"recipient":    recipient,
 		"templateName": templateName,
 		"data":         data,
 	}
 	m.SentEmails = append(m.SentEmails, email)
+
+	if m.templates == nil {
+		return
+	}
+
+	message, err := mailutils.RenderMessage(m.templates, recipient, templateName, data)
+	if err != nil {
+		panic(err)
+	}
+
+	m.messages = append(m.messages, *message)
+}
+
+// Messages returns the emails rendered by a MockMailer created with NewMockMailerWithTemplates.
+func (m *MockMailer) Messages() []mailutils.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]mailutils.Message(nil), m.messages...)
 }
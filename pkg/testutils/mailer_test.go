@@ -0,0 +1,59 @@
+package testutils_test
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/mailutils"
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func TestMockMailerRecordsMessages(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("welcome.go.tmpl").Parse(
+		`{{define "subject"}}Welcome to {{.Tenant}}{{end}}` +
+			`{{define "plainBody"}}Hi {{.Name}}{{end}}` +
+			`{{define "htmlBody"}}<p>Hi {{.Name}}</p>{{end}}`,
+	))
+
+	mailer := testutils.NewMockMailerWithTemplates(map[string]*template.Template{"welcome.go.tmpl": tmpl})
+
+	var _ mailutils.Mailer = mailer
+
+	mailer.Send("admin@acme.com", "welcome.go.tmpl", map[string]string{"Tenant": "Acme", "Name": "Admin"})
+
+	messages := mailer.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(messages))
+	}
+
+	expected := mailutils.Message{
+		Recipient: "admin@acme.com",
+		Subject:   "Welcome to Acme",
+		PlainBody: "Hi Admin",
+		HTMLBody:  "<p>Hi Admin</p>",
+	}
+	if messages[0] != expected {
+		t.Errorf("expected %+v, got %+v", expected, messages[0])
+	}
+
+	if len(mailer.SentEmails) != 1 || mailer.SentEmails[0]["templateName"] != "welcome.go.tmpl" {
+		t.Errorf("expected sent email to be recorded, got %v", mailer.SentEmails)
+	}
+}
+
+func TestMockMailerWithoutTemplates(t *testing.T) {
+	t.Parallel()
+
+	mailer := testutils.NewMockMailer()
+	mailer.Send("john@acme.com", "invite.go.tmpl", map[string]string{"URL": "https://example.com"})
+
+	if len(mailer.SentEmails) != 1 || mailer.SentEmails[0]["recipient"] != "john@acme.com" {
+		t.Errorf("expected sent email to be recorded, got %v", mailer.SentEmails)
+	}
+
+	if len(mailer.Messages()) != 0 {
+		t.Errorf("expected no rendered messages without templates, got %v", mailer.Messages())
+	}
+}
@@ -0,0 +1,22 @@
+package testutils
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gurch101/gowebutils/pkg/httputils"
+)
+
+// NewTestServer starts an httptest.Server that serves handler behind httputils.StandardMiddleware, so
+// integration tests exercise the same logging, recovery, rate limiting and timeout behaviour as the
+// starter server. Rate limits and timeouts are read from env when the server is created, so tests can
+// tune them with t.Setenv. Like httptest.NewServer, it panics if the server cannot be created, e.g. because
+// the rate limit env config is invalid. The caller must call Close on the returned server.
+func NewTestServer(handler http.Handler) *httptest.Server {
+	stack, err := httputils.StandardMiddleware(httputils.MiddlewareConfig{}) //nolint: exhaustruct
+	if err != nil {
+		panic(err)
+	}
+
+	return httptest.NewServer(stack.Handler(handler))
+}
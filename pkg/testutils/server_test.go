@@ -0,0 +1,44 @@
+package testutils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/testutils"
+)
+
+func TestNewTestServerRecoversFromPanics(t *testing.T) {
+	t.Parallel()
+
+	router := testutils.NewRouter()
+	router.Get("/panic", func(_ http.ResponseWriter, _ *http.Request) {
+		panic("boom")
+	})
+
+	server := testutils.NewTestServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/panic") //nolint: noctx
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected status %d; got %d", http.StatusInternalServerError, resp.StatusCode)
+	}
+
+	if resp.Header.Get("X-Request-Id") == "" {
+		t.Error("expected request ID header to be set by the middleware stack")
+	}
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if body["errors"] == nil {
+		t.Errorf("expected error response; got %v", body)
+	}
+}
@@ -0,0 +1,123 @@
+package threads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+)
+
+var (
+	// ErrPoolClosed is returned when a job is submitted to a pool that has been shut down.
+	ErrPoolClosed = errors.New("worker pool is closed")
+	// ErrPoolFull is returned when a job is submitted to a pool whose queue is full.
+	ErrPoolFull = errors.New("worker pool queue is full")
+)
+
+// Job is a unit of background work. Its context is cancelled if the pool's Shutdown deadline elapses before
+// the job completes.
+type Job func(ctx context.Context)
+
+// Pool runs jobs in the background with at most a fixed number running at once, e.g. to send emails after a
+// request completes without blocking the response. Panics in jobs are recovered and logged.
+type Pool struct {
+	jobs   chan Job
+	wg     sync.WaitGroup
+	ctx    context.Context //nolint: containedctx
+	cancel context.CancelFunc
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewPool starts a pool of workers goroutines that run submitted jobs. Up to queueSize jobs may wait for a
+// free worker before Submit returns ErrPoolFull.
+func NewPool(workers, queueSize int) *Pool {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	pool := &Pool{
+		jobs:   make(chan Job, queueSize),
+		wg:     sync.WaitGroup{},
+		ctx:    ctx,
+		cancel: cancel,
+		mu:     sync.RWMutex{},
+		closed: false,
+	}
+
+	pool.wg.Add(workers)
+
+	for range workers {
+		go pool.work()
+	}
+
+	return pool
+}
+
+// Submit queues job to be run by the next free worker. It does not wait for the job to run.
+func (p *Pool) Submit(job Job) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	select {
+	case p.jobs <- job:
+		return nil
+	default:
+		return ErrPoolFull
+	}
+}
+
+// Shutdown stops the pool accepting jobs and waits for queued and running jobs to finish. If ctx is done
+// first, the context passed to running jobs is cancelled and ctx's error is returned.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+
+		return fmt.Errorf("worker pool shutdown: %w", ctx.Err())
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for job := range p.jobs {
+		p.run(job)
+	}
+}
+
+// run runs a single job, logging rather than propagating any panic so that the worker keeps running.
+func (p *Pool) run(job Job) {
+	defer func() {
+		if err := recover(); err != nil {
+			slog.Error("background job failed",
+				"error", fmt.Sprintf("recover panic: %v", err),
+				"stack", string(debug.Stack()),
+			)
+		}
+	}()
+
+	job(p.ctx)
+}
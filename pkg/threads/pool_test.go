@@ -0,0 +1,139 @@
+package threads_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gurch101/gowebutils/pkg/threads"
+)
+
+func TestPoolRunsJobs(t *testing.T) {
+	t.Parallel()
+
+	pool := threads.NewPool(2, 10)
+
+	var ran atomic.Int32
+
+	for range 10 {
+		if err := pool.Submit(func(context.Context) { ran.Add(1) }); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ran.Load() != 10 {
+		t.Errorf("expected 10 jobs to run, got %d", ran.Load())
+	}
+
+	if err := pool.Submit(func(context.Context) {}); !errors.Is(err, threads.ErrPoolClosed) {
+		t.Errorf("expected ErrPoolClosed after shutdown, got %v", err)
+	}
+}
+
+func TestPoolRecoversPanics(t *testing.T) {
+	t.Parallel()
+
+	pool := threads.NewPool(1, 2)
+
+	var ran atomic.Bool
+
+	_ = pool.Submit(func(context.Context) { panic("boom") })
+	_ = pool.Submit(func(context.Context) { ran.Store(true) })
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !ran.Load() {
+		t.Error("expected worker to keep running jobs after a panic")
+	}
+}
+
+func TestPoolRejectsJobsWhenFull(t *testing.T) {
+	t.Parallel()
+
+	pool := threads.NewPool(1, 1)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	_ = pool.Submit(func(context.Context) {
+		close(started)
+		<-release
+	})
+
+	<-started
+
+	if err := pool.Submit(func(context.Context) {}); err != nil {
+		t.Fatalf("expected job to be queued, got %v", err)
+	}
+
+	if err := pool.Submit(func(context.Context) {}); !errors.Is(err, threads.ErrPoolFull) {
+		t.Errorf("expected ErrPoolFull, got %v", err)
+	}
+
+	close(release)
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPoolShutdownWaitsForInFlightJobs(t *testing.T) {
+	t.Parallel()
+
+	pool := threads.NewPool(1, 1)
+	started := make(chan struct{})
+
+	var finished atomic.Bool
+
+	_ = pool.Submit(func(context.Context) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		finished.Store(true)
+	})
+
+	<-started
+
+	if err := pool.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !finished.Load() {
+		t.Error("expected Shutdown to wait for the in-flight job")
+	}
+}
+
+func TestPoolShutdownDeadline(t *testing.T) {
+	t.Parallel()
+
+	pool := threads.NewPool(1, 1)
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+
+	_ = pool.Submit(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		close(cancelled)
+	})
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("expected the running job's context to be cancelled")
+	}
+}
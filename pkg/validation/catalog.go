@@ -0,0 +1,76 @@
+package validation
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is the language used when a message is not available in the requested language.
+const DefaultLanguage = "en"
+
+// Catalog holds validation messages by language and key. Keys are the messages passed to the Validator,
+// so a key can be a plain English message such as "Invalid plan" or an identifier such as "plan.invalid".
+// A Catalog should be populated at startup; it is safe for concurrent reads once populated.
+type Catalog struct {
+	messages map[string]map[string]string
+}
+
+// NewCatalog creates an empty Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{messages: map[string]map[string]string{}}
+}
+
+// Add registers messages for a language tag such as "fr" or "de-CH", replacing any existing messages with
+// the same keys. It returns the Catalog so that calls can be chained.
+func (c *Catalog) Add(lang string, messages map[string]string) *Catalog {
+	lang = strings.ToLower(lang)
+
+	if c.messages[lang] == nil {
+		c.messages[lang] = map[string]string{}
+	}
+
+	for key, message := range messages {
+		c.messages[lang][key] = message
+	}
+
+	return c
+}
+
+// Languages returns the languages in the Catalog, with DefaultLanguage first and the rest sorted.
+func (c *Catalog) Languages() []string {
+	languages := []string{DefaultLanguage}
+
+	for lang := range c.messages {
+		if lang != DefaultLanguage {
+			languages = append(languages, lang)
+		}
+	}
+
+	sort.Strings(languages[1:])
+
+	return languages
+}
+
+// Message returns the message for key in lang. If lang has no message for key, the base language
+// (e.g. "fr" for "fr-CA") and then DefaultLanguage are tried. If none has a message, key is returned.
+func (c *Catalog) Message(lang, key string) string {
+	lang = strings.ToLower(lang)
+	base, _, _ := strings.Cut(lang, "-")
+
+	for _, candidate := range []string{lang, base, DefaultLanguage} {
+		if message, ok := c.messages[candidate][key]; ok {
+			return message
+		}
+	}
+
+	return key
+}
+
+// NewValidator creates a Validator that renders error messages in lang using the Catalog.
+func (c *Catalog) NewValidator(lang string) *Validator {
+	v := NewValidator()
+	v.catalog = c
+	v.lang = lang
+
+	return v
+}
@@ -0,0 +1,101 @@
+package validation_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+func newTestCatalog() *validation.Catalog {
+	return validation.NewCatalog().
+		Add("en", map[string]string{"plan.invalid": "Invalid plan"}).
+		Add("fr", map[string]string{
+			"plan.invalid":                  "Forfait invalide",
+			"Tenant Name is required":       "Le nom du locataire est obligatoire",
+			"must be at most %d characters": "doit contenir au plus %d caractères",
+		}).
+		Add("de", map[string]string{"plan.invalid": "Ungültiger Tarif"})
+}
+
+func TestCatalogMessage(t *testing.T) {
+	t.Parallel()
+
+	catalog := newTestCatalog().Add("fr-CA", map[string]string{"plan.invalid": "Plan invalide"})
+
+	tests := []struct {
+		name     string
+		lang     string
+		key      string
+		expected string
+	}{
+		{"default language", "en", "plan.invalid", "Invalid plan"},
+		{"override", "fr", "plan.invalid", "Forfait invalide"},
+		{"region override", "fr-CA", "plan.invalid", "Plan invalide"},
+		{"falls back to base language", "de-CH", "plan.invalid", "Ungültiger Tarif"},
+		{"case insensitive", "FR", "plan.invalid", "Forfait invalide"},
+		{"falls back to default language", "es", "plan.invalid", "Invalid plan"},
+		{"falls back to key", "fr", "tenant.missing", "tenant.missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if message := catalog.Message(tt.lang, tt.key); message != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, message)
+			}
+		})
+	}
+}
+
+func TestCatalogLanguages(t *testing.T) {
+	t.Parallel()
+
+	expected := []string{"en", "de", "fr"}
+	if languages := newTestCatalog().Languages(); !reflect.DeepEqual(languages, expected) {
+		t.Errorf("expected %v, got %v", expected, languages)
+	}
+}
+
+func TestCatalogNewValidator(t *testing.T) {
+	t.Parallel()
+
+	v := newTestCatalog().NewValidator("fr")
+	v.Required("", "tenantName", "Tenant Name is required")
+	v.In("enterprise", []string{"free", "paid"}, "plan", "plan.invalid")
+	v.MaxLength("Acme Incorporated", 4, "tenantName", "")
+	v.Email("acme.com", "contactEmail", "Contact Email is invalid")
+	validation.ValidateEach(v, "contacts", []string{"acme.com"}, func(v *validation.Validator, email string) {
+		v.Check(email == "", "email", "plan.invalid")
+	})
+
+	expected := []validation.Error{
+		{Field: "tenantName", Message: "Le nom du locataire est obligatoire"},
+		{Field: "plan", Message: "Forfait invalide"},
+		{Field: "tenantName", Message: "doit contenir au plus 4 caractères"},
+		{Field: "contactEmail", Message: "Contact Email is invalid"},
+		{Field: "contacts[0].email", Message: "Forfait invalide"},
+	}
+	if !reflect.DeepEqual(v.Errors, expected) {
+		t.Errorf("expected %v, got %v", expected, v.Errors)
+	}
+}
+
+func TestCatalogNewValidatorFormatsBoundsMessagesOnce(t *testing.T) {
+	t.Parallel()
+
+	// The formatted French message is itself a key, so looking it up again would replace it.
+	catalog := validation.NewCatalog().Add("fr", map[string]string{
+		"must be at least %d characters":      "doit contenir au moins %d caractères",
+		"doit contenir au moins 3 caractères": "traduit deux fois",
+	})
+
+	v := catalog.NewValidator("fr")
+	v.MinLength("ab", 3, "tenantName", "")
+
+	expected := []validation.Error{{Field: "tenantName", Message: "doit contenir au moins 3 caractères"}}
+	if !reflect.DeepEqual(v.Errors, expected) {
+		t.Errorf("expected %v, got %v", expected, v.Errors)
+	}
+}
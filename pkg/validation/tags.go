@@ -0,0 +1,216 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ValidateStruct adds an error to the Validator for every exported field of value, a struct or pointer to a
+// struct, that breaks a rule in its validate tag. Rules are comma-separated:
+//
+//   - required: strings must not be empty, pointers must not be nil and other values must not be zero.
+//   - email: strings must be valid email addresses, as determined by ValidateEmail.
+//   - min=N and max=N: strings must have at least or at most N characters, and numbers must be at least or
+//     at most N.
+//   - oneof=a b c: strings must be one of the space-separated values.
+//
+// For example:
+//
+//	type CreateTenantRequest struct {
+//		TenantName   string `json:"tenantName" validate:"required,max=100"`
+//		ContactEmail string `json:"contactEmail" validate:"required,email"`
+//		Plan         string `json:"plan" validate:"required,oneof=free paid"`
+//	}
+//
+// Errors are reported against the field's json name. Rules other than required are skipped for empty strings
+// and nil pointers, so optional fields are only checked when they are set. Nested structs, and slices and
+// arrays of structs, are validated too, with errors reported against the path of the nested field, e.g.
+// "billing.email" or "contacts[2].email"; the fields of embedded structs are reported as if they were declared
+// on the outer struct. A validate:"-" tag skips a field and anything nested in it. ValidateStruct panics if a
+// tag holds an unknown or malformed rule, since that is a programming error.
+func ValidateStruct(v *Validator, value any) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validation: ValidateStruct expects a struct, got %s", rv.Type()))
+	}
+
+	validateStruct(v, rv)
+}
+
+func validateStruct(v *Validator, rv reflect.Value) {
+	rt := rv.Type()
+	for i := range rt.NumField() {
+		field := rt.Field(i)
+
+		tag, ok := field.Tag.Lookup("validate")
+
+		// The exported fields of embedded structs are encoded even if the embedded type is unexported.
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+
+		name := fieldName(field)
+		if ok && field.IsExported() {
+			validateField(v, name, rv.Field(i), tag)
+		}
+
+		validateNested(v, name, field.Anonymous && field.Tag.Get("json") == "", rv.Field(i))
+	}
+}
+
+// validateNested validates value if it is a struct, or each element if it is a slice or array of structs,
+// merging the errors into v under name. The fields of an embedded struct are validated directly into v.
+func validateNested(v *Validator, name string, embedded bool, value reflect.Value) {
+	value = indirect(value)
+
+	switch value.Kind() { //nolint: exhaustive
+	case reflect.Struct:
+		if embedded {
+			validateStruct(v, value)
+
+			return
+		}
+
+		child := v.child()
+		validateStruct(child, value)
+		v.Merge(name, child)
+	case reflect.Slice, reflect.Array:
+		elemType := value.Type().Elem()
+		if elemType.Kind() == reflect.Pointer {
+			elemType = elemType.Elem()
+		}
+
+		if elemType.Kind() != reflect.Struct {
+			return
+		}
+
+		for i := range value.Len() {
+			if elem := indirect(value.Index(i)); elem.IsValid() {
+				child := v.child()
+				validateStruct(child, elem)
+				v.Merge(fmt.Sprintf("%s[%d]", name, i), child)
+			}
+		}
+	}
+}
+
+// indirect dereferences pointers, returning the zero Value if a nil pointer is reached.
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+
+		value = value.Elem()
+	}
+
+	return value
+}
+
+// fieldName returns the name a struct field is encoded as in JSON.
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+
+	return name
+}
+
+func validateField(v *Validator, name string, value reflect.Value, tag string) {
+	rules := strings.Split(tag, ",")
+
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			for _, rule := range rules {
+				if rule == "required" {
+					v.AddError(name, "is required")
+				}
+			}
+
+			return
+		}
+
+		value = value.Elem()
+	}
+
+	for _, rule := range rules {
+		rule, arg, _ := strings.Cut(rule, "=")
+
+		if rule == "required" {
+			v.Check(!value.IsZero(), name, "is required")
+
+			continue
+		}
+
+		if value.Kind() == reflect.String && value.String() == "" {
+			continue
+		}
+
+		if !checkRule(v, name, value, rule, arg) {
+			return
+		}
+	}
+}
+
+// checkRule checks a single rule other than required, returning false if it failed so that later rules on
+// the same field are skipped.
+func checkRule(v *Validator, name string, value reflect.Value, rule, arg string) bool {
+	errorCount := len(v.Errors)
+
+	switch rule {
+	case "email":
+		v.Email(stringValue(value, rule), name, "must be a valid email address")
+	case "min", "max":
+		checkBound(v, name, value, rule, arg)
+	case "oneof":
+		options := strings.Fields(arg)
+		v.In(stringValue(value, rule), options, name, "must be one of "+strings.Join(options, ", "))
+	default:
+		panic(fmt.Sprintf("validation: unknown rule %q on field %s", rule, name))
+	}
+
+	return len(v.Errors) == errorCount
+}
+
+func checkBound(v *Validator, name string, value reflect.Value, rule, arg string) {
+	bound, err := strconv.Atoi(arg)
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid %s rule %q on field %s", rule, arg, name))
+	}
+
+	switch value.Kind() { //nolint: exhaustive
+	case reflect.String:
+		if rule == "min" {
+			v.MinLength(value.String(), bound, name, "")
+		} else {
+			v.MaxLength(value.String(), bound, name, "")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rule == "min" {
+			v.checkf(value.Int() >= int64(bound), name, "must be at least %d", bound)
+		} else {
+			v.checkf(value.Int() <= int64(bound), name, "must be at most %d", bound)
+		}
+	default:
+		panic(fmt.Sprintf("validation: %s rule on field %s of unsupported kind %s", rule, name, value.Kind()))
+	}
+}
+
+func stringValue(value reflect.Value, rule string) string {
+	if value.Kind() != reflect.String {
+		panic(fmt.Sprintf("validation: %s rule on field of unsupported kind %s", rule, value.Kind()))
+	}
+
+	return value.String()
+}
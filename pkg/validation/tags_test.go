@@ -0,0 +1,153 @@
+package validation_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gurch101/gowebutils/pkg/validation"
+)
+
+type tagsTestRequest struct {
+	Name     string  `json:"name" validate:"required,min=2,max=10"`
+	Email    string  `json:"email" validate:"required,email"`
+	Plan     string  `json:"plan" validate:"required,oneof=free paid"`
+	Nickname *string `json:"nickname,omitempty" validate:"max=5"`
+	Seats    int     `json:"seats" validate:"min=1,max=50"`
+	Notes    string  `validate:"max=3"`
+}
+
+func TestValidateStruct(t *testing.T) {
+	t.Parallel()
+
+	nickname := "toolong"
+
+	tests := []struct {
+		name     string
+		value    tagsTestRequest
+		expected []validation.Error
+	}{
+		{
+			name:     "valid",
+			value:    tagsTestRequest{Name: "Acme", Email: "admin@acme.com", Plan: "free", Seats: 5},
+			expected: []validation.Error{},
+		},
+		{
+			name:  "missing required fields",
+			value: tagsTestRequest{Seats: 1},
+			expected: []validation.Error{
+				{Field: "name", Message: "is required"},
+				{Field: "email", Message: "is required"},
+				{Field: "plan", Message: "is required"},
+			},
+		},
+		{
+			name: "invalid values",
+			value: tagsTestRequest{
+				Name: "A", Email: "not-an-email", Plan: "enterprise", Nickname: &nickname, Seats: 51, Notes: "long",
+			},
+			expected: []validation.Error{
+				{Field: "name", Message: "must be at least 2 characters"},
+				{Field: "email", Message: "must be a valid email address"},
+				{Field: "plan", Message: "must be one of free, paid"},
+				{Field: "nickname", Message: "must be at most 5 characters"},
+				{Field: "seats", Message: "must be at most 50"},
+				{Field: "Notes", Message: "must be at most 3 characters"},
+			},
+		},
+		{
+			name:  "stops at the first failing rule of a field",
+			value: tagsTestRequest{Name: "A very long name", Email: "admin@acme.com", Plan: "paid", Seats: 0},
+			expected: []validation.Error{
+				{Field: "name", Message: "must be at most 10 characters"},
+				{Field: "seats", Message: "must be at least 1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := validation.NewValidator()
+			validation.ValidateStruct(v, &tt.value)
+
+			if !reflect.DeepEqual(v.Errors, tt.expected) {
+				t.Errorf("expected errors %+v, got %+v", tt.expected, v.Errors)
+			}
+		})
+	}
+}
+
+type tagsTestAddress struct {
+	Street string `json:"street" validate:"required"`
+}
+
+type tagsTestContact struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type tagsTestAudit struct {
+	CreatedBy string `json:"createdBy" validate:"required"`
+}
+
+type tagsTestNestedRequest struct {
+	tagsTestAudit
+	Name     string             `json:"name" validate:"required"`
+	Billing  tagsTestAddress    `json:"billing"`
+	Shipping *tagsTestAddress   `json:"shipping,omitempty"`
+	Contacts []tagsTestContact  `json:"contacts" validate:"required"`
+	Backups  []*tagsTestContact `json:"backups"`
+	Ignored  tagsTestAddress    `json:"ignored" validate:"-"`
+}
+
+func TestValidateStruct_Nested(t *testing.T) {
+	t.Parallel()
+
+	v := validation.NewValidator()
+	validation.ValidateStruct(v, tagsTestNestedRequest{
+		Name:     "Acme",
+		Shipping: &tagsTestAddress{Street: "1 Main St"},
+		Contacts: []tagsTestContact{{Email: "admin@acme.com"}, {Email: "not-an-email"}},
+		Backups:  []*tagsTestContact{nil, {Email: ""}},
+	})
+
+	expected := []validation.Error{
+		{Field: "createdBy", Message: "is required"},
+		{Field: "billing.street", Message: "is required"},
+		{Field: "contacts[1].email", Message: "must be a valid email address"},
+		{Field: "backups[1].email", Message: "is required"},
+	}
+	if !reflect.DeepEqual(v.Errors, expected) {
+		t.Errorf("expected errors %+v, got %+v", expected, v.Errors)
+	}
+}
+
+func TestValidateStruct_Localized(t *testing.T) {
+	t.Parallel()
+
+	catalog := validation.NewCatalog().Add("fr", map[string]string{"is required": "est obligatoire"})
+	v := catalog.NewValidator("fr")
+
+	validation.ValidateStruct(v, struct {
+		Name string `json:"name" validate:"required"`
+	}{})
+
+	expected := []validation.Error{{Field: "name", Message: "est obligatoire"}}
+	if !reflect.DeepEqual(v.Errors, expected) {
+		t.Errorf("expected errors %+v, got %+v", expected, v.Errors)
+	}
+}
+
+func TestValidateStruct_PanicsOnUnknownRule(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected ValidateStruct to panic on an unknown rule")
+		}
+	}()
+
+	validation.ValidateStruct(validation.NewValidator(), struct {
+		Name string `json:"name" validate:"uuid"`
+	}{Name: "x"})
+}
@@ -1,15 +1,46 @@
 // Package validation provides a validator for validating user input.
 package validation
 
-import "regexp"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxEmailLength is the maximum length of an email address that can be used in an SMTP path (RFC 5321).
+	maxEmailLength = 254
+	// maxEmailLocalPartLength is the maximum length of the part of an email address before the @ (RFC 5321).
+	maxEmailLocalPartLength = 64
+)
 
 // EmailRX is a regex for sanity checking the format of email addresses.
 // The regex pattern used is taken from  https://html.spec.whatwg.org/#valid-e-mail-address.
 var EmailRX = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$") //nolint:lll
 
+// ValidateEmail returns true if email is a valid email address. In addition to matching EmailRX, the
+// address must fit the RFC 5321 length limits and its local part must not start or end with a dot or
+// contain consecutive dots.
+func ValidateEmail(email string) bool {
+	if len(email) > maxEmailLength || !EmailRX.MatchString(email) {
+		return false
+	}
+
+	localPart := email[:strings.LastIndex(email, "@")]
+
+	return len(localPart) <= maxEmailLocalPartLength &&
+		!strings.HasPrefix(localPart, ".") &&
+		!strings.HasSuffix(localPart, ".") &&
+		!strings.Contains(localPart, "..")
+}
+
 // Validator is a simple struct for collecting validation errors.
 type Validator struct {
 	Errors []Error `json:"errors"`
+	// catalog and lang localize error messages. They are set by Catalog.NewValidator.
+	catalog *Catalog
+	lang    string
 }
 
 // Error is a simple struct for representing a validation error.
@@ -25,32 +56,80 @@ func (v Error) Error() string {
 
 // NewValidator creates a new Validator.
 func NewValidator() *Validator {
-	return &Validator{
+	return &Validator{ //nolint: exhaustruct
 		Errors: []Error{},
 	}
 }
 
-// Check adds an error to the Validator if the condition is false.
+// Check adds an error to the Validator if the condition is false. If the Validator was created by
+// Catalog.NewValidator, message is used as a key to look up the message in the request's language.
 func (v *Validator) Check(condition bool, field, message string) {
 	if !condition {
-		v.Errors = append(v.Errors, Error{Field: field, Message: message})
+		v.AddError(field, message)
 	}
 }
 
-// Matches returns true if a string value matches a specific regexp pattern.
+// Matches adds an error to the Validator if a string value does not match a specific regexp pattern.
 func (v *Validator) Matches(value string, rx *regexp.Regexp, field, message string) {
 	v.Check(rx.MatchString(value), field, message)
 }
 
+// Email adds an error to the Validator if a string value is not a valid email address, as determined by
+// ValidateEmail.
 func (v *Validator) Email(value string, field, message string) {
-	v.Check(EmailRX.MatchString(value), field, message)
+	v.Check(ValidateEmail(value), field, message)
 }
 
+// Required adds an error to the Validator if a string value is empty.
 func (v *Validator) Required(value, field, message string) {
 	v.Check(value != "", field, message)
 }
 
-// In returns true if a specific value is in a list of strings.
+// MinLength adds an error to the Validator if a string value is shorter than minLength characters. If
+// message is empty, a standard message such as "must be at least 3 characters" is used.
+func (v *Validator) MinLength(value string, minLength int, field, message string) {
+	if message == "" {
+		v.checkf(utf8.RuneCountInString(value) >= minLength, field, "must be at least %d characters", minLength)
+
+		return
+	}
+
+	v.Check(utf8.RuneCountInString(value) >= minLength, field, message)
+}
+
+// MaxLength adds an error to the Validator if a string value is longer than maxLength characters. If
+// message is empty, a standard message such as "must be at most 100 characters" is used.
+func (v *Validator) MaxLength(value string, maxLength int, field, message string) {
+	if message == "" {
+		v.checkf(utf8.RuneCountInString(value) <= maxLength, field, "must be at most %d characters", maxLength)
+
+		return
+	}
+
+	v.Check(utf8.RuneCountInString(value) <= maxLength, field, message)
+}
+
+// Between adds an error to the Validator if an int value is outside the inclusive range [minValue, maxValue].
+// If message is empty, a standard message such as "must be between 1 and 50" is used.
+func (v *Validator) Between(value, minValue, maxValue int, field, message string) {
+	if message == "" {
+		v.checkf(value >= minValue && value <= maxValue, field, "must be between %d and %d", minValue, maxValue)
+
+		return
+	}
+
+	v.Check(value >= minValue && value <= maxValue, field, message)
+}
+
+// checkf adds an error to the Validator if the condition is false, with the message for key formatted with args.
+// Unlike Check, the formatted message is not looked up again, since only key is in the Catalog.
+func (v *Validator) checkf(condition bool, field, key string, args ...any) {
+	if !condition {
+		v.Errors = append(v.Errors, Error{Field: field, Message: fmt.Sprintf(v.message(key), args...)})
+	}
+}
+
+// In adds an error to the Validator if a specific value is not in a list of strings.
 func (v *Validator) In(value string, list []string, key, message string) {
 	for i := range list {
 		if value == list[i] {
@@ -61,16 +140,89 @@ func (v *Validator) In(value string, list []string, key, message string) {
 	v.AddError(key, message)
 }
 
-// AddError adds an error to the Validator.
+// AddError adds an error to the Validator. If the Validator was created by Catalog.NewValidator, message
+// is used as a key to look up the message in the request's language.
 func (v *Validator) AddError(field, message string) {
 	newError := Error{
 		Field:   field,
-		Message: message,
+		Message: v.message(message),
 	}
 	v.Errors = append(v.Errors, newError)
 }
 
+// Merge adds the errors of a child Validator, prefixing each field name with the path of the child value
+// in the parent, e.g. a child error on "email" merged with prefix "billing" is reported as "billing.email".
+// A child error with an empty field name is reported on the prefix itself.
+func (v *Validator) Merge(prefix string, child *Validator) {
+	for _, err := range child.Errors {
+		field := prefix
+		if err.Field != "" {
+			field = prefix + "." + err.Field
+		}
+
+		v.Errors = append(v.Errors, Error{Field: field, Message: err.Message})
+	}
+}
+
+// ValidateEach validates every element of items with a fresh Validator and merges any errors into v with
+// the element's index in the field name, e.g. "contacts[2].email". validate may itself call ValidateEach or
+// Merge to validate deeper levels of nesting.
+func ValidateEach[T any](v *Validator, field string, items []T, validate func(v *Validator, item T)) {
+	for i, item := range items {
+		child := v.child()
+		validate(child, item)
+		v.Merge(fmt.Sprintf("%s[%d]", field, i), child)
+	}
+}
+
+// child returns an empty Validator that localizes messages the same way as v.
+func (v *Validator) child() *Validator {
+	child := NewValidator()
+	child.catalog = v.catalog
+	child.lang = v.lang
+
+	return child
+}
+
+// message returns the message for key in the Validator's language, or key itself if the Validator is
+// not localized.
+func (v *Validator) message(key string) string {
+	if v.catalog == nil {
+		return key
+	}
+
+	return v.catalog.Message(v.lang, key)
+}
+
 // HasErrors returns true if the Validator has any errors.
 func (v *Validator) HasErrors() bool {
 	return len(v.Errors) > 0
 }
+
+// Rule is a validation rule that spans multiple fields of a value, such as an end date that must be after a
+// start date. When Check returns false, an error with Message is attached to Field.
+type Rule[T any] struct {
+	Field   string
+	Message string
+	Check   func(value T) bool
+}
+
+// CheckRules runs each rule against value in order and adds an error to the Validator for every rule that
+// fails. Rules can be declared once per request type and reused by every handler that validates it:
+//
+//	var tenantRules = []validation.Rule[CreateTenantRequest]{
+//		{
+//			Field:   "billingEmail",
+//			Message: "Billing Email is required for paid plans",
+//			Check: func(req CreateTenantRequest) bool {
+//				return req.Plan != Paid || req.BillingEmail != ""
+//			},
+//		},
+//	}
+//
+//	validation.CheckRules(v, req, tenantRules...)
+func CheckRules[T any](v *Validator, value T, rules ...Rule[T]) {
+	for _, rule := range rules {
+		v.Check(rule.Check(value), rule.Field, rule.Message)
+	}
+}
@@ -1,7 +1,12 @@
 package validation_test
 
 import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gurch101/gowebutils/pkg/validation"
 )
@@ -108,3 +113,403 @@ func TestValidatorRequired(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatorMaxLength(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    string
+		expected bool
+	}{
+		{"shorter", "Acme", true},
+		{"exact", "Acme!", true},
+		{"longer", "Acme Inc", false},
+		{"multibyte characters", "Ümlåt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := validation.NewValidator()
+			v.MaxLength(tt.value, 5, "name", "must not be more than 5 characters")
+
+			if v.HasErrors() == tt.expected {
+				t.Errorf("expected valid to be %v, got errors %v", tt.expected, v.Errors)
+			}
+		})
+	}
+}
+
+func TestValidatorIn(t *testing.T) {
+	t.Parallel()
+
+	plans := []string{"free", "paid"}
+
+	tests := []struct {
+		name     string
+		value    string
+		expected bool
+	}{
+		{"first", "free", true},
+		{"last", "paid", true},
+		{"not in list", "enterprise", false},
+		{"case sensitive", "Free", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := validation.NewValidator()
+			v.In(tt.value, plans, "plan", "Invalid plan")
+
+			if v.HasErrors() == tt.expected {
+				t.Errorf("expected valid to be %v, got errors %v", tt.expected, v.Errors)
+			}
+		})
+	}
+}
+
+func TestValidatorAccumulatesErrorsInOrder(t *testing.T) {
+	t.Parallel()
+
+	v := validation.NewValidator()
+	v.Required("", "tenantName", "Tenant Name is required")
+	v.Email("admin@acme.com", "contactEmail", "Contact Email is invalid")
+	v.In("enterprise", []string{"free", "paid"}, "plan", "Invalid plan")
+	v.MaxLength("a very long name", 5, "tenantName", "Tenant Name is too long")
+	v.Matches("abc", regexp.MustCompile(`^\d+$`), "code", "Code must be numeric")
+
+	payload, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal validator: %v", err)
+	}
+
+	expected := `{"errors":[` +
+		`{"field":"tenantName","message":"Tenant Name is required"},` +
+		`{"field":"plan","message":"Invalid plan"},` +
+		`{"field":"tenantName","message":"Tenant Name is too long"},` +
+		`{"field":"code","message":"Code must be numeric"}]}`
+	if string(payload) != expected {
+		t.Errorf("expected %s, got %s", expected, payload)
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		email    string
+		expected bool
+	}{
+		{"simple", "admin@acme.com", true},
+		{"plus addressing", "admin+billing@acme.com", true},
+		{"dot in local part", "first.last@acme.com", true},
+		{"subdomain", "admin@mail.acme.co.uk", true},
+		{"single label domain", "admin@localhost", true},
+		{"empty", "", false},
+		{"missing @", "admin.acme.com", false},
+		{"missing local part", "@acme.com", false},
+		{"missing domain", "admin@", false},
+		{"multiple @", "admin@billing@acme.com", false},
+		{"whitespace", "ad min@acme.com", false},
+		{"leading dot", ".admin@acme.com", false},
+		{"trailing dot", "admin.@acme.com", false},
+		{"consecutive dots", "first..last@acme.com", false},
+		{"domain leading hyphen", "admin@-acme.com", false},
+		{"local part at limit", strings.Repeat("a", 64) + "@acme.com", true},
+		{"local part too long", strings.Repeat("a", 65) + "@acme.com", false},
+		{"address too long", "admin@" + strings.Repeat(strings.Repeat("a", 60)+".", 5) + "com", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if result := validation.ValidateEmail(tt.email); result != tt.expected {
+				t.Errorf("expected %v for %q, got %v", tt.expected, tt.email, result)
+			}
+		})
+	}
+}
+
+func TestValidatorEmail(t *testing.T) {
+	t.Parallel()
+
+	v := validation.NewValidator()
+	v.Email("admin@acme.com", "contactEmail", "Contact Email is invalid")
+	v.Email(".admin@acme.com", "contactEmail", "Contact Email is invalid")
+
+	if len(v.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %v", v.Errors)
+	}
+
+	expected := validation.Error{Field: "contactEmail", Message: "Contact Email is invalid"}
+	if v.Errors[0] != expected {
+		t.Errorf("expected %v, got %v", expected, v.Errors[0])
+	}
+}
+
+type subscription struct {
+	Plan         string
+	BillingEmail string
+	StartDate    time.Time
+	EndDate      time.Time
+}
+
+var subscriptionRules = []validation.Rule[subscription]{
+	{
+		Field:   "billingEmail",
+		Message: "Billing Email is required for paid plans",
+		Check: func(s subscription) bool {
+			return s.Plan != "paid" || s.BillingEmail != ""
+		},
+	},
+	{
+		Field:   "endDate",
+		Message: "End Date must be after Start Date",
+		Check: func(s subscription) bool {
+			return s.EndDate.After(s.StartDate)
+		},
+	},
+}
+
+func TestCheckRules(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	tests := []struct {
+		name     string
+		value    subscription
+		expected []validation.Error
+	}{
+		{
+			name:     "free plan without billing email",
+			value:    subscription{Plan: "free", StartDate: start, EndDate: end}, //nolint: exhaustruct
+			expected: []validation.Error{},
+		},
+		{
+			name:     "paid plan with billing email",
+			value:    subscription{Plan: "paid", BillingEmail: "billing@acme.com", StartDate: start, EndDate: end},
+			expected: []validation.Error{},
+		},
+		{
+			name:  "paid plan without billing email",
+			value: subscription{Plan: "paid", StartDate: start, EndDate: end}, //nolint: exhaustruct
+			expected: []validation.Error{
+				{Field: "billingEmail", Message: "Billing Email is required for paid plans"},
+			},
+		},
+		{
+			name:  "all rules fail in order",
+			value: subscription{Plan: "paid", StartDate: end, EndDate: start}, //nolint: exhaustruct
+			expected: []validation.Error{
+				{Field: "billingEmail", Message: "Billing Email is required for paid plans"},
+				{Field: "endDate", Message: "End Date must be after Start Date"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := validation.NewValidator()
+			validation.CheckRules(v, tt.value, subscriptionRules...)
+
+			if !reflect.DeepEqual(v.Errors, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, v.Errors)
+			}
+		})
+	}
+}
+
+func TestCheckRulesAfterFieldChecks(t *testing.T) {
+	t.Parallel()
+
+	v := validation.NewValidator()
+	v.Required("", "tenantName", "Tenant Name is required")
+	validation.CheckRules(v, subscription{Plan: "paid"}, subscriptionRules[0]) //nolint: exhaustruct
+
+	expected := []validation.Error{
+		{Field: "tenantName", Message: "Tenant Name is required"},
+		{Field: "billingEmail", Message: "Billing Email is required for paid plans"},
+	}
+	if !reflect.DeepEqual(v.Errors, expected) {
+		t.Errorf("expected %v, got %v", expected, v.Errors)
+	}
+}
+
+type contact struct {
+	Email  string
+	Phones []string
+}
+
+func validateContact(v *validation.Validator, c contact) {
+	v.Email(c.Email, "email", "Email is invalid")
+	validation.ValidateEach(v, "phones", c.Phones, func(v *validation.Validator, phone string) {
+		v.Matches(phone, regexp.MustCompile(`^\+?\d+$`), "", "Phone must be numeric")
+	})
+}
+
+func TestValidateEach(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		contacts []contact
+		expected []validation.Error
+	}{
+		{
+			name:     "no elements",
+			contacts: nil,
+			expected: []validation.Error{},
+		},
+		{
+			name: "valid elements",
+			contacts: []contact{
+				{Email: "admin@acme.com", Phones: []string{"+15555550100"}},
+				{Email: "billing@acme.com", Phones: nil},
+			},
+			expected: []validation.Error{},
+		},
+		{
+			name: "invalid element",
+			contacts: []contact{
+				{Email: "admin@acme.com", Phones: nil},
+				{Email: "billing@acme.com", Phones: nil},
+				{Email: "support.acme.com", Phones: nil},
+			},
+			expected: []validation.Error{
+				{Field: "contacts[2].email", Message: "Email is invalid"},
+			},
+		},
+		{
+			name: "nested invalid elements",
+			contacts: []contact{
+				{Email: "admin.acme.com", Phones: []string{"+15555550100", "call me"}},
+			},
+			expected: []validation.Error{
+				{Field: "contacts[0].email", Message: "Email is invalid"},
+				{Field: "contacts[0].phones[1]", Message: "Phone must be numeric"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := validation.NewValidator()
+			validation.ValidateEach(v, "contacts", tt.contacts, validateContact)
+
+			if !reflect.DeepEqual(v.Errors, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, v.Errors)
+			}
+		})
+	}
+}
+
+func TestValidatorMerge(t *testing.T) {
+	t.Parallel()
+
+	child := validation.NewValidator()
+	child.Required("", "email", "Email is required")
+
+	v := validation.NewValidator()
+	v.Required("", "tenantName", "Tenant Name is required")
+	v.Merge("billing", child)
+
+	expected := []validation.Error{
+		{Field: "tenantName", Message: "Tenant Name is required"},
+		{Field: "billing.email", Message: "Email is required"},
+	}
+	if !reflect.DeepEqual(v.Errors, expected) {
+		t.Errorf("expected %v, got %v", expected, v.Errors)
+	}
+}
+
+func TestValidatorMinLength(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    string
+		expected bool
+	}{
+		{"empty", "", false},
+		{"one below", "ab", false},
+		{"exact", "abc", true},
+		{"longer", "abcd", true},
+		{"multibyte characters", "ÜÅé", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := validation.NewValidator()
+			v.MinLength(tt.value, 3, "tenantName", "")
+
+			if v.HasErrors() == tt.expected {
+				t.Errorf("expected valid to be %v, got errors %v", tt.expected, v.Errors)
+			}
+		})
+	}
+}
+
+func TestValidatorBetween(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		value    int
+		expected bool
+	}{
+		{"below min", 0, false},
+		{"min", 1, true},
+		{"inside", 25, true},
+		{"max", 50, true},
+		{"above max", 51, false},
+		{"negative", -1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := validation.NewValidator()
+			v.Between(tt.value, 1, 50, "seats", "")
+
+			if v.HasErrors() == tt.expected {
+				t.Errorf("expected valid to be %v, got errors %v", tt.expected, v.Errors)
+			}
+		})
+	}
+}
+
+func TestValidatorBoundsMessages(t *testing.T) {
+	t.Parallel()
+
+	v := validation.NewValidator()
+	v.MinLength("ab", 3, "tenantName", "")
+	v.MaxLength("abcd", 3, "tenantName", "")
+	v.Between(0, 1, 50, "seats", "")
+	v.Between(51, 1, 50, "seats", "Seats must be between 1 and 50")
+
+	expected := []validation.Error{
+		{Field: "tenantName", Message: "must be at least 3 characters"},
+		{Field: "tenantName", Message: "must be at most 3 characters"},
+		{Field: "seats", Message: "must be between 1 and 50"},
+		{Field: "seats", Message: "Seats must be between 1 and 50"},
+	}
+	if !reflect.DeepEqual(v.Errors, expected) {
+		t.Errorf("expected %v, got %v", expected, v.Errors)
+	}
+}